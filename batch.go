@@ -0,0 +1,251 @@
+package wujiesdk
+
+// @Title        batch.go
+// @Description  split keys of batch query into chunks and run chunks in parallel
+// @Create       XdpCs 2026-10-19 17:15
+// @Update       XdpCs 2026-10-19 17:15
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// keys per request of batch query, CreateParams is limited to 6 keys by the server,
+// other limits are conservative defaults and can be changed by WithBatchSize
+const (
+	CreateParamsBatchLimit         = 6
+	GeneratingInfoBatchLimit       = 20
+	GetSuperSizeBatchLimit         = 20
+	CameraGeneratingInfoBatchLimit = 20
+	VideoGeneratingInfoBatchLimit  = 20
+	ImageBatchCheckBatchLimit      = 10
+)
+
+// DefaultBatchConcurrency is the number of chunks queried at the same time
+const DefaultBatchConcurrency = 4
+
+// ErrBatchKeyNotFound is the error of key missing in the response of its chunk
+var ErrBatchKeyNotFound = errors.New("key not found in response")
+
+// BatchResult is the result of one key of batch query
+type BatchResult[T any] struct {
+	Key   string
+	Value T
+	Code  WujieCode
+	Err   error
+}
+
+// BatchResults is results of batch query in the order of input keys
+type BatchResults[T any] []BatchResult[T]
+
+// Values get values of keys without error
+func (b BatchResults[T]) Values() []T {
+	values := make([]T, 0, len(b))
+	for _, r := range b {
+		if r.Err == nil {
+			values = append(values, r.Value)
+		}
+	}
+	return values
+}
+
+// Err get BatchError of keys with error, it is nil when every key succeeds
+func (b BatchResults[T]) Err() error {
+	e := &BatchError{}
+	for _, r := range b {
+		if r.Err != nil {
+			e.Keys = append(e.Keys, r.Key)
+			e.Errs = append(e.Errs, r.Err)
+		}
+	}
+	if len(e.Keys) == 0 {
+		return nil
+	}
+	return e
+}
+
+// BatchError is errors of keys of batch query
+type BatchError struct {
+	Keys []string
+	Errs []error
+}
+
+func (b *BatchError) Error() string {
+	messages := make([]string, 0, len(b.Keys))
+	for i, key := range b.Keys {
+		messages = append(messages, fmt.Sprintf("%s: %v", key, b.Errs[i]))
+	}
+	return fmt.Sprintf("batch: %d keys failed: %s", len(b.Keys), strings.Join(messages, "; "))
+}
+
+// Is reports whether any error of keys matches target
+func (b *BatchError) Is(target error) bool {
+	for _, err := range b.Errs {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// BatchOption is option of batch query
+type BatchOption func(*batchConfig)
+
+type batchConfig struct {
+	size        int
+	concurrency int
+}
+
+// WithBatchSize set keys per request, it should not exceed the limit of the server
+func WithBatchSize(size int) BatchOption {
+	return func(b *batchConfig) {
+		b.size = size
+	}
+}
+
+// WithBatchConcurrency set the number of chunks queried at the same time
+func WithBatchConcurrency(concurrency int) BatchOption {
+	return func(b *batchConfig) {
+		b.concurrency = concurrency
+	}
+}
+
+func newBatchConfig(size int, options []BatchOption) *batchConfig {
+	b := &batchConfig{size: size, concurrency: DefaultBatchConcurrency}
+	for _, option := range options {
+		option(b)
+	}
+	if b.size < 1 {
+		b.size = size
+	}
+	if b.concurrency < 1 {
+		b.concurrency = 1
+	}
+	return b
+}
+
+// chunkKeys split unique keys into chunks of size, duplicated keys are queried once
+func chunkKeys(keys []string, size int) [][]string {
+	seen := make(map[string]struct{}, len(keys))
+	var chunks [][]string
+	var chunk []string
+	for _, key := range keys {
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		chunk = append(chunk, key)
+		if len(chunk) == size {
+			chunks = append(chunks, chunk)
+			chunk = nil
+		}
+	}
+	if len(chunk) > 0 {
+		chunks = append(chunks, chunk)
+	}
+	return chunks
+}
+
+// runBatch query chunks of keys with bounded concurrency and merge results in the order of keys,
+// error of a chunk is the error of every key in it
+func runBatch[T any](ctx context.Context, keys []string, config *batchConfig,
+	query func(ctx context.Context, chunk []string) (WujieCode, []T, error), keyOf func(T) string) BatchResults[T] {
+	type chunkResult struct {
+		code   WujieCode
+		values map[string]T
+		err    error
+	}
+	chunks := chunkKeys(keys, config.size)
+	results := make([]chunkResult, len(chunks))
+	sem := make(chan struct{}, config.concurrency)
+	var wg sync.WaitGroup
+	for i, chunk := range chunks {
+		wg.Add(1)
+		go func(i int, chunk []string) {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				results[i] = chunkResult{code: ErrorWujieCode, err: ctx.Err()}
+				return
+			}
+			code, values, err := query(ctx, chunk)
+			r := chunkResult{code: code, err: err, values: make(map[string]T, len(values))}
+			for _, v := range values {
+				r.values[keyOf(v)] = v
+			}
+			results[i] = r
+		}(i, chunk)
+	}
+	wg.Wait()
+
+	byKey := make(map[string]*chunkResult, len(keys))
+	for i, chunk := range chunks {
+		for _, key := range chunk {
+			byKey[key] = &results[i]
+		}
+	}
+	merged := make(BatchResults[T], 0, len(keys))
+	for _, key := range keys {
+		r := byKey[key]
+		result := BatchResult[T]{Key: key, Code: r.code, Err: r.err}
+		if r.err == nil {
+			v, ok := r.values[key]
+			if ok {
+				result.Value = v
+			} else {
+				result.Err = ErrBatchKeyNotFound
+			}
+		}
+		merged = append(merged, result)
+	}
+	return merged
+}
+
+// CreateParamsBatch query CreateParams of any number of keys, CreateParamsBatchLimit keys per request
+func (c *Caller) CreateParamsBatch(ctx context.Context, keys []string, options ...BatchOption) BatchResults[CreateParams] {
+	return runBatch(ctx, keys, newBatchConfig(CreateParamsBatchLimit, options), c.CreateParams,
+		func(p CreateParams) string { return p.Key })
+}
+
+// GeneratingInfoBatch query GeneratingInfo of any number of keys, GeneratingInfoBatchLimit keys per request
+func (c *Caller) GeneratingInfoBatch(ctx context.Context, keys []string, options ...BatchOption) BatchResults[ImageGeneratingInfo] {
+	return runBatch(ctx, keys, newBatchConfig(GeneratingInfoBatchLimit, options), c.GeneratingInfo,
+		func(i ImageGeneratingInfo) string { return i.Key })
+}
+
+// GetSuperSizeBatch query GetSuperSize of any number of keys, GetSuperSizeBatchLimit keys per request
+func (c *Caller) GetSuperSizeBatch(ctx context.Context, keys []string, options ...BatchOption) BatchResults[SuperSizeInfo] {
+	return runBatch(ctx, keys, newBatchConfig(GetSuperSizeBatchLimit, options), c.GetSuperSize,
+		func(i SuperSizeInfo) string { return i.Key })
+}
+
+// CameraGeneratingInfoBatch query CameraGeneratingInfo of any number of keys, CameraGeneratingInfoBatchLimit keys per request
+func (c *Caller) CameraGeneratingInfoBatch(ctx context.Context, keys []string, options ...BatchOption) BatchResults[CameraGeneratingInfo] {
+	return runBatch(ctx, keys, newBatchConfig(CameraGeneratingInfoBatchLimit, options), c.CameraGeneratingInfo,
+		func(i CameraGeneratingInfo) string { return i.Key })
+}
+
+// VideoGeneratingInfoBatch query VideoGeneratingInfo of any number of keys, VideoGeneratingInfoBatchLimit keys per request
+func (c *Caller) VideoGeneratingInfoBatch(ctx context.Context, keys []string, options ...BatchOption) BatchResults[VideoGeneratingInfoDetail] {
+	query := func(ctx context.Context, chunk []string) (WujieCode, []VideoGeneratingInfoDetail, error) {
+		code, info, err := c.VideoGeneratingInfo(ctx, chunk)
+		if err != nil || info == nil {
+			return code, nil, err
+		}
+		return code, info.List, nil
+	}
+	return runBatch(ctx, keys, newBatchConfig(VideoGeneratingInfoBatchLimit, options), query,
+		func(i VideoGeneratingInfoDetail) string { return i.Key })
+}
+
+// ImageBatchCheckBatch check any number of images, ImageBatchCheckBatchLimit images per request,
+// Key of results is the image url
+func (c *Caller) ImageBatchCheckBatch(ctx context.Context, imageURLList []string, options ...BatchOption) BatchResults[ImageCheckInfo] {
+	return runBatch(ctx, imageURLList, newBatchConfig(ImageBatchCheckBatchLimit, options), c.ImageBatchCheck,
+		func(i ImageCheckInfo) string { return i.ImageUrl })
+}
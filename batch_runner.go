@@ -0,0 +1,576 @@
+package wujiesdk
+
+// @Title        batch_runner.go
+// @Description  run create requests of jsonl or yaml manifest with concurrency and budget limits
+// @Create       XdpCs 2026-10-19 23:40
+// @Update       XdpCs 2026-10-20 13:45
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ManifestFormat is the format of batch manifest
+type ManifestFormat string
+
+const (
+	JSONLManifestFormat ManifestFormat = "jsonl" // one BatchItem in json per line
+	YAMLManifestFormat  ManifestFormat = "yaml"  // a sequence of BatchItem
+)
+
+// ManifestFormatOf get format of manifest by extension of path, .yaml and .yml are YAMLManifestFormat
+func ManifestFormatOf(path string) ManifestFormat {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return YAMLManifestFormat
+	}
+	return JSONLManifestFormat
+}
+
+// BatchItem is one create request of batch manifest, Request is the request of Product, such as *CreateImageRequest,
+// ID is derived from Product and Request if empty, so that unchanged items keep their ID between runs
+type BatchItem struct {
+	ID      string
+	Product Product
+	Request interface{}
+}
+
+// manifestItem is BatchItem in manifest, request is decoded by product
+type manifestItem struct {
+	ID      string      `json:"id" yaml:"id"`
+	Product Product     `json:"product" yaml:"product"`
+	Request interface{} `json:"request" yaml:"request"`
+}
+
+// LoadBatchManifest load batch manifest from file in ManifestFormatOf(path)
+func LoadBatchManifest(path string) ([]BatchItem, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("os.Open: path: %s, error: %w", path, err)
+	}
+	defer f.Close()
+	items, err := ReadBatchManifest(f, ManifestFormatOf(path))
+	if err != nil {
+		return nil, fmt.Errorf("ReadBatchManifest: path: %s, error: %w", path, err)
+	}
+	return items, nil
+}
+
+// ReadBatchManifest read batch manifest, unknown fields of requests are rejected and duplicated IDs are errors
+func ReadBatchManifest(r io.Reader, format ManifestFormat) ([]BatchItem, error) {
+	var raws []manifestItem
+	switch format {
+	case YAMLManifestFormat:
+		if err := yaml.NewDecoder(r).Decode(&raws); err != nil && !errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("yaml.Decoder.Decode: error: %w", err)
+		}
+	case JSONLManifestFormat:
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+		for line := 1; scanner.Scan(); line++ {
+			text := bytes.TrimSpace(scanner.Bytes())
+			if len(text) == 0 || text[0] == '#' {
+				continue
+			}
+			var raw manifestItem
+			if err := json.Unmarshal(text, &raw); err != nil {
+				return nil, fmt.Errorf("json.Unmarshal: line: %d, error: %w", line, err)
+			}
+			raws = append(raws, raw)
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("scanner.Err: error: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("ReadBatchManifest: format: %q, error: unknown format", string(format))
+	}
+
+	items := make([]BatchItem, 0, len(raws))
+	ids := make(map[string]int, len(raws))
+	for i, raw := range raws {
+		item, err := raw.item()
+		if err != nil {
+			return nil, fmt.Errorf("item: %d, error: %w", i+1, err)
+		}
+		if j, ok := ids[item.ID]; ok {
+			return nil, fmt.Errorf("item: %d, id: %s, error: duplicated with item %d", i+1, item.ID, j+1)
+		}
+		ids[item.ID] = i
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// item decode request by product, yaml is turned into json first so that json tags of requests are used
+func (m *manifestItem) item() (BatchItem, error) {
+	request, err := m.Product.NewRequest()
+	if err != nil {
+		return BatchItem{}, err
+	}
+	data, err := json.Marshal(m.Request)
+	if err != nil {
+		return BatchItem{}, fmt.Errorf("json.Marshal: request: %v, error: %w", m.Request, err)
+	}
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.DisallowUnknownFields()
+	if err := d.Decode(request); err != nil {
+		return BatchItem{}, fmt.Errorf("json.Decoder.Decode: product: %s, error: %w", m.Product, err)
+	}
+	item := BatchItem{ID: m.ID, Product: m.Product, Request: request}
+	if item.ID == "" {
+		// data is compact json with sorted keys of maps, which is stable for the same request
+		sum := sha256.Sum256(append([]byte(m.Product+":"), data...))
+		item.ID = string(m.Product) + "-" + hex.EncodeToString(sum[:6])
+	}
+	return item, nil
+}
+
+// BatchItemStatus is the status of BatchItem after running
+type BatchItemStatus string
+
+const (
+	SucceededBatchItemStatus BatchItemStatus = "succeeded"
+	FailedBatchItemStatus    BatchItemStatus = "failed"
+	SkippedBatchItemStatus   BatchItemStatus = "skipped" // not submitted because of budget or cancellation
+)
+
+// BatchItemResult is one line of results manifest
+type BatchItemResult struct {
+	ID           string          `json:"id"`
+	Product      Product         `json:"product"`
+	Status       BatchItemStatus `json:"status"`
+	Keys         []string        `json:"keys,omitempty"`
+	ExpectedCost Cost            `json:"expected_cost"`
+	ActualCost   Cost            `json:"actual_cost"`
+	TraceIDs     []string        `json:"trace_ids,omitempty"`
+	Files        []string        `json:"files,omitempty"`
+	Error        string          `json:"error,omitempty"`
+	StartedAt    time.Time       `json:"started_at"`
+	FinishedAt   time.Time       `json:"finished_at"`
+}
+
+// LoadBatchResults load results manifest, the last result of an ID wins, a missing file has no results
+func LoadBatchResults(path string) ([]BatchItemResult, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("os.ReadFile: path: %s, error: %w", path, err)
+	}
+	var results []BatchItemResult
+	index := make(map[string]int)
+	for i, line := range bytes.Split(data, []byte("\n")) {
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		var r BatchItemResult
+		if err := json.Unmarshal(line, &r); err != nil {
+			return nil, fmt.Errorf("json.Unmarshal: path: %s, line: %d, error: %w", path, i+1, err)
+		}
+		if j, ok := index[r.ID]; ok {
+			results[j] = r
+			continue
+		}
+		index[r.ID] = len(results)
+		results = append(results, r)
+	}
+	return results, nil
+}
+
+// BatchProgress is the progress of BatchRunner.Run
+type BatchProgress struct {
+	Total     int
+	Done      int
+	Succeeded int
+	Failed    int
+	Skipped   int
+	Previous  int  // items succeeded in previous runs, they are counted in Done and Succeeded too
+	Spent     Cost // expected cost of items submitted in this run
+	Last      *BatchItemResult
+}
+
+// BatchRunner submits items of batch manifest and polls them until they finish,
+// results are appended to ResultsPath as items finish and compacted when Run returns
+type BatchRunner struct {
+	Caller       *Caller
+	Concurrency  int           // items running at the same time, DefaultBatchConcurrency if <= 0
+	Budget       Cost          // limit of expected cost of items submitted by one Run, zero field means no limit
+	Pricer       Pricer        // price items against Budget before submission, it is required when Budget is not zero
+	PollInterval time.Duration // DefaultJobPollInterval if <= 0
+	DownloadDir  string        // download assets of succeeded items into it if not empty
+	Downloader   *Downloader   // NewDownloader of client of Caller if nil
+	ResultsPath  string        // results manifest in jsonl, items succeeded in it are skipped
+	OnProgress   func(BatchProgress)
+}
+
+// NewBatchRunner new batch runner, it adds a HttpHook to client of caller to collect TRACE_IDs of items
+func NewBatchRunner(c *Caller) *BatchRunner {
+	addTraceIDHook(c.Client)
+	return &BatchRunner{
+		Caller:      c,
+		Concurrency: DefaultBatchConcurrency,
+		Downloader:  NewDownloader(c.Client),
+	}
+}
+
+// Run run items and get their results in the order of items, ctx cancellation skips items not submitted yet,
+// the error is about results manifest, errors of items are in their results
+func (b *BatchRunner) Run(ctx context.Context, items []BatchItem) ([]BatchItemResult, error) {
+	if !b.Budget.IsZero() && b.Pricer == nil {
+		return nil, fmt.Errorf("BatchRunner.Run: budget: {%v}, error: Pricer is required with budget", b.Budget)
+	}
+	previous, err := b.previous()
+	if err != nil {
+		return nil, err
+	}
+	var out *os.File
+	if b.ResultsPath != "" {
+		if err := os.MkdirAll(filepath.Dir(b.ResultsPath), 0o755); err != nil {
+			return nil, fmt.Errorf("os.MkdirAll: path: %s, error: %w", b.ResultsPath, err)
+		}
+		out, err = os.OpenFile(b.ResultsPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return nil, fmt.Errorf("os.OpenFile: path: %s, error: %w", b.ResultsPath, err)
+		}
+	}
+
+	run := &batchRun{runner: b, out: out, downloader: b.Downloader, progress: BatchProgress{Total: len(items)}}
+	if run.downloader == nil && b.DownloadDir != "" {
+		run.downloader = NewDownloader(b.Caller.Client)
+	}
+	results := make([]BatchItemResult, len(items))
+	concurrency := b.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultBatchConcurrency
+	}
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, item := range items {
+		prev, ok := previous[item.ID]
+		if ok && prev.Status == SucceededBatchItemStatus {
+			results[i] = prev
+			run.finish(&results[i], true)
+			continue
+		}
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			// keys of the previous run are kept for the next run to poll
+			results[i] = BatchItemResult{ID: item.ID, Product: item.Product, Status: SkippedBatchItemStatus,
+				Keys: prev.Keys, ExpectedCost: prev.ExpectedCost, Error: ctx.Err().Error()}
+			run.finish(&results[i], false)
+			continue
+		}
+		wg.Add(1)
+		go func(i int, item BatchItem, prev BatchItemResult) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			results[i] = run.item(ctx, item, prev)
+			run.finish(&results[i], false)
+		}(i, item, prev)
+	}
+	wg.Wait()
+
+	if out == nil {
+		return results, nil
+	}
+	if err := out.Close(); err != nil {
+		return results, fmt.Errorf("f.Close: path: %s, error: %w", b.ResultsPath, err)
+	}
+	if err := run.writeErr; err != nil {
+		return results, err
+	}
+	if err := b.compact(results); err != nil {
+		return results, err
+	}
+	return results, nil
+}
+
+func (b *BatchRunner) previous() (map[string]BatchItemResult, error) {
+	previous := make(map[string]BatchItemResult)
+	if b.ResultsPath == "" {
+		return previous, nil
+	}
+	results, err := LoadBatchResults(b.ResultsPath)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range results {
+		previous[r.ID] = r
+	}
+	return previous, nil
+}
+
+// compact rewrite results manifest with one line per item, items of this run first in their order
+func (b *BatchRunner) compact(results []BatchItemResult) error {
+	all, err := LoadBatchResults(b.ResultsPath)
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	seen := make(map[string]bool, len(results))
+	for i := range results {
+		seen[results[i].ID] = true
+		if err := enc.Encode(&results[i]); err != nil {
+			return fmt.Errorf("json.Encoder.Encode: error: %w", err)
+		}
+	}
+	for i := range all {
+		if !seen[all[i].ID] {
+			if err := enc.Encode(&all[i]); err != nil {
+				return fmt.Errorf("json.Encoder.Encode: error: %w", err)
+			}
+		}
+	}
+	tmp := b.ResultsPath + ".tmp"
+	if err := os.WriteFile(tmp, buf.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("os.WriteFile: path: %s, error: %w", tmp, err)
+	}
+	if err := os.Rename(tmp, b.ResultsPath); err != nil {
+		return fmt.Errorf("os.Rename: path: %s, error: %w", b.ResultsPath, err)
+	}
+	return nil
+}
+
+// batchRun is the state of one BatchRunner.Run
+type batchRun struct {
+	runner     *BatchRunner
+	out        *os.File
+	downloader *Downloader
+
+	mu       sync.Mutex
+	progress BatchProgress
+	writeErr error
+}
+
+// item run item, keys of prev are polled instead of submitting item again, so that jobs interrupted by
+// cancellation or errors of polling are not charged twice, item is submitted again only if they failed
+func (r *batchRun) item(ctx context.Context, item BatchItem, prev BatchItemResult) (result BatchItemResult) {
+	result = BatchItemResult{ID: item.ID, Product: item.Product, StartedAt: time.Now()}
+	traces := &traceIDs{}
+	ctx = context.WithValue(ctx, traceIDsKey{}, traces)
+	defer func() {
+		result.TraceIDs = traces.get()
+		result.FinishedAt = time.Now()
+	}()
+	fail := func(status BatchItemStatus, err error) BatchItemResult {
+		result.Status, result.Error = status, err.Error()
+		return result
+	}
+
+	var state *JobState
+	if len(prev.Keys) > 0 {
+		result.Keys, result.ExpectedCost = prev.Keys, prev.ExpectedCost
+		traces.poll()
+		polled, err := r.runner.Caller.WaitJob(ctx, item.Product, prev.Keys, r.runner.PollInterval)
+		if err != nil {
+			return fail(FailedBatchItemStatus, err)
+		}
+		if polled.Err == nil {
+			state = polled
+		} else {
+			result.Keys, result.ExpectedCost = nil, Cost{}
+			traces.submit()
+		}
+	}
+	if state == nil {
+		estimate, err := r.reserve(ctx, item)
+		if err != nil {
+			return fail(SkippedBatchItemStatus, err)
+		}
+		job, err := r.runner.Caller.SubmitJob(ctx, item.Request)
+		if err != nil {
+			r.settle(estimate, Cost{})
+			return fail(FailedBatchItemStatus, err)
+		}
+		result.Keys, result.ExpectedCost = job.Keys, job.ExpectedCost
+		if result.ExpectedCost.IsZero() {
+			result.ExpectedCost = estimate
+		}
+		r.settle(estimate, result.ExpectedCost)
+		if len(job.Keys) == 0 {
+			return fail(FailedBatchItemStatus, fmt.Errorf("product: %s, error: no keys created", item.Product))
+		}
+
+		traces.poll()
+		if state, err = r.runner.Caller.WaitJob(ctx, item.Product, job.Keys, r.runner.PollInterval); err != nil {
+			return fail(FailedBatchItemStatus, err)
+		}
+	}
+	result.ActualCost = state.ActualCost(item.Product, result.ExpectedCost)
+	if state.Err != nil {
+		return fail(FailedBatchItemStatus, state.Err)
+	}
+	if r.runner.DownloadDir != "" {
+		for _, d := range r.downloader.DownloadAll(ctx, AssetsOf(state.Result), r.runner.DownloadDir) {
+			if d.Err != nil {
+				return fail(FailedBatchItemStatus, fmt.Errorf("download: url: %s, error: %w", d.Asset.Url, d.Err))
+			}
+			result.Files = append(result.Files, d.Path)
+		}
+	}
+	result.Status = SucceededBatchItemStatus
+	return result
+}
+
+// reserve price item and reserve its cost in budget, it fails when budget is exceeded
+func (r *batchRun) reserve(ctx context.Context, item BatchItem) (Cost, error) {
+	budget := r.runner.Budget
+	if budget.IsZero() {
+		return Cost{}, nil
+	}
+	estimate, err := r.runner.Pricer.Price(ctx, item.Product.Call(item.Request))
+	if err != nil {
+		return Cost{}, fmt.Errorf("Pricer.Price: product: %s, error: %w", item.Product, err)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	spent := r.progress.Spent
+	if exceeded(spent.Add(estimate), budget) || reached(spent, budget) {
+		return Cost{}, &BudgetExceededError{Call: item.Product.Call(item.Request).Name, Period: CallBudgetPeriod,
+			Cost: estimate, Spent: spent, Limit: budget}
+	}
+	r.progress.Spent = spent.Add(estimate)
+	return estimate, nil
+}
+
+// settle replace reserved estimate with expected cost of the submitted item
+func (r *batchRun) settle(estimate, expected Cost) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.progress.Spent = r.progress.Spent.Sub(estimate).Add(expected)
+}
+
+// reached report whether spent has used up a limit, zero field of limit means no limit
+func reached(spent, limit Cost) bool {
+	return (limit.Points > 0 && spent.Points >= limit.Points) ||
+		(limit.ProDuration > 0 && spent.ProDuration >= limit.ProDuration)
+}
+
+// finish count result in progress and append it to results manifest
+func (r *batchRun) finish(result *BatchItemResult, previous bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	p := &r.progress
+	p.Done++
+	switch result.Status {
+	case SucceededBatchItemStatus:
+		p.Succeeded++
+	case FailedBatchItemStatus:
+		p.Failed++
+	case SkippedBatchItemStatus:
+		p.Skipped++
+	}
+	if previous {
+		p.Previous++
+	} else if r.out != nil && r.writeErr == nil {
+		data, err := json.Marshal(result)
+		if err == nil {
+			_, err = r.out.Write(append(data, '\n'))
+		}
+		if err != nil {
+			r.writeErr = fmt.Errorf("results manifest: path: %s, error: %w", r.runner.ResultsPath, err)
+		}
+	}
+	if r.runner.OnProgress != nil {
+		progress := *p
+		progress.Last = result
+		r.runner.OnProgress(progress)
+	}
+}
+
+// traceIDsKey is the context key of traceIDs
+type traceIDsKey struct{}
+
+// traceIDs collects TRACE_IDs of responses of requests made with its context,
+// only the last one is kept once polling starts so that long jobs do not grow results manifest
+type traceIDs struct {
+	mu      sync.Mutex
+	ids     []string
+	polling bool
+	polled  string
+}
+
+func (t *traceIDs) add(id string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.polling {
+		t.polled = id
+		return
+	}
+	t.ids = append(t.ids, id)
+}
+
+func (t *traceIDs) poll() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.polling = true
+}
+
+// submit stop polling before submitting again, the last TRACE_ID of polling is kept
+func (t *traceIDs) submit() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.polled != "" {
+		t.ids = append(t.ids, t.polled)
+	}
+	t.polling, t.polled = false, ""
+}
+
+func (t *traceIDs) get() []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	ids := append([]string(nil), t.ids...)
+	if t.polled != "" {
+		ids = append(ids, t.polled)
+	}
+	return ids
+}
+
+// traceIDHook is a HttpHook adding TRACE_ID of response to traceIDs in context of request
+type traceIDHook struct{}
+
+// BeforeRequest do nothing
+func (traceIDHook) BeforeRequest(_ *http.Request) error {
+	return nil
+}
+
+// AfterRequest add TRACE_ID of response to traceIDs in context
+func (traceIDHook) AfterRequest(resp *http.Response, _ error) {
+	if resp == nil || resp.Request == nil {
+		return
+	}
+	traces, ok := resp.Request.Context().Value(traceIDsKey{}).(*traceIDs)
+	if id := getTraceID(resp); ok && id != "" {
+		traces.add(id)
+	}
+}
+
+// addTraceIDHook add traceIDHook to client once
+func addTraceIDHook(c *Client) {
+	for _, hook := range c.HttpHooks {
+		if _, ok := hook.(traceIDHook); ok {
+			return
+		}
+	}
+	c.AddHttpHooks(traceIDHook{})
+}
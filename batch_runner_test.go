@@ -0,0 +1,137 @@
+package wujiesdk_test
+
+// @Title        batch_runner_test.go
+// @Description  test batch runner against fake server
+// @Create       XdpCs 2026-10-20 11:50
+// @Update       XdpCs 2026-10-20 13:45
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/XdpCs/wujiesdk"
+	"github.com/XdpCs/wujiesdk/wujietest"
+)
+
+const batchManifest = `{"id": "cat", "product": "image", "request": {"model": 1, "prompt": "a cat", "num": 1}}
+{"id": "dog", "product": "image", "request": {"model": 1, "prompt": "a dog", "num": 1}}
+`
+
+func TestBatchRunnerResume(t *testing.T) {
+	var failing int32 = 1
+	s := wujietest.NewServer(wujietest.WithTiming(0, 0), wujietest.WithOutcome(
+		func(_ wujietest.JobKind, _ string, request interface{}) *wujiesdk.FailMessage {
+			if r, ok := request.(*wujiesdk.CreateImageRequest); ok && r.Prompt == "a dog" && atomic.LoadInt32(&failing) == 1 {
+				return &wujiesdk.FailMessage{FailCode: 1, FailMessage: "failed"}
+			}
+			return nil
+		}))
+	defer s.Close()
+	items, err := wujiesdk.ReadBatchManifest(strings.NewReader(batchManifest), wujiesdk.JSONLManifestFormat)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "jobs.results.jsonl")
+	run := func() ([]wujiesdk.BatchItemResult, wujiesdk.BatchProgress) {
+		b := wujiesdk.NewBatchRunner(s.Caller())
+		b.PollInterval = 10 * time.Millisecond
+		b.ResultsPath = path
+		var last wujiesdk.BatchProgress
+		b.OnProgress = func(p wujiesdk.BatchProgress) { last = p }
+		results, err := b.Run(context.Background(), items)
+		if err != nil {
+			t.Fatalf("Run error: %v", err)
+		}
+		return results, last
+	}
+
+	results, _ := run()
+	if results[0].Status != wujiesdk.SucceededBatchItemStatus || results[1].Status != wujiesdk.FailedBatchItemStatus {
+		t.Fatalf("first run statuses: %s, %s, want succeeded, failed", results[0].Status, results[1].Status)
+	}
+	if len(results[0].Keys) == 0 || len(results[0].TraceIDs) == 0 {
+		t.Errorf("first run result of cat: %+v, want keys and trace ids", results[0])
+	}
+
+	atomic.StoreInt32(&failing, 0)
+	created := s.Calls(wujiesdk.CreateImageWujieRouter)
+	results, progress := run()
+	if results[0].Status != wujiesdk.SucceededBatchItemStatus || results[1].Status != wujiesdk.SucceededBatchItemStatus {
+		t.Fatalf("second run statuses: %s, %s, want succeeded", results[0].Status, results[1].Status)
+	}
+	if n := s.Calls(wujiesdk.CreateImageWujieRouter) - created; n != 1 {
+		t.Errorf("second run created %d items, want only the failed one", n)
+	}
+	if progress.Previous != 1 || progress.Succeeded != 2 {
+		t.Errorf("second run progress: %+v, want 1 previous and 2 succeeded", progress)
+	}
+
+	saved, err := wujiesdk.LoadBatchResults(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(saved) != 2 {
+		t.Errorf("results manifest has %d lines after compaction, want 2", len(saved))
+	}
+}
+
+func TestBatchRunnerBudgetRequiresPricer(t *testing.T) {
+	s := wujietest.NewServer(wujietest.WithTiming(0, 0))
+	defer s.Close()
+	b := wujiesdk.NewBatchRunner(s.Caller())
+	b.Budget = wujiesdk.Cost{Points: 10}
+	if _, err := b.Run(context.Background(), nil); err == nil {
+		t.Error("Run with budget and without pricer succeeded")
+	}
+}
+
+func TestBatchRunnerResumeKeys(t *testing.T) {
+	clock := wujietest.NewManualClock(time.Now())
+	s := wujietest.NewServer(wujietest.WithTiming(0, time.Minute), wujietest.WithClock(clock.Now))
+	defer s.Close()
+	items, err := wujiesdk.ReadBatchManifest(strings.NewReader(batchManifest), wujiesdk.JSONLManifestFormat)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "jobs.results.jsonl")
+	dir := t.TempDir()
+	run := func(timeout time.Duration) []wujiesdk.BatchItemResult {
+		// a struct literal runner downloads with a default downloader
+		b := &wujiesdk.BatchRunner{Caller: s.Caller(), PollInterval: 10 * time.Millisecond, DownloadDir: dir, ResultsPath: path}
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		results, err := b.Run(ctx, items)
+		if err != nil {
+			t.Fatalf("Run error: %v", err)
+		}
+		return results
+	}
+
+	// the jobs are still generating when the first run is cancelled
+	first := run(200 * time.Millisecond)
+	for _, r := range first {
+		if r.Status != wujiesdk.FailedBatchItemStatus || len(r.Keys) == 0 {
+			t.Fatalf("first run result: %+v, want failed with keys", r)
+		}
+	}
+
+	clock.Advance(time.Minute)
+	created := s.Calls(wujiesdk.CreateImageWujieRouter)
+	second := run(10 * time.Second)
+	if n := s.Calls(wujiesdk.CreateImageWujieRouter) - created; n != 0 {
+		t.Errorf("second run created %d items, want keys of the first run polled", n)
+	}
+	for i, r := range second {
+		if r.Status != wujiesdk.SucceededBatchItemStatus || len(r.Keys) == 0 || r.Keys[0] != first[i].Keys[0] {
+			t.Errorf("second run result: %+v, want succeeded with keys %v", r, first[i].Keys)
+		}
+	}
+	if files, _ := os.ReadDir(dir); len(files) == 0 {
+		t.Error("no assets downloaded")
+	}
+}
@@ -0,0 +1,243 @@
+package wujiesdk
+
+// @Title        budget.go
+// @Description  guard create calls with budgets of points and pro duration
+// @Create       XdpCs 2026-10-19 13:45
+// @Update       XdpCs 2026-10-20 09:40
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// BudgetPeriod is the period of budget
+type BudgetPeriod string
+
+const (
+	CallBudgetPeriod       BudgetPeriod = "call"
+	HourBudgetPeriod       BudgetPeriod = "hour"
+	DayBudgetPeriod        BudgetPeriod = "day"
+	BalanceBudgetPeriod    BudgetPeriod = "balance"
+	KillSwitchBudgetPeriod BudgetPeriod = "kill_switch"
+)
+
+// Budget limits Cost of create calls, zero field means no limit
+type Budget struct {
+	PerCall Cost
+	PerHour Cost
+	PerDay  Cost
+	// CheckBalance reject the call when account's balance is less than its Cost
+	CheckBalance bool
+}
+
+// BudgetExceededError is returned when create call would exceed the budget
+type BudgetExceededError struct {
+	Call   string
+	Period BudgetPeriod
+	Cost   Cost // cost of the call
+	Spent  Cost // spent in the period before the call
+	Limit  Cost // limit of the period
+}
+
+func (b *BudgetExceededError) Error() string {
+	if b.Period == KillSwitchBudgetPeriod {
+		return fmt.Sprintf("budget: call: %s, error: spending is killed", b.Call)
+	}
+	return fmt.Sprintf("budget: call: %s, period: %s, cost: {%v}, spent: {%v}, limit: {%v}, error: budget exceeded",
+		b.Call, b.Period, b.Cost, b.Spent, b.Limit)
+}
+
+// KillSwitch stops all spending of guards using it, it is safe for concurrent use
+type KillSwitch struct {
+	killed int32
+}
+
+// Kill stop spending
+func (k *KillSwitch) Kill() {
+	atomic.StoreInt32(&k.killed, 1)
+}
+
+// Resume allow spending again
+func (k *KillSwitch) Resume() {
+	atomic.StoreInt32(&k.killed, 0)
+}
+
+// Killed report whether spending is killed
+func (k *KillSwitch) Killed() bool {
+	return atomic.LoadInt32(&k.killed) == 1
+}
+
+// GlobalKillSwitch is checked by every BudgetGuard
+var GlobalKillSwitch = &KillSwitch{}
+
+type budgetSpend struct {
+	at   time.Time
+	call *Call
+	cost Cost
+}
+
+// BudgetGuard is a CallerHook which prices create calls before submission
+// and rejects them with *BudgetExceededError when the budget would be exceeded,
+// the cost is reserved before submission and released when the call fails or a later hook stops it,
+// create calls which Pricer can not price are rejected with its error, such as ErrUnpricedCall
+type BudgetGuard struct {
+	Caller     *Caller
+	Budget     Budget
+	Pricer     Pricer
+	KillSwitch KillSwitch
+	Now        func() time.Time
+
+	mu     sync.Mutex
+	spends []budgetSpend
+}
+
+// NewBudgetGuard new budget guard, calls are priced by CallerPricer with table
+func NewBudgetGuard(c *Caller, budget Budget, table PriceTable) *BudgetGuard {
+	return &BudgetGuard{
+		Caller: c,
+		Budget: budget,
+		Pricer: NewCallerPricer(c, table),
+		Now:    time.Now,
+	}
+}
+
+// Kill stop spending of this guard
+func (g *BudgetGuard) Kill() {
+	g.KillSwitch.Kill()
+}
+
+// Resume allow spending of this guard again
+func (g *BudgetGuard) Resume() {
+	g.KillSwitch.Resume()
+}
+
+// BeforeCall price the create call and reserve its cost
+func (g *BudgetGuard) BeforeCall(ctx context.Context, call *Call) error {
+	if !isCreateCall(call) {
+		return nil
+	}
+	if GlobalKillSwitch.Killed() || g.KillSwitch.Killed() {
+		return &BudgetExceededError{Call: call.Name, Period: KillSwitchBudgetPeriod}
+	}
+	cost, err := g.Pricer.Price(ctx, call)
+	if err != nil {
+		return fmt.Errorf("g.Pricer.Price: %w", err)
+	}
+	if exceeded(cost, g.Budget.PerCall) {
+		return &BudgetExceededError{Call: call.Name, Period: CallBudgetPeriod, Cost: cost, Limit: g.Budget.PerCall}
+	}
+	if g.Budget.CheckBalance {
+		if err := g.checkBalance(ctx, call, cost); err != nil {
+			return err
+		}
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	now := g.now()
+	g.trim(now)
+	if spent := g.spentSince(now.Add(-time.Hour)); exceeded(spent.Add(cost), g.Budget.PerHour) {
+		return &BudgetExceededError{Call: call.Name, Period: HourBudgetPeriod, Cost: cost, Spent: spent, Limit: g.Budget.PerHour}
+	}
+	if spent := g.spentSince(now.Add(-24 * time.Hour)); exceeded(spent.Add(cost), g.Budget.PerDay) {
+		return &BudgetExceededError{Call: call.Name, Period: DayBudgetPeriod, Cost: cost, Spent: spent, Limit: g.Budget.PerDay}
+	}
+	if !cost.IsZero() {
+		g.spends = append(g.spends, budgetSpend{at: now, call: call, cost: cost})
+	}
+	return nil
+}
+
+// AfterCall release the reserved cost when the call fails or is stopped by a later hook
+func (g *BudgetGuard) AfterCall(_ context.Context, call *Call, code WujieCode, _ interface{}, err error) {
+	if err == nil && code == OKWujieCode {
+		return
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for i, s := range g.spends {
+		if s.call == call {
+			g.spends = append(g.spends[:i], g.spends[i+1:]...)
+			return
+		}
+	}
+}
+
+// Spent get the cost spent in the last hour and the last day
+func (g *BudgetGuard) Spent() (hour, day Cost) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	now := g.now()
+	return g.spentSince(now.Add(-time.Hour)), g.spentSince(now.Add(-24 * time.Hour))
+}
+
+func (g *BudgetGuard) checkBalance(ctx context.Context, call *Call, cost Cost) error {
+	if cost.Points > 0 {
+		_, balance, err := g.Caller.AvailableIntegralBalance(ctx)
+		if err != nil {
+			return fmt.Errorf("g.Caller.AvailableIntegralBalance: %w", err)
+		}
+		if cost.Points > balance {
+			return &BudgetExceededError{Call: call.Name, Period: BalanceBudgetPeriod, Cost: cost, Limit: Cost{Points: balance}}
+		}
+	}
+	if cost.ProDuration > 0 {
+		_, balance, err := g.Caller.AccountBalancePro(ctx)
+		if err != nil {
+			return fmt.Errorf("g.Caller.AccountBalancePro: %w", err)
+		}
+		if cost.ProDuration > balance {
+			return &BudgetExceededError{Call: call.Name, Period: BalanceBudgetPeriod, Cost: cost, Limit: Cost{ProDuration: balance}}
+		}
+	}
+	return nil
+}
+
+func (g *BudgetGuard) now() time.Time {
+	if g.Now == nil {
+		return time.Now()
+	}
+	return g.Now()
+}
+
+// trim drop spends older than one day, g.mu must be held
+func (g *BudgetGuard) trim(now time.Time) {
+	since := now.Add(-24 * time.Hour)
+	i := 0
+	for i < len(g.spends) && !g.spends[i].at.After(since) {
+		i++
+	}
+	g.spends = g.spends[i:]
+}
+
+// spentSince get the cost spent after since, g.mu must be held
+func (g *BudgetGuard) spentSince(since time.Time) Cost {
+	var spent Cost
+	for _, s := range g.spends {
+		if s.at.After(since) {
+			spent = spent.Add(s.cost)
+		}
+	}
+	return spent
+}
+
+// exceeded report whether cost is over limit, zero field of limit means no limit
+func exceeded(cost, limit Cost) bool {
+	return (limit.Points > 0 && cost.Points > limit.Points) ||
+		(limit.ProDuration > 0 && cost.ProDuration > limit.ProDuration)
+}
+
+// isCreateCall report whether the call submits a job which may cost points or duration
+func isCreateCall(call *Call) bool {
+	switch call.Request.(type) {
+	case *CreateImageRequest, *CreateImageProRequest, *PostSuperSizeRequest, *YouthifyRequest,
+		*CreateAvatarRequest, *CreateAvatarArtworkRequest, *CreateSpellAnalysisRequest,
+		*PromptOptimizeSubmitRequest, *CreateVideoRequest, *CreateCameraRequest,
+		*CreateSegmentationRequest, *CreateInfiniteZoomRequest, *CreateVectorStudioRequest,
+		*CreateSVDRequest, *CreateMidjourneyRequest, *CreateFluxRequest:
+		return true
+	}
+	return false
+}
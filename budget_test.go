@@ -0,0 +1,63 @@
+package wujiesdk_test
+
+// @Title        budget_test.go
+// @Description  test budget guard against fake server
+// @Create       XdpCs 2026-10-20 11:50
+// @Update       XdpCs 2026-10-20 11:50
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/XdpCs/wujiesdk"
+	"github.com/XdpCs/wujiesdk/wujietest"
+)
+
+var errRejected = errors.New("rejected")
+
+// rejectHook rejects calls of name
+type rejectHook struct {
+	name string
+}
+
+func (r *rejectHook) BeforeCall(_ context.Context, call *wujiesdk.Call) error {
+	if call.Name == r.name {
+		return errRejected
+	}
+	return nil
+}
+
+func (r *rejectHook) AfterCall(_ context.Context, _ *wujiesdk.Call, _ wujiesdk.WujieCode, _ interface{}, _ error) {
+}
+
+func TestBudgetGuard(t *testing.T) {
+	s := wujietest.NewServer(wujietest.WithTiming(0, 0))
+	defer s.Close()
+	c := s.Caller()
+	guard := wujiesdk.NewBudgetGuard(c, wujiesdk.Budget{PerDay: wujiesdk.Cost{Points: 1000}}, wujiesdk.PriceTable{})
+	c.AddCallerHooks(guard, &rejectHook{name: "CreateImage"})
+	ctx := context.Background()
+
+	if _, _, err := c.CreateImage(ctx, &wujiesdk.CreateImageRequest{Model: 1, Prompt: "a cat", Num: 1}); !errors.Is(err, errRejected) {
+		t.Fatalf("CreateImage error: %v, want %v", err, errRejected)
+	}
+	if hour, day := guard.Spent(); !hour.IsZero() || !day.IsZero() {
+		t.Errorf("spent hour: {%v}, day: {%v} after rejection, want zero", hour, day)
+	}
+
+	if _, _, err := c.CreateMidjourney(ctx, &wujiesdk.CreateMidjourneyRequest{Model: 1, Prompt: "a cat", Num: 1}); !errors.Is(err, wujiesdk.ErrUnpricedCall) {
+		t.Errorf("CreateMidjourney error: %v, want %v", err, wujiesdk.ErrUnpricedCall)
+	}
+	if n := s.Calls(wujiesdk.CreateMidjourneyWujieRouter); n != 0 {
+		t.Errorf("server got %d calls of unpriced request", n)
+	}
+
+	guard.Pricer = wujiesdk.NewCallerPricer(c, wujiesdk.PriceTable{Calls: map[string]wujiesdk.Cost{"CreateMidjourney": {Points: 30}}})
+	if _, _, err := c.CreateMidjourney(ctx, &wujiesdk.CreateMidjourneyRequest{Model: 1, Prompt: "a cat", Num: 1}); err != nil {
+		t.Fatalf("CreateMidjourney error: %v", err)
+	}
+	if _, day := guard.Spent(); day.Points != 30 {
+		t.Errorf("spent day: {%v}, want 30 points", day)
+	}
+}
@@ -3,7 +3,7 @@ package wujiesdk
 // @Title        caller.go
 // @Description  handle wujie sdk's response
 // @Create       XdpCs 2023-09-10 20:47
-// @Update       XdpCs 2024-01-29 14:18
+// @Update       XdpCs 2026-10-20 13:15
 
 import (
 	"context"
@@ -14,7 +14,8 @@ import (
 
 // Caller is the caller for wujie sdk
 type Caller struct {
-	Client *Client
+	Client      *Client
+	CallerHooks CallerHooks // hook before and after call
 }
 
 // NewCaller create a new caller
@@ -23,7 +24,13 @@ func NewCaller(c *Client) *Caller {
 }
 
 // AvailableIntegralBalance get available integral balance
-func (c *Caller) AvailableIntegralBalance(ctx context.Context) (WujieCode, int, error) {
+func (c *Caller) AvailableIntegralBalance(ctx context.Context) (code WujieCode, result int, err error) {
+	call := &Call{Name: "AvailableIntegralBalance", Router: AvailableIntegralBalanceWujieRouter}
+	if err = c.beforeCall(ctx, call); err != nil {
+		return ErrorWujieCode, result, fmt.Errorf("c.beforeCall: %w", err)
+	}
+	defer func() { c.afterCall(ctx, call, code, result, err) }()
+
 	resp, err := c.Client.AvailableIntegralBalance(ctx)
 	if err != nil {
 		return ErrorWujieCode, 0, fmt.Errorf("c.Client.AvailableIntegralBalance: %w", err)
@@ -34,8 +41,8 @@ func (c *Caller) AvailableIntegralBalance(ctx context.Context) (WujieCode, int,
 	if err := json.NewDecoder(resp.Body).Decode(&bResp); err != nil {
 		return ErrorWujieCode, 0, fmt.Errorf("json.NewDecoder: %w", err)
 	}
-	code := WujieCode(bResp.Code)
-	if err := code.Err(); err != nil {
+	code = WujieCode(bResp.Code)
+	if err := c.codeErr(ctx, code, bResp.Message); err != nil {
 		return code, 0, fmt.Errorf("TRACE_ID: %s, WujieCode: %w, Message: %s", getTraceID(resp), err, bResp.Message)
 	}
 
@@ -43,7 +50,13 @@ func (c *Caller) AvailableIntegralBalance(ctx context.Context) (WujieCode, int,
 }
 
 // ExchangePoint exchange points with people
-func (c *Caller) ExchangePoint(ctx context.Context, eReq *ExchangePointRequest) (WujieCode, bool, error) {
+func (c *Caller) ExchangePoint(ctx context.Context, eReq *ExchangePointRequest) (code WujieCode, result bool, err error) {
+	call := &Call{Name: "ExchangePoint", Router: ExchangePointWujieRouter, Request: eReq}
+	if err = c.beforeCall(ctx, call); err != nil {
+		return ErrorWujieCode, result, fmt.Errorf("c.beforeCall: %w", err)
+	}
+	defer func() { c.afterCall(ctx, call, code, result, err) }()
+
 	resp, err := c.Client.ExchangePoint(ctx, eReq)
 	if err != nil {
 		return ErrorWujieCode, false, fmt.Errorf("c.Client.ExchangePoint: %w", err)
@@ -54,8 +67,8 @@ func (c *Caller) ExchangePoint(ctx context.Context, eReq *ExchangePointRequest)
 	if err := json.NewDecoder(resp.Body).Decode(&eResp); err != nil {
 		return ErrorWujieCode, false, fmt.Errorf("json.NewDecoder: %w", err)
 	}
-	code := WujieCode(eResp.Code)
-	if err := code.Err(); err != nil {
+	code = WujieCode(eResp.Code)
+	if err := c.codeErr(ctx, code, eResp.Message); err != nil {
 		return code, false, fmt.Errorf("TRACE_ID: %s, WujieCode: %w, Message: %s, ExchangePointRequest: %s",
 			getTraceID(resp), err, eResp.Message, eReq.String())
 	}
@@ -63,7 +76,13 @@ func (c *Caller) ExchangePoint(ctx context.Context, eReq *ExchangePointRequest)
 }
 
 // ModelBaseInfos get model base infos
-func (c *Caller) ModelBaseInfos(ctx context.Context) (WujieCode, []ModelBaseInfo, error) {
+func (c *Caller) ModelBaseInfos(ctx context.Context) (code WujieCode, result []ModelBaseInfo, err error) {
+	call := &Call{Name: "ModelBaseInfos", Router: ModelBaseInfosWujieRouter}
+	if err = c.beforeCall(ctx, call); err != nil {
+		return ErrorWujieCode, result, fmt.Errorf("c.beforeCall: %w", err)
+	}
+	defer func() { c.afterCall(ctx, call, code, result, err) }()
+
 	resp, err := c.Client.ModelBaseInfos(ctx)
 	if err != nil {
 		return ErrorWujieCode, nil, fmt.Errorf("c.Client.ModelBaseInfos: %w", err)
@@ -74,8 +93,8 @@ func (c *Caller) ModelBaseInfos(ctx context.Context) (WujieCode, []ModelBaseInfo
 	if err := json.NewDecoder(resp.Body).Decode(&mResp); err != nil {
 		return ErrorWujieCode, nil, fmt.Errorf("json.NewDecoder: %w", err)
 	}
-	code := WujieCode(mResp.Code)
-	if err := code.Err(); err != nil {
+	code = WujieCode(mResp.Code)
+	if err := c.codeErr(ctx, code, mResp.Message); err != nil {
 		return code, nil, fmt.Errorf("TRACE_ID: %s, WujieCode: %w, Message: %s", getTraceID(resp), err, mResp.Message)
 	}
 
@@ -83,7 +102,13 @@ func (c *Caller) ModelBaseInfos(ctx context.Context) (WujieCode, []ModelBaseInfo
 }
 
 // DefaultResourceStyleModel get default resource style model
-func (c *Caller) DefaultResourceStyleModel(ctx context.Context) (WujieCode, []StyleModel, error) {
+func (c *Caller) DefaultResourceStyleModel(ctx context.Context) (code WujieCode, result []StyleModel, err error) {
+	call := &Call{Name: "DefaultResourceStyleModel", Router: DefaultResourceStyleModelWujieRouter}
+	if err = c.beforeCall(ctx, call); err != nil {
+		return ErrorWujieCode, result, fmt.Errorf("c.beforeCall: %w", err)
+	}
+	defer func() { c.afterCall(ctx, call, code, result, err) }()
+
 	resp, err := c.Client.DefaultResourceStyleModel(ctx)
 	if err != nil {
 		return ErrorWujieCode, nil, fmt.Errorf("c.Client.DefaultResourceStyleModel: %w", err)
@@ -94,15 +119,21 @@ func (c *Caller) DefaultResourceStyleModel(ctx context.Context) (WujieCode, []St
 	if err := json.NewDecoder(resp.Body).Decode(&dResp); err != nil {
 		return ErrorWujieCode, nil, fmt.Errorf("json.NewDecoder: %w", err)
 	}
-	code := WujieCode(dResp.Code)
-	if err := code.Err(); err != nil {
+	code = WujieCode(dResp.Code)
+	if err := c.codeErr(ctx, code, dResp.Message); err != nil {
 		return code, nil, fmt.Errorf("TRACE_ID: %s, WujieCode: %w, Message: %s", getTraceID(resp), err, dResp.Message)
 	}
 	return code, dResp.Data.StyleModels, nil
 }
 
 // DefaultResourceModel get model's default resource
-func (c *Caller) DefaultResourceModel(ctx context.Context, model int32) (WujieCode, *DefaultResourceModelData, error) {
+func (c *Caller) DefaultResourceModel(ctx context.Context, model ModelCode) (code WujieCode, result *DefaultResourceModelData, err error) {
+	call := &Call{Name: "DefaultResourceModel", Router: DefaultResourceModelWujieRouter, Request: model}
+	if err = c.beforeCall(ctx, call); err != nil {
+		return ErrorWujieCode, result, fmt.Errorf("c.beforeCall: %w", err)
+	}
+	defer func() { c.afterCall(ctx, call, code, result, err) }()
+
 	resp, err := c.Client.DefaultResourceModel(ctx, model)
 	if err != nil {
 		return ErrorWujieCode, nil, fmt.Errorf("c.Client.DefaultResourceModel: %w", err)
@@ -113,15 +144,21 @@ func (c *Caller) DefaultResourceModel(ctx context.Context, model int32) (WujieCo
 	if err := json.NewDecoder(resp.Body).Decode(&mResp); err != nil {
 		return ErrorWujieCode, nil, fmt.Errorf("json.NewDecoder: %w", err)
 	}
-	code := WujieCode(mResp.Code)
-	if err := code.Err(); err != nil {
+	code = WujieCode(mResp.Code)
+	if err := c.codeErr(ctx, code, mResp.Message); err != nil {
 		return code, nil, fmt.Errorf("TRACE_ID: %s, WujieCode: %w, Message: %s", getTraceID(resp), err, mResp.Message)
 	}
 	return code, &mResp.Data, nil
 }
 
 // CreateImage create image
-func (c *Caller) CreateImage(ctx context.Context, cReq *CreateImageRequest) (WujieCode, *CreateImageData, error) {
+func (c *Caller) CreateImage(ctx context.Context, cReq *CreateImageRequest) (code WujieCode, result *CreateImageData, err error) {
+	call := &Call{Name: "CreateImage", Router: CreateImageWujieRouter, Request: cReq}
+	if err = c.beforeCall(ctx, call); err != nil {
+		return ErrorWujieCode, result, fmt.Errorf("c.beforeCall: %w", err)
+	}
+	defer func() { c.afterCall(ctx, call, code, result, err) }()
+
 	resp, err := c.Client.CreateImage(ctx, cReq)
 	if err != nil {
 		return ErrorWujieCode, nil, fmt.Errorf("c.Client.CreateImage: %w", err)
@@ -132,8 +169,8 @@ func (c *Caller) CreateImage(ctx context.Context, cReq *CreateImageRequest) (Wuj
 	if err := json.NewDecoder(resp.Body).Decode(&cResp); err != nil {
 		return ErrorWujieCode, nil, fmt.Errorf("json.NewDecoder: %w", err)
 	}
-	code := WujieCode(cResp.Code)
-	if err := code.Err(); err != nil {
+	code = WujieCode(cResp.Code)
+	if err := c.codeErr(ctx, code, cResp.Message); err != nil {
 		return code, nil, fmt.Errorf("TRACE_ID: %s, WujieCode: %w, Message: %s, CreateImageRequest: %s",
 			getTraceID(resp), err, cResp.Message, cReq.String())
 	}
@@ -141,7 +178,13 @@ func (c *Caller) CreateImage(ctx context.Context, cReq *CreateImageRequest) (Wuj
 }
 
 // GeneratingInfo get image generating info
-func (c *Caller) GeneratingInfo(ctx context.Context, keys []string) (WujieCode, []ImageGeneratingInfo, error) {
+func (c *Caller) GeneratingInfo(ctx context.Context, keys []string) (code WujieCode, result []ImageGeneratingInfo, err error) {
+	call := &Call{Name: "GeneratingInfo", Router: ImageGeneratingInfoWujieRouter, Request: keys}
+	if err = c.beforeCall(ctx, call); err != nil {
+		return ErrorWujieCode, result, fmt.Errorf("c.beforeCall: %w", err)
+	}
+	defer func() { c.afterCall(ctx, call, code, result, err) }()
+
 	resp, err := c.Client.GeneratingInfo(ctx, keys)
 	if err != nil {
 		return ErrorWujieCode, nil, fmt.Errorf("c.Client.GeneratingInfo: %w", err)
@@ -152,8 +195,8 @@ func (c *Caller) GeneratingInfo(ctx context.Context, keys []string) (WujieCode,
 	if err := json.NewDecoder(resp.Body).Decode(&gResp); err != nil {
 		return ErrorWujieCode, nil, fmt.Errorf("json.NewDecoder: %w", err)
 	}
-	code := WujieCode(gResp.Code)
-	if err := code.Err(); err != nil {
+	code = WujieCode(gResp.Code)
+	if err := c.codeErr(ctx, code, gResp.Message); err != nil {
 		return code, nil, fmt.Errorf("TRACE_ID: %s, WujieCode: %w, Message: %s, keys: %v",
 			getTraceID(resp), err, gResp.Message, keys)
 	}
@@ -161,7 +204,13 @@ func (c *Caller) GeneratingInfo(ctx context.Context, keys []string) (WujieCode,
 }
 
 // ImageInfo get image detail
-func (c *Caller) ImageInfo(ctx context.Context, key string) (WujieCode, *ImageInfoData, error) {
+func (c *Caller) ImageInfo(ctx context.Context, key string) (code WujieCode, result *ImageInfoData, err error) {
+	call := &Call{Name: "ImageInfo", Router: ImageInfoWujieRouter, Request: key}
+	if err = c.beforeCall(ctx, call); err != nil {
+		return ErrorWujieCode, result, fmt.Errorf("c.beforeCall: %w", err)
+	}
+	defer func() { c.afterCall(ctx, call, code, result, err) }()
+
 	resp, err := c.Client.ImageInfo(ctx, key)
 	if err != nil {
 		return ErrorWujieCode, nil, fmt.Errorf("c.Client.ImageInfo: %w", err)
@@ -172,8 +221,8 @@ func (c *Caller) ImageInfo(ctx context.Context, key string) (WujieCode, *ImageIn
 	if err := json.NewDecoder(resp.Body).Decode(&iResp); err != nil {
 		return ErrorWujieCode, nil, fmt.Errorf("json.NewDecoder: %w", err)
 	}
-	code := WujieCode(iResp.Code)
-	if err := code.Err(); err != nil {
+	code = WujieCode(iResp.Code)
+	if err := c.codeErr(ctx, code, iResp.Message); err != nil {
 		return code, nil, fmt.Errorf("TRACE_ID: %s, WujieCode: %w, Message: %s, key: %s",
 			getTraceID(resp), err, iResp.Message, key)
 	}
@@ -181,7 +230,13 @@ func (c *Caller) ImageInfo(ctx context.Context, key string) (WujieCode, *ImageIn
 }
 
 // ImagePriceInfo get image price info
-func (c *Caller) ImagePriceInfo(ctx context.Context, iReq *ImagePriceInfoRequest) (WujieCode, *ImagePriceInfoData, error) {
+func (c *Caller) ImagePriceInfo(ctx context.Context, iReq *ImagePriceInfoRequest) (code WujieCode, result *ImagePriceInfoData, err error) {
+	call := &Call{Name: "ImagePriceInfo", Router: ImagePriceInfoWujieRouter, Request: iReq}
+	if err = c.beforeCall(ctx, call); err != nil {
+		return ErrorWujieCode, result, fmt.Errorf("c.beforeCall: %w", err)
+	}
+	defer func() { c.afterCall(ctx, call, code, result, err) }()
+
 	resp, err := c.Client.ImagePriceInfo(ctx, iReq)
 	if err != nil {
 		return ErrorWujieCode, nil, fmt.Errorf("c.Client.ImagePriceInfo: %w", err)
@@ -192,8 +247,8 @@ func (c *Caller) ImagePriceInfo(ctx context.Context, iReq *ImagePriceInfoRequest
 	if err := json.NewDecoder(resp.Body).Decode(&iResp); err != nil {
 		return ErrorWujieCode, nil, fmt.Errorf("json.NewDecoder: %w", err)
 	}
-	code := WujieCode(iResp.Code)
-	if err := code.Err(); err != nil {
+	code = WujieCode(iResp.Code)
+	if err := c.codeErr(ctx, code, iResp.Message); err != nil {
 		return code, nil, fmt.Errorf("TRACE_ID: %s, WujieCode: %w, Message: %s, ImagePriceInfoRequest: %s",
 			getTraceID(resp), err, iResp.Message, iReq.String())
 	}
@@ -201,7 +256,13 @@ func (c *Caller) ImagePriceInfo(ctx context.Context, iReq *ImagePriceInfoRequest
 }
 
 // PostSuperSize create super size
-func (c *Caller) PostSuperSize(ctx context.Context, pReq *PostSuperSizeRequest) (WujieCode, string, error) {
+func (c *Caller) PostSuperSize(ctx context.Context, pReq *PostSuperSizeRequest) (code WujieCode, result string, err error) {
+	call := &Call{Name: "PostSuperSize", Router: SuperSizeWujieRouter, Request: pReq}
+	if err = c.beforeCall(ctx, call); err != nil {
+		return ErrorWujieCode, result, fmt.Errorf("c.beforeCall: %w", err)
+	}
+	defer func() { c.afterCall(ctx, call, code, result, err) }()
+
 	resp, err := c.Client.PostSuperSize(ctx, pReq)
 	if err != nil {
 		return ErrorWujieCode, "", fmt.Errorf("c.Client.PostSuperSize: %w", err)
@@ -212,8 +273,8 @@ func (c *Caller) PostSuperSize(ctx context.Context, pReq *PostSuperSizeRequest)
 	if err := json.NewDecoder(resp.Body).Decode(&pResp); err != nil {
 		return ErrorWujieCode, "", fmt.Errorf("json.NewDecoder: %w", err)
 	}
-	code := WujieCode(pResp.Code)
-	if err := code.Err(); err != nil {
+	code = WujieCode(pResp.Code)
+	if err := c.codeErr(ctx, code, pResp.Message); err != nil {
 		return code, "", fmt.Errorf("TRACE_ID: %s, WujieCode: %w, Message: %s, PostSuperSizeRequest: %s",
 			getTraceID(resp), err, pResp.Message, pReq.String())
 	}
@@ -221,7 +282,13 @@ func (c *Caller) PostSuperSize(ctx context.Context, pReq *PostSuperSizeRequest)
 }
 
 // GetSuperSize get super size result
-func (c *Caller) GetSuperSize(ctx context.Context, keys []string) (WujieCode, []SuperSizeInfo, error) {
+func (c *Caller) GetSuperSize(ctx context.Context, keys []string) (code WujieCode, result []SuperSizeInfo, err error) {
+	call := &Call{Name: "GetSuperSize", Router: SuperSizeWujieRouter, Request: keys}
+	if err = c.beforeCall(ctx, call); err != nil {
+		return ErrorWujieCode, result, fmt.Errorf("c.beforeCall: %w", err)
+	}
+	defer func() { c.afterCall(ctx, call, code, result, err) }()
+
 	resp, err := c.Client.GetSuperSize(ctx, keys)
 	if err != nil {
 		return ErrorWujieCode, nil, fmt.Errorf("c.Client.GetSuperSize: %w", err)
@@ -232,8 +299,8 @@ func (c *Caller) GetSuperSize(ctx context.Context, keys []string) (WujieCode, []
 	if err := json.NewDecoder(resp.Body).Decode(&gResp); err != nil {
 		return ErrorWujieCode, nil, fmt.Errorf("json.NewDecoder: %w", err)
 	}
-	code := WujieCode(gResp.Code)
-	if err := code.Err(); err != nil {
+	code = WujieCode(gResp.Code)
+	if err := c.codeErr(ctx, code, gResp.Message); err != nil {
 		return code, nil, fmt.Errorf("TRACE_ID: %s, WujieCode: %w, Message: %s, keys: %v",
 			getTraceID(resp), err, gResp.Message, keys)
 	}
@@ -241,7 +308,13 @@ func (c *Caller) GetSuperSize(ctx context.Context, keys []string) (WujieCode, []
 }
 
 // CreateParams get create params
-func (c *Caller) CreateParams(ctx context.Context, keys []string) (WujieCode, []CreateParams, error) {
+func (c *Caller) CreateParams(ctx context.Context, keys []string) (code WujieCode, result []CreateParams, err error) {
+	call := &Call{Name: "CreateParams", Router: CreateParamsWujieRouter, Request: keys}
+	if err = c.beforeCall(ctx, call); err != nil {
+		return ErrorWujieCode, result, fmt.Errorf("c.beforeCall: %w", err)
+	}
+	defer func() { c.afterCall(ctx, call, code, result, err) }()
+
 	resp, err := c.Client.CreateParams(ctx, keys)
 	if err != nil {
 		return ErrorWujieCode, nil, fmt.Errorf("c.Client.CreateParams: %w", err)
@@ -252,8 +325,8 @@ func (c *Caller) CreateParams(ctx context.Context, keys []string) (WujieCode, []
 	if err := json.NewDecoder(resp.Body).Decode(&cResp); err != nil {
 		return ErrorWujieCode, nil, fmt.Errorf("json.NewDecoder: %w", err)
 	}
-	code := WujieCode(cResp.Code)
-	if err := code.Err(); err != nil {
+	code = WujieCode(cResp.Code)
+	if err := c.codeErr(ctx, code, cResp.Message); err != nil {
 		return code, nil, fmt.Errorf("TRACE_ID: %s, WujieCode: %w, Message: %s, keys: %v",
 			getTraceID(resp), err, cResp.Message, keys)
 	}
@@ -261,7 +334,13 @@ func (c *Caller) CreateParams(ctx context.Context, keys []string) (WujieCode, []
 }
 
 // ImageModelQueueInfo get image model queue info
-func (c *Caller) ImageModelQueueInfo(ctx context.Context, model int32) (WujieCode, *ImageModelQueueInfoData, error) {
+func (c *Caller) ImageModelQueueInfo(ctx context.Context, model ModelCode) (code WujieCode, result *ImageModelQueueInfoData, err error) {
+	call := &Call{Name: "ImageModelQueueInfo", Router: ImageModelQueueInfoWujieRouter, Request: model}
+	if err = c.beforeCall(ctx, call); err != nil {
+		return ErrorWujieCode, result, fmt.Errorf("c.beforeCall: %w", err)
+	}
+	defer func() { c.afterCall(ctx, call, code, result, err) }()
+
 	resp, err := c.Client.ImageModelQueueInfo(ctx, model)
 	if err != nil {
 		return ErrorWujieCode, nil, fmt.Errorf("c.Client.ImageModelQueueInfo: %w", err)
@@ -272,8 +351,8 @@ func (c *Caller) ImageModelQueueInfo(ctx context.Context, model int32) (WujieCod
 	if err := json.NewDecoder(resp.Body).Decode(&iResp); err != nil {
 		return ErrorWujieCode, nil, fmt.Errorf("json.NewDecoder: %w", err)
 	}
-	code := WujieCode(iResp.Code)
-	if err := code.Err(); err != nil {
+	code = WujieCode(iResp.Code)
+	if err := c.codeErr(ctx, code, iResp.Message); err != nil {
 		return code, nil, fmt.Errorf("TRACE_ID: %s, WujieCode: %w, Message: %s, ImageModelQueueInfoRequest: %v",
 			getTraceID(resp), err, iResp.Message, model)
 	}
@@ -281,7 +360,13 @@ func (c *Caller) ImageModelQueueInfo(ctx context.Context, model int32) (WujieCod
 }
 
 // CancelImage cancel image
-func (c *Caller) CancelImage(ctx context.Context, key string) (WujieCode, string, error) {
+func (c *Caller) CancelImage(ctx context.Context, key string) (code WujieCode, result string, err error) {
+	call := &Call{Name: "CancelImage", Router: CancelImageWujieRouter, Request: key}
+	if err = c.beforeCall(ctx, call); err != nil {
+		return ErrorWujieCode, result, fmt.Errorf("c.beforeCall: %w", err)
+	}
+	defer func() { c.afterCall(ctx, call, code, result, err) }()
+
 	resp, err := c.Client.CancelImage(ctx, key)
 	if err != nil {
 		return ErrorWujieCode, "", fmt.Errorf("c.Client.CancelImage: %w", err)
@@ -292,8 +377,8 @@ func (c *Caller) CancelImage(ctx context.Context, key string) (WujieCode, string
 	if err := json.NewDecoder(resp.Body).Decode(&cResp); err != nil {
 		return ErrorWujieCode, "", fmt.Errorf("json.NewDecoder: %w", err)
 	}
-	code := WujieCode(cResp.Code)
-	if err := code.Err(); err != nil {
+	code = WujieCode(cResp.Code)
+	if err := c.codeErr(ctx, code, cResp.Message); err != nil {
 		return code, "", fmt.Errorf("TRACE_ID: %s, WujieCode: %w, Message: %s, key: %s",
 			getTraceID(resp), err, cResp.Message, key)
 	}
@@ -301,7 +386,13 @@ func (c *Caller) CancelImage(ctx context.Context, key string) (WujieCode, string
 }
 
 // AccelerateImage accelerate image
-func (c *Caller) AccelerateImage(ctx context.Context, aReq *AccelerateImageRequest) (WujieCode, bool, error) {
+func (c *Caller) AccelerateImage(ctx context.Context, aReq *AccelerateImageRequest) (code WujieCode, result bool, err error) {
+	call := &Call{Name: "AccelerateImage", Router: AccelerateImageWujieRouter, Request: aReq}
+	if err = c.beforeCall(ctx, call); err != nil {
+		return ErrorWujieCode, result, fmt.Errorf("c.beforeCall: %w", err)
+	}
+	defer func() { c.afterCall(ctx, call, code, result, err) }()
+
 	resp, err := c.Client.AccelerateImage(ctx, aReq)
 	if err != nil {
 		return ErrorWujieCode, false, fmt.Errorf("c.Client.AccelerateImage: %w", err)
@@ -312,8 +403,8 @@ func (c *Caller) AccelerateImage(ctx context.Context, aReq *AccelerateImageReque
 	if err := json.NewDecoder(resp.Body).Decode(&aResp); err != nil {
 		return ErrorWujieCode, false, fmt.Errorf("json.NewDecoder: %w", err)
 	}
-	code := WujieCode(aResp.Code)
-	if err := code.Err(); err != nil {
+	code = WujieCode(aResp.Code)
+	if err := c.codeErr(ctx, code, aResp.Message); err != nil {
 		return code, false, fmt.Errorf("TRACE_ID: %s, WujieCode: %w, Message: %s, AccelerateImageRequest: %s",
 			getTraceID(resp), err, aResp.Message, aReq.String())
 	}
@@ -321,7 +412,13 @@ func (c *Caller) AccelerateImage(ctx context.Context, aReq *AccelerateImageReque
 }
 
 // PromptOptimizeSubmit submit prompt optimize
-func (c *Caller) PromptOptimizeSubmit(ctx context.Context, pReq *PromptOptimizeSubmitRequest) (WujieCode, bool, error) {
+func (c *Caller) PromptOptimizeSubmit(ctx context.Context, pReq *PromptOptimizeSubmitRequest) (code WujieCode, result bool, err error) {
+	call := &Call{Name: "PromptOptimizeSubmit", Router: PromptOptimizeSubmitWujieRouter, Request: pReq}
+	if err = c.beforeCall(ctx, call); err != nil {
+		return ErrorWujieCode, result, fmt.Errorf("c.beforeCall: %w", err)
+	}
+	defer func() { c.afterCall(ctx, call, code, result, err) }()
+
 	resp, err := c.Client.PromptOptimizeSubmit(ctx, pReq)
 	if err != nil {
 		return ErrorWujieCode, false, fmt.Errorf("c.Client.PromptOptimizeSubmit: %w", err)
@@ -332,8 +429,8 @@ func (c *Caller) PromptOptimizeSubmit(ctx context.Context, pReq *PromptOptimizeS
 	if err := json.NewDecoder(resp.Body).Decode(&pResp); err != nil {
 		return ErrorWujieCode, false, fmt.Errorf("json.NewDecoder: %w", err)
 	}
-	code := WujieCode(pResp.Code)
-	if err := code.Err(); err != nil {
+	code = WujieCode(pResp.Code)
+	if err := c.codeErr(ctx, code, pResp.Message); err != nil {
 		return code, false, fmt.Errorf("TRACE_ID: %s, WujieCode: %w, Message: %s, PromptOptimizeSubmitRequest: %s",
 			getTraceID(resp), err, pResp.Message, pReq.String())
 	}
@@ -341,7 +438,13 @@ func (c *Caller) PromptOptimizeSubmit(ctx context.Context, pReq *PromptOptimizeS
 }
 
 // PromptOptimizeResult get prompt optimize result
-func (c *Caller) PromptOptimizeResult(ctx context.Context, taskID string) (WujieCode, *PromptOptimizeResultData, error) {
+func (c *Caller) PromptOptimizeResult(ctx context.Context, taskID string) (code WujieCode, result *PromptOptimizeResultData, err error) {
+	call := &Call{Name: "PromptOptimizeResult", Router: PromptOptimizeResultWujieRouter, Request: taskID}
+	if err = c.beforeCall(ctx, call); err != nil {
+		return ErrorWujieCode, result, fmt.Errorf("c.beforeCall: %w", err)
+	}
+	defer func() { c.afterCall(ctx, call, code, result, err) }()
+
 	resp, err := c.Client.PromptOptimizeResult(ctx, taskID)
 	if err != nil {
 		return ErrorWujieCode, nil, fmt.Errorf("c.Client.PromptOptimizeResult: %w", err)
@@ -352,8 +455,8 @@ func (c *Caller) PromptOptimizeResult(ctx context.Context, taskID string) (Wujie
 	if err := json.NewDecoder(resp.Body).Decode(&pResp); err != nil {
 		return ErrorWujieCode, nil, fmt.Errorf("json.NewDecoder: %w", err)
 	}
-	code := WujieCode(pResp.Code)
-	if err := code.Err(); err != nil {
+	code = WujieCode(pResp.Code)
+	if err := c.codeErr(ctx, code, pResp.Message); err != nil {
 		return code, nil, fmt.Errorf("TRACE_ID: %s, WujieCode: %w, Message: %s, taskID: %s",
 			getTraceID(resp), err, pResp.Message, taskID)
 	}
@@ -361,7 +464,13 @@ func (c *Caller) PromptOptimizeResult(ctx context.Context, taskID string) (Wujie
 }
 
 // Youthify youthify image
-func (c *Caller) Youthify(ctx context.Context, yReq *YouthifyRequest) (WujieCode, *YouthifyData, error) {
+func (c *Caller) Youthify(ctx context.Context, yReq *YouthifyRequest) (code WujieCode, result *YouthifyData, err error) {
+	call := &Call{Name: "Youthify", Router: YouthifyWujieRouter, Request: yReq}
+	if err = c.beforeCall(ctx, call); err != nil {
+		return ErrorWujieCode, result, fmt.Errorf("c.beforeCall: %w", err)
+	}
+	defer func() { c.afterCall(ctx, call, code, result, err) }()
+
 	resp, err := c.Client.Youthify(ctx, yReq)
 	if err != nil {
 		return ErrorWujieCode, nil, fmt.Errorf("c.Client.Youthify: %w", err)
@@ -372,8 +481,8 @@ func (c *Caller) Youthify(ctx context.Context, yReq *YouthifyRequest) (WujieCode
 	if err := json.NewDecoder(resp.Body).Decode(&yResp); err != nil {
 		return ErrorWujieCode, nil, fmt.Errorf("json.NewDecoder: %w", err)
 	}
-	code := WujieCode(yResp.Code)
-	if err := code.Err(); err != nil {
+	code = WujieCode(yResp.Code)
+	if err := c.codeErr(ctx, code, yResp.Message); err != nil {
 		return code, nil, fmt.Errorf("TRACE_ID: %s, WujieCode: %w, Message: %s, YouthifyRequest: %s",
 			getTraceID(resp), err, yResp.Message, yReq.String())
 	}
@@ -381,7 +490,13 @@ func (c *Caller) Youthify(ctx context.Context, yReq *YouthifyRequest) (WujieCode
 }
 
 // QuerySpell query spell
-func (c *Caller) QuerySpell(ctx context.Context) (WujieCode, []QuerySpellData, error) {
+func (c *Caller) QuerySpell(ctx context.Context) (code WujieCode, result []QuerySpellData, err error) {
+	call := &Call{Name: "QuerySpell", Router: QuerySpellWujieRouter}
+	if err = c.beforeCall(ctx, call); err != nil {
+		return ErrorWujieCode, result, fmt.Errorf("c.beforeCall: %w", err)
+	}
+	defer func() { c.afterCall(ctx, call, code, result, err) }()
+
 	resp, err := c.Client.QuerySpell(ctx)
 	if err != nil {
 		return ErrorWujieCode, nil, fmt.Errorf("c.Client.QuerySpell: %w", err)
@@ -392,15 +507,21 @@ func (c *Caller) QuerySpell(ctx context.Context) (WujieCode, []QuerySpellData, e
 	if err := json.NewDecoder(resp.Body).Decode(&qResp); err != nil {
 		return ErrorWujieCode, nil, fmt.Errorf("json.NewDecoder: %w", err)
 	}
-	code := WujieCode(qResp.Code)
-	if err := code.Err(); err != nil {
+	code = WujieCode(qResp.Code)
+	if err := c.codeErr(ctx, code, qResp.Message); err != nil {
 		return code, nil, fmt.Errorf("TRACE_ID: %s, WujieCode: %w, Message: %s", getTraceID(resp), err, qResp.Message)
 	}
 	return code, qResp.Data, nil
 }
 
 // CreateImagePro create pro image
-func (c *Caller) CreateImagePro(ctx context.Context, cReq *CreateImageProRequest) (WujieCode, []CreateImageProResult, error) {
+func (c *Caller) CreateImagePro(ctx context.Context, cReq *CreateImageProRequest) (code WujieCode, result []CreateImageProResult, err error) {
+	call := &Call{Name: "CreateImagePro", Router: CreateImageProWujieRouter, Request: cReq}
+	if err = c.beforeCall(ctx, call); err != nil {
+		return ErrorWujieCode, result, fmt.Errorf("c.beforeCall: %w", err)
+	}
+	defer func() { c.afterCall(ctx, call, code, result, err) }()
+
 	resp, err := c.Client.CreateImagePro(ctx, cReq)
 	if err != nil {
 		return ErrorWujieCode, nil, fmt.Errorf("c.Client.CreateImagePro: %w", err)
@@ -411,8 +532,8 @@ func (c *Caller) CreateImagePro(ctx context.Context, cReq *CreateImageProRequest
 	if err := json.NewDecoder(resp.Body).Decode(&cResp); err != nil {
 		return ErrorWujieCode, nil, fmt.Errorf("json.NewDecoder: %w", err)
 	}
-	code := WujieCode(cResp.Code)
-	if err := code.Err(); err != nil {
+	code = WujieCode(cResp.Code)
+	if err := c.codeErr(ctx, code, cResp.Message); err != nil {
 		return code, nil, fmt.Errorf("TRACE_ID: %s, WujieCode: %w, Message: %s, CreateImageProRequest: %s",
 			getTraceID(resp), err, cResp.Message, cReq.String())
 	}
@@ -420,7 +541,13 @@ func (c *Caller) CreateImagePro(ctx context.Context, cReq *CreateImageProRequest
 }
 
 // GeneratingInfoPro get pro image generating info
-func (c *Caller) GeneratingInfoPro(ctx context.Context, keys []string) (WujieCode, []GeneratingInfoPro, error) {
+func (c *Caller) GeneratingInfoPro(ctx context.Context, keys []string) (code WujieCode, result []GeneratingInfoPro, err error) {
+	call := &Call{Name: "GeneratingInfoPro", Router: ImageGeneratingInfoProWujieRouter, Request: keys}
+	if err = c.beforeCall(ctx, call); err != nil {
+		return ErrorWujieCode, result, fmt.Errorf("c.beforeCall: %w", err)
+	}
+	defer func() { c.afterCall(ctx, call, code, result, err) }()
+
 	resp, err := c.Client.GeneratingInfoPro(ctx, keys)
 	if err != nil {
 		return ErrorWujieCode, nil, fmt.Errorf("c.Client.GeneratingInfoPro: %w", err)
@@ -431,8 +558,8 @@ func (c *Caller) GeneratingInfoPro(ctx context.Context, keys []string) (WujieCod
 	if err := json.NewDecoder(resp.Body).Decode(&gResp); err != nil {
 		return ErrorWujieCode, nil, fmt.Errorf("json.NewDecoder: %w", err)
 	}
-	code := WujieCode(gResp.Code)
-	if err := code.Err(); err != nil {
+	code = WujieCode(gResp.Code)
+	if err := c.codeErr(ctx, code, gResp.Message); err != nil {
 		return code, nil, fmt.Errorf("TRACE_ID: %s, WujieCode: %w, Message: %s, keys: %v",
 			getTraceID(resp), err, gResp.Message, keys)
 	}
@@ -440,7 +567,13 @@ func (c *Caller) GeneratingInfoPro(ctx context.Context, keys []string) (WujieCod
 }
 
 // AccountBalancePro get account balance pro
-func (c *Caller) AccountBalancePro(ctx context.Context) (WujieCode, int, error) {
+func (c *Caller) AccountBalancePro(ctx context.Context) (code WujieCode, result int, err error) {
+	call := &Call{Name: "AccountBalancePro", Router: AccountBalanceProWujieRouter}
+	if err = c.beforeCall(ctx, call); err != nil {
+		return ErrorWujieCode, result, fmt.Errorf("c.beforeCall: %w", err)
+	}
+	defer func() { c.afterCall(ctx, call, code, result, err) }()
+
 	resp, err := c.Client.AccountBalancePro(ctx)
 	if err != nil {
 		return ErrorWujieCode, 0, fmt.Errorf("c.Client.AccountBalancePro: %w", err)
@@ -451,15 +584,21 @@ func (c *Caller) AccountBalancePro(ctx context.Context) (WujieCode, int, error)
 	if err := json.NewDecoder(resp.Body).Decode(&aResp); err != nil {
 		return ErrorWujieCode, 0, fmt.Errorf("json.NewDecoder: %w", err)
 	}
-	code := WujieCode(aResp.Code)
-	if err := code.Err(); err != nil {
+	code = WujieCode(aResp.Code)
+	if err := c.codeErr(ctx, code, aResp.Message); err != nil {
 		return code, 0, fmt.Errorf("TRACE_ID: %s, WujieCode: %w, Message: %s", getTraceID(resp), err, aResp.Message)
 	}
 	return code, aResp.Data.ResourceBalance, nil
 }
 
 // ModelBaseInfosPro get model base infos pro
-func (c *Caller) ModelBaseInfosPro(ctx context.Context) (WujieCode, []ModelBaseInfoPro, error) {
+func (c *Caller) ModelBaseInfosPro(ctx context.Context) (code WujieCode, result []ModelBaseInfoPro, err error) {
+	call := &Call{Name: "ModelBaseInfosPro", Router: ModelBaseInfosProWujieRouter}
+	if err = c.beforeCall(ctx, call); err != nil {
+		return ErrorWujieCode, result, fmt.Errorf("c.beforeCall: %w", err)
+	}
+	defer func() { c.afterCall(ctx, call, code, result, err) }()
+
 	resp, err := c.Client.ModelBaseInfosPro(ctx)
 	if err != nil {
 		return ErrorWujieCode, nil, fmt.Errorf("c.Client.ModelBaseInfosPro: %w", err)
@@ -470,15 +609,21 @@ func (c *Caller) ModelBaseInfosPro(ctx context.Context) (WujieCode, []ModelBaseI
 	if err := json.NewDecoder(resp.Body).Decode(&mResp); err != nil {
 		return ErrorWujieCode, nil, fmt.Errorf("json.NewDecoder: %w", err)
 	}
-	code := WujieCode(mResp.Code)
-	if err := code.Err(); err != nil {
+	code = WujieCode(mResp.Code)
+	if err := c.codeErr(ctx, code, mResp.Message); err != nil {
 		return code, nil, fmt.Errorf("TRACE_ID: %s, WujieCode: %w, Message: %s", getTraceID(resp), err, mResp.Message)
 	}
 	return code, mResp.Data, nil
 }
 
 // ControlNetOptionPro control net option pro
-func (c *Caller) ControlNetOptionPro(ctx context.Context) (WujieCode, []ControlNetOptionPro, error) {
+func (c *Caller) ControlNetOptionPro(ctx context.Context) (code WujieCode, result []ControlNetOptionPro, err error) {
+	call := &Call{Name: "ControlNetOptionPro", Router: ControlNetOptionProWujieRouter}
+	if err = c.beforeCall(ctx, call); err != nil {
+		return ErrorWujieCode, result, fmt.Errorf("c.beforeCall: %w", err)
+	}
+	defer func() { c.afterCall(ctx, call, code, result, err) }()
+
 	resp, err := c.Client.ControlNetOptionPro(ctx)
 	if err != nil {
 		return ErrorWujieCode, nil, fmt.Errorf("c.Client.ControlNetOptionPro: %w", err)
@@ -489,8 +634,8 @@ func (c *Caller) ControlNetOptionPro(ctx context.Context) (WujieCode, []ControlN
 	if err := json.NewDecoder(resp.Body).Decode(&cResp); err != nil {
 		return ErrorWujieCode, nil, fmt.Errorf("json.NewDecoder: %w", err)
 	}
-	code := WujieCode(cResp.Code)
-	if err := code.Err(); err != nil {
+	code = WujieCode(cResp.Code)
+	if err := c.codeErr(ctx, code, cResp.Message); err != nil {
 		return code, nil, fmt.Errorf("TRACE_ID: %s, WujieCode: %w, Message: %s",
 			getTraceID(resp), err, cResp.Message)
 	}
@@ -498,7 +643,13 @@ func (c *Caller) ControlNetOptionPro(ctx context.Context) (WujieCode, []ControlN
 }
 
 // ImageInfoPro get image info pro
-func (c *Caller) ImageInfoPro(ctx context.Context, key string) (WujieCode, *ImageInfoPro, error) {
+func (c *Caller) ImageInfoPro(ctx context.Context, key string) (code WujieCode, result *ImageInfoPro, err error) {
+	call := &Call{Name: "ImageInfoPro", Router: ImageInfoProWujieRouter, Request: key}
+	if err = c.beforeCall(ctx, call); err != nil {
+		return ErrorWujieCode, result, fmt.Errorf("c.beforeCall: %w", err)
+	}
+	defer func() { c.afterCall(ctx, call, code, result, err) }()
+
 	resp, err := c.Client.ImageInfoPro(ctx, key)
 	if err != nil {
 		return ErrorWujieCode, nil, fmt.Errorf("c.Client.ImageInfoPro: %w", err)
@@ -509,8 +660,8 @@ func (c *Caller) ImageInfoPro(ctx context.Context, key string) (WujieCode, *Imag
 	if err := json.NewDecoder(resp.Body).Decode(&iResp); err != nil {
 		return ErrorWujieCode, nil, fmt.Errorf("json.NewDecoder: %w", err)
 	}
-	code := WujieCode(iResp.Code)
-	if err := code.Err(); err != nil {
+	code = WujieCode(iResp.Code)
+	if err := c.codeErr(ctx, code, iResp.Message); err != nil {
 		return code, nil, fmt.Errorf("TRACE_ID: %s, WujieCode: %w, Message: %s, key: %s",
 			getTraceID(resp), err, iResp.Message, key)
 	}
@@ -518,7 +669,13 @@ func (c *Caller) ImageInfoPro(ctx context.Context, key string) (WujieCode, *Imag
 }
 
 // CreateAvatar create avatar
-func (c *Caller) CreateAvatar(ctx context.Context, cReq *CreateAvatarRequest) (WujieCode, *CreateAvatarData, error) {
+func (c *Caller) CreateAvatar(ctx context.Context, cReq *CreateAvatarRequest) (code WujieCode, result *CreateAvatarData, err error) {
+	call := &Call{Name: "CreateAvatar", Router: CreateAvatarWujieRouter, Request: cReq}
+	if err = c.beforeCall(ctx, call); err != nil {
+		return ErrorWujieCode, result, fmt.Errorf("c.beforeCall: %w", err)
+	}
+	defer func() { c.afterCall(ctx, call, code, result, err) }()
+
 	resp, err := c.Client.CreateAvatar(ctx, cReq)
 	if err != nil {
 		return ErrorWujieCode, nil, fmt.Errorf("c.Client.CreateAvatar: %w", err)
@@ -530,8 +687,8 @@ func (c *Caller) CreateAvatar(ctx context.Context, cReq *CreateAvatarRequest) (W
 		return ErrorWujieCode, nil, fmt.Errorf("json.NewDecoder: %w", err)
 	}
 
-	code := WujieCode(cResp.Code)
-	if err := code.Err(); err != nil {
+	code = WujieCode(cResp.Code)
+	if err := c.codeErr(ctx, code, cResp.Message); err != nil {
 		return code, nil, fmt.Errorf("TRACE_ID: %s, WujieCode: %w, Message: %s, CreateAvatarRequest: %s",
 			getTraceID(resp), err, cResp.Message, cReq.String())
 	}
@@ -539,7 +696,13 @@ func (c *Caller) CreateAvatar(ctx context.Context, cReq *CreateAvatarRequest) (W
 }
 
 // DeleteAvatar delete avatar
-func (c *Caller) DeleteAvatar(ctx context.Context, key string) (WujieCode, bool, error) {
+func (c *Caller) DeleteAvatar(ctx context.Context, key string) (code WujieCode, result bool, err error) {
+	call := &Call{Name: "DeleteAvatar", Router: DeleteAvatarWujieRouter, Request: key}
+	if err = c.beforeCall(ctx, call); err != nil {
+		return ErrorWujieCode, result, fmt.Errorf("c.beforeCall: %w", err)
+	}
+	defer func() { c.afterCall(ctx, call, code, result, err) }()
+
 	resp, err := c.Client.DeleteAvatar(ctx, key)
 	if err != nil {
 		return ErrorWujieCode, false, fmt.Errorf("c.Client.DeleteAvatar: %w", err)
@@ -550,8 +713,8 @@ func (c *Caller) DeleteAvatar(ctx context.Context, key string) (WujieCode, bool,
 	if err := json.NewDecoder(resp.Body).Decode(&dResp); err != nil {
 		return ErrorWujieCode, false, fmt.Errorf("json.NewDecoder: %w", err)
 	}
-	code := WujieCode(dResp.Code)
-	if err := code.Err(); err != nil {
+	code = WujieCode(dResp.Code)
+	if err := c.codeErr(ctx, code, dResp.Message); err != nil {
 		return code, false, fmt.Errorf("TRACE_ID: %s, WujieCode: %w, Message: %s, key: %s",
 			getTraceID(resp), err, dResp.Message, key)
 	}
@@ -559,7 +722,13 @@ func (c *Caller) DeleteAvatar(ctx context.Context, key string) (WujieCode, bool,
 }
 
 // AvatarInfo get avatar info
-func (c *Caller) AvatarInfo(ctx context.Context, key string) (WujieCode, *AvatarInfoData, error) {
+func (c *Caller) AvatarInfo(ctx context.Context, key string) (code WujieCode, result *AvatarInfoData, err error) {
+	call := &Call{Name: "AvatarInfo", Router: AvatarInfoWujieRouter, Request: key}
+	if err = c.beforeCall(ctx, call); err != nil {
+		return ErrorWujieCode, result, fmt.Errorf("c.beforeCall: %w", err)
+	}
+	defer func() { c.afterCall(ctx, call, code, result, err) }()
+
 	resp, err := c.Client.AvatarInfo(ctx, key)
 	if err != nil {
 		return ErrorWujieCode, nil, fmt.Errorf("c.Client.AvatarInfo: %w", err)
@@ -571,8 +740,8 @@ func (c *Caller) AvatarInfo(ctx context.Context, key string) (WujieCode, *Avatar
 		return ErrorWujieCode, nil, fmt.Errorf("json.NewDecoder: %w", err)
 	}
 
-	code := WujieCode(aResp.Code)
-	if err := code.Err(); err != nil {
+	code = WujieCode(aResp.Code)
+	if err := c.codeErr(ctx, code, aResp.Message); err != nil {
 		return code, nil, fmt.Errorf("TRACE_ID: %s, WujieCode: %w, Message: %s, key: %s",
 			getTraceID(resp), err, aResp.Message, key)
 	}
@@ -580,7 +749,13 @@ func (c *Caller) AvatarInfo(ctx context.Context, key string) (WujieCode, *Avatar
 }
 
 // ImageBatchCheck image batch check
-func (c *Caller) ImageBatchCheck(ctx context.Context, imageURLList []string) (WujieCode, []ImageCheckInfo, error) {
+func (c *Caller) ImageBatchCheck(ctx context.Context, imageURLList []string) (code WujieCode, result []ImageCheckInfo, err error) {
+	call := &Call{Name: "ImageBatchCheck", Router: ImageBatchCheckWujieRouter, Request: imageURLList}
+	if err = c.beforeCall(ctx, call); err != nil {
+		return ErrorWujieCode, result, fmt.Errorf("c.beforeCall: %w", err)
+	}
+	defer func() { c.afterCall(ctx, call, code, result, err) }()
+
 	resp, err := c.Client.ImageBatchCheck(ctx, imageURLList)
 	if err != nil {
 		return ErrorWujieCode, nil, fmt.Errorf("c.Client.ImageBatchCheck: %w", err)
@@ -592,8 +767,8 @@ func (c *Caller) ImageBatchCheck(ctx context.Context, imageURLList []string) (Wu
 		return ErrorWujieCode, nil, fmt.Errorf("json.NewDecoder: %w", err)
 	}
 
-	code := WujieCode(iResp.Code)
-	if err := code.Err(); err != nil {
+	code = WujieCode(iResp.Code)
+	if err := c.codeErr(ctx, code, iResp.Message); err != nil {
 		return code, nil, fmt.Errorf("TRACE_ID: %s, WujieCode: %w, Message: %s",
 			getTraceID(resp), err, iResp.Message)
 	}
@@ -601,7 +776,13 @@ func (c *Caller) ImageBatchCheck(ctx context.Context, imageURLList []string) (Wu
 }
 
 // CreateAvatarArtwork create avatar artwork
-func (c *Caller) CreateAvatarArtwork(ctx context.Context, cReq *CreateAvatarArtworkRequest) (WujieCode, *CreateAvatarArtworkData, error) {
+func (c *Caller) CreateAvatarArtwork(ctx context.Context, cReq *CreateAvatarArtworkRequest) (code WujieCode, result *CreateAvatarArtworkData, err error) {
+	call := &Call{Name: "CreateAvatarArtwork", Router: CreateAvatarArtworkWujieRouter, Request: cReq}
+	if err = c.beforeCall(ctx, call); err != nil {
+		return ErrorWujieCode, result, fmt.Errorf("c.beforeCall: %w", err)
+	}
+	defer func() { c.afterCall(ctx, call, code, result, err) }()
+
 	resp, err := c.Client.CreateAvatarArtwork(ctx, cReq)
 	if err != nil {
 		return ErrorWujieCode, nil, fmt.Errorf("c.Client.CreateAvatarArtwork: %w", err)
@@ -612,8 +793,8 @@ func (c *Caller) CreateAvatarArtwork(ctx context.Context, cReq *CreateAvatarArtw
 	if err := json.NewDecoder(resp.Body).Decode(&cResp); err != nil {
 		return ErrorWujieCode, nil, fmt.Errorf("json.NewDecoder: %w", err)
 	}
-	code := WujieCode(cResp.Code)
-	if err := code.Err(); err != nil {
+	code = WujieCode(cResp.Code)
+	if err := c.codeErr(ctx, code, cResp.Message); err != nil {
 		return code, nil, fmt.Errorf("TRACE_ID: %s, WujieCode: %w, Message: %s, CreateAvatarArtworkRequest: %s",
 			getTraceID(resp), err, cResp.Message, cReq.String())
 	}
@@ -621,7 +802,13 @@ func (c *Caller) CreateAvatarArtwork(ctx context.Context, cReq *CreateAvatarArtw
 }
 
 // AvatarDefaultResource get avatar default resource
-func (c *Caller) AvatarDefaultResource(ctx context.Context) (WujieCode, *AvatarDefaultResource, error) {
+func (c *Caller) AvatarDefaultResource(ctx context.Context) (code WujieCode, result *AvatarDefaultResource, err error) {
+	call := &Call{Name: "AvatarDefaultResource", Router: AvatarDefaultResourceWujieRouter}
+	if err = c.beforeCall(ctx, call); err != nil {
+		return ErrorWujieCode, result, fmt.Errorf("c.beforeCall: %w", err)
+	}
+	defer func() { c.afterCall(ctx, call, code, result, err) }()
+
 	resp, err := c.Client.AvatarDefaultResource(ctx)
 	if err != nil {
 		return ErrorWujieCode, nil, fmt.Errorf("c.Client.AvatarDefaultResource: %w", err)
@@ -632,15 +819,21 @@ func (c *Caller) AvatarDefaultResource(ctx context.Context) (WujieCode, *AvatarD
 	if err := json.NewDecoder(resp.Body).Decode(&aResp); err != nil {
 		return ErrorWujieCode, nil, fmt.Errorf("json.NewDecoder: %w", err)
 	}
-	code := WujieCode(aResp.Code)
-	if err := code.Err(); err != nil {
+	code = WujieCode(aResp.Code)
+	if err := c.codeErr(ctx, code, aResp.Message); err != nil {
 		return code, nil, fmt.Errorf("TRACE_ID: %s, WujieCode: %w, Message: %s", getTraceID(resp), err, aResp.Message)
 	}
 	return code, &aResp.Data, nil
 }
 
 // CreateSpellAnalysis create spell analysis
-func (c *Caller) CreateSpellAnalysis(ctx context.Context, cReq *CreateSpellAnalysisRequest) (WujieCode, string, error) {
+func (c *Caller) CreateSpellAnalysis(ctx context.Context, cReq *CreateSpellAnalysisRequest) (code WujieCode, result string, err error) {
+	call := &Call{Name: "CreateSpellAnalysis", Router: CreateSpellAnalysisWujieRouter, Request: cReq}
+	if err = c.beforeCall(ctx, call); err != nil {
+		return ErrorWujieCode, result, fmt.Errorf("c.beforeCall: %w", err)
+	}
+	defer func() { c.afterCall(ctx, call, code, result, err) }()
+
 	resp, err := c.Client.CreateSpellAnalysis(ctx, cReq)
 	if err != nil {
 		return ErrorWujieCode, "", fmt.Errorf("c.Client.CreateSpellAnalysis: %w", err)
@@ -651,8 +844,8 @@ func (c *Caller) CreateSpellAnalysis(ctx context.Context, cReq *CreateSpellAnaly
 	if err := json.NewDecoder(resp.Body).Decode(&cResp); err != nil {
 		return ErrorWujieCode, "", fmt.Errorf("json.NewDecoder: %w", err)
 	}
-	code := WujieCode(cResp.Code)
-	if err := code.Err(); err != nil {
+	code = WujieCode(cResp.Code)
+	if err := c.codeErr(ctx, code, cResp.Message); err != nil {
 		return code, "", fmt.Errorf("TRACE_ID: %s, WujieCode: %w, Message: %s, CreateSpellAnalysisRequest: %s",
 			getTraceID(resp), err, cResp.Message, cReq.String())
 	}
@@ -660,7 +853,13 @@ func (c *Caller) CreateSpellAnalysis(ctx context.Context, cReq *CreateSpellAnaly
 }
 
 // SpellAnalysisInfo get spell analysis info
-func (c *Caller) SpellAnalysisInfo(ctx context.Context, key string) (WujieCode, *SpellAnalysisInfo, error) {
+func (c *Caller) SpellAnalysisInfo(ctx context.Context, key string) (code WujieCode, result *SpellAnalysisInfo, err error) {
+	call := &Call{Name: "SpellAnalysisInfo", Router: SpellAnalysisInfoWujieRouter, Request: key}
+	if err = c.beforeCall(ctx, call); err != nil {
+		return ErrorWujieCode, result, fmt.Errorf("c.beforeCall: %w", err)
+	}
+	defer func() { c.afterCall(ctx, call, code, result, err) }()
+
 	resp, err := c.Client.SpellAnalysisInfo(ctx, key)
 	if err != nil {
 		return ErrorWujieCode, nil, fmt.Errorf("c.Client.SpellAnalysisInfo: %w", err)
@@ -671,8 +870,8 @@ func (c *Caller) SpellAnalysisInfo(ctx context.Context, key string) (WujieCode,
 	if err := json.NewDecoder(resp.Body).Decode(&sResp); err != nil {
 		return ErrorWujieCode, nil, fmt.Errorf("json.NewDecoder: %w", err)
 	}
-	code := WujieCode(sResp.Code)
-	if err := code.Err(); err != nil {
+	code = WujieCode(sResp.Code)
+	if err := c.codeErr(ctx, code, sResp.Message); err != nil {
 		return code, nil, fmt.Errorf("TRACE_ID: %s, WujieCode: %w, Message: %s, key: %s",
 			getTraceID(resp), err, sResp.Message, key)
 	}
@@ -680,7 +879,13 @@ func (c *Caller) SpellAnalysisInfo(ctx context.Context, key string) (WujieCode,
 }
 
 // MagicDiceTheme get magic dice theme
-func (c *Caller) MagicDiceTheme(ctx context.Context) (WujieCode, []MagicDiceTheme, error) {
+func (c *Caller) MagicDiceTheme(ctx context.Context) (code WujieCode, result []MagicDiceTheme, err error) {
+	call := &Call{Name: "MagicDiceTheme", Router: MagicDiceThemeWujieRouter}
+	if err = c.beforeCall(ctx, call); err != nil {
+		return ErrorWujieCode, result, fmt.Errorf("c.beforeCall: %w", err)
+	}
+	defer func() { c.afterCall(ctx, call, code, result, err) }()
+
 	resp, err := c.Client.MagicDiceTheme(ctx)
 	if err != nil {
 		return ErrorWujieCode, nil, fmt.Errorf("c.Client.MagicDiceTheme: %w", err)
@@ -691,15 +896,21 @@ func (c *Caller) MagicDiceTheme(ctx context.Context) (WujieCode, []MagicDiceThem
 	if err := json.NewDecoder(resp.Body).Decode(&mResp); err != nil {
 		return ErrorWujieCode, nil, fmt.Errorf("json.NewDecoder: %w", err)
 	}
-	code := WujieCode(mResp.Code)
-	if err := code.Err(); err != nil {
+	code = WujieCode(mResp.Code)
+	if err := c.codeErr(ctx, code, mResp.Message); err != nil {
 		return code, nil, fmt.Errorf("TRACE_ID: %s, WujieCode: %w, Message: %s", getTraceID(resp), err, mResp.Message)
 	}
 	return code, mResp.Data, nil
 }
 
 // CreateMagicDice create magic dice
-func (c *Caller) CreateMagicDice(ctx context.Context, cReq *CreateMagicDiceRequest) (WujieCode, *CreateMagicDiceResult, error) {
+func (c *Caller) CreateMagicDice(ctx context.Context, cReq *CreateMagicDiceRequest) (code WujieCode, result *CreateMagicDiceResult, err error) {
+	call := &Call{Name: "CreateMagicDice", Router: CreateMagicDiceWujieRouter, Request: cReq}
+	if err = c.beforeCall(ctx, call); err != nil {
+		return ErrorWujieCode, result, fmt.Errorf("c.beforeCall: %w", err)
+	}
+	defer func() { c.afterCall(ctx, call, code, result, err) }()
+
 	resp, err := c.Client.CreateMagicDice(ctx, cReq)
 	if err != nil {
 		return ErrorWujieCode, nil, fmt.Errorf("c.Client.CreateMagicDice: %w", err)
@@ -710,8 +921,8 @@ func (c *Caller) CreateMagicDice(ctx context.Context, cReq *CreateMagicDiceReque
 	if err := json.NewDecoder(resp.Body).Decode(&cResp); err != nil {
 		return ErrorWujieCode, nil, fmt.Errorf("json.NewDecoder: %w", err)
 	}
-	code := WujieCode(cResp.Code)
-	if err := code.Err(); err != nil {
+	code = WujieCode(cResp.Code)
+	if err := c.codeErr(ctx, code, cResp.Message); err != nil {
 		return code, nil, fmt.Errorf("TRACE_ID: %s, WujieCode: %w, Message: %s, CreateMagicDiceRequest: %s",
 			getTraceID(resp), err, cResp.Message, cReq.String())
 	}
@@ -719,7 +930,13 @@ func (c *Caller) CreateMagicDice(ctx context.Context, cReq *CreateMagicDiceReque
 }
 
 // CreateVideo create video
-func (c *Caller) CreateVideo(ctx context.Context, cReq *CreateVideoRequest) (WujieCode, string, error) {
+func (c *Caller) CreateVideo(ctx context.Context, cReq *CreateVideoRequest) (code WujieCode, result string, err error) {
+	call := &Call{Name: "CreateVideo", Router: CreateVideoWujieRouter, Request: cReq}
+	if err = c.beforeCall(ctx, call); err != nil {
+		return ErrorWujieCode, result, fmt.Errorf("c.beforeCall: %w", err)
+	}
+	defer func() { c.afterCall(ctx, call, code, result, err) }()
+
 	resp, err := c.Client.CreateVideo(ctx, cReq)
 	if err != nil {
 		return ErrorWujieCode, "", fmt.Errorf("c.Client.CreateVideo: %w", err)
@@ -730,8 +947,8 @@ func (c *Caller) CreateVideo(ctx context.Context, cReq *CreateVideoRequest) (Wuj
 	if err := json.NewDecoder(resp.Body).Decode(&cResp); err != nil {
 		return ErrorWujieCode, "", fmt.Errorf("json.NewDecoder: %w", err)
 	}
-	code := WujieCode(cResp.Code)
-	if err := code.Err(); err != nil {
+	code = WujieCode(cResp.Code)
+	if err := c.codeErr(ctx, code, cResp.Message); err != nil {
 		return code, "", fmt.Errorf("TRACE_ID: %s, WujieCode: %w, Message: %s, CreateVideoRequest: %s",
 			getTraceID(resp), err, cResp.Message, cReq.String())
 	}
@@ -739,7 +956,13 @@ func (c *Caller) CreateVideo(ctx context.Context, cReq *CreateVideoRequest) (Wuj
 }
 
 // VideoInfo get video info
-func (c *Caller) VideoInfo(ctx context.Context, key string) (WujieCode, *VideoInfo, error) {
+func (c *Caller) VideoInfo(ctx context.Context, key string) (code WujieCode, result *VideoInfo, err error) {
+	call := &Call{Name: "VideoInfo", Router: VideoInfoWujieRouter, Request: key}
+	if err = c.beforeCall(ctx, call); err != nil {
+		return ErrorWujieCode, result, fmt.Errorf("c.beforeCall: %w", err)
+	}
+	defer func() { c.afterCall(ctx, call, code, result, err) }()
+
 	resp, err := c.Client.VideoInfo(ctx, key)
 	if err != nil {
 		return ErrorWujieCode, nil, fmt.Errorf("c.Client.VideoInfo: %w", err)
@@ -750,8 +973,8 @@ func (c *Caller) VideoInfo(ctx context.Context, key string) (WujieCode, *VideoIn
 	if err := json.NewDecoder(resp.Body).Decode(&vResp); err != nil {
 		return ErrorWujieCode, nil, fmt.Errorf("json.NewDecoder: %w", err)
 	}
-	code := WujieCode(vResp.Code)
-	if err := code.Err(); err != nil {
+	code = WujieCode(vResp.Code)
+	if err := c.codeErr(ctx, code, vResp.Message); err != nil {
 		return code, nil, fmt.Errorf("TRACE_ID: %s, WujieCode: %w, Message: %s, key: %s",
 			getTraceID(resp), err, vResp.Message, key)
 	}
@@ -759,7 +982,13 @@ func (c *Caller) VideoInfo(ctx context.Context, key string) (WujieCode, *VideoIn
 }
 
 // VideoOptionMenuAndPriceTable get video option menu and price table
-func (c *Caller) VideoOptionMenuAndPriceTable(ctx context.Context) (WujieCode, *VideoOptionMenuAndPriceTable, error) {
+func (c *Caller) VideoOptionMenuAndPriceTable(ctx context.Context) (code WujieCode, result *VideoOptionMenuAndPriceTable, err error) {
+	call := &Call{Name: "VideoOptionMenuAndPriceTable", Router: VideoOptionMenuAndPriceTableWujieRouter}
+	if err = c.beforeCall(ctx, call); err != nil {
+		return ErrorWujieCode, result, fmt.Errorf("c.beforeCall: %w", err)
+	}
+	defer func() { c.afterCall(ctx, call, code, result, err) }()
+
 	resp, err := c.Client.VideoOptionMenuAndPriceTable(ctx)
 	if err != nil {
 		return ErrorWujieCode, nil, fmt.Errorf("c.Client.VideoOptionMenuAndPriceTable: %w", err)
@@ -770,8 +999,8 @@ func (c *Caller) VideoOptionMenuAndPriceTable(ctx context.Context) (WujieCode, *
 	if err := json.NewDecoder(resp.Body).Decode(&vResp); err != nil {
 		return ErrorWujieCode, nil, fmt.Errorf("json.NewDecoder: %w", err)
 	}
-	code := WujieCode(vResp.Code)
-	if err := code.Err(); err != nil {
+	code = WujieCode(vResp.Code)
+	if err := c.codeErr(ctx, code, vResp.Message); err != nil {
 		return code, nil, fmt.Errorf("TRACE_ID: %s, WujieCode: %w, Message: %s",
 			getTraceID(resp), err, vResp.Message)
 	}
@@ -779,7 +1008,13 @@ func (c *Caller) VideoOptionMenuAndPriceTable(ctx context.Context) (WujieCode, *
 }
 
 // VideoModelQueueInfo get video queue info
-func (c *Caller) VideoModelQueueInfo(ctx context.Context, model int32) (WujieCode, *VideoModelQueueInfo, error) {
+func (c *Caller) VideoModelQueueInfo(ctx context.Context, model ModelCode) (code WujieCode, result *VideoModelQueueInfo, err error) {
+	call := &Call{Name: "VideoModelQueueInfo", Router: VideoModelQueueInfoWujieRouter, Request: model}
+	if err = c.beforeCall(ctx, call); err != nil {
+		return ErrorWujieCode, result, fmt.Errorf("c.beforeCall: %w", err)
+	}
+	defer func() { c.afterCall(ctx, call, code, result, err) }()
+
 	resp, err := c.Client.VideoModelQueueInfo(ctx, model)
 	if err != nil {
 		return ErrorWujieCode, nil, fmt.Errorf("c.Client.VideoModelQueueInfo: %w", err)
@@ -790,8 +1025,8 @@ func (c *Caller) VideoModelQueueInfo(ctx context.Context, model int32) (WujieCod
 	if err := json.NewDecoder(resp.Body).Decode(&vResp); err != nil {
 		return ErrorWujieCode, nil, fmt.Errorf("json.NewDecoder: %w", err)
 	}
-	code := WujieCode(vResp.Code)
-	if err := code.Err(); err != nil {
+	code = WujieCode(vResp.Code)
+	if err := c.codeErr(ctx, code, vResp.Message); err != nil {
 		return code, nil, fmt.Errorf("TRACE_ID: %s, WujieCode: %w, Message: %s",
 			getTraceID(resp), err, vResp.Message)
 	}
@@ -799,7 +1034,13 @@ func (c *Caller) VideoModelQueueInfo(ctx context.Context, model int32) (WujieCod
 }
 
 // VideoGeneratingInfo get video generating info
-func (c *Caller) VideoGeneratingInfo(ctx context.Context, keys []string) (WujieCode, *VideoGeneratingInfo, error) {
+func (c *Caller) VideoGeneratingInfo(ctx context.Context, keys []string) (code WujieCode, result *VideoGeneratingInfo, err error) {
+	call := &Call{Name: "VideoGeneratingInfo", Router: VideoGeneratingInfoWujieRouter, Request: keys}
+	if err = c.beforeCall(ctx, call); err != nil {
+		return ErrorWujieCode, result, fmt.Errorf("c.beforeCall: %w", err)
+	}
+	defer func() { c.afterCall(ctx, call, code, result, err) }()
+
 	resp, err := c.Client.VideoGeneratingInfo(ctx, keys)
 	if err != nil {
 		return ErrorWujieCode, nil, fmt.Errorf("c.Client.VideoGeneratingInfo: %w", err)
@@ -810,8 +1051,8 @@ func (c *Caller) VideoGeneratingInfo(ctx context.Context, keys []string) (WujieC
 	if err := json.NewDecoder(resp.Body).Decode(&vResp); err != nil {
 		return ErrorWujieCode, nil, fmt.Errorf("json.NewDecoder: %w", err)
 	}
-	code := WujieCode(vResp.Code)
-	if err := code.Err(); err != nil {
+	code = WujieCode(vResp.Code)
+	if err := c.codeErr(ctx, code, vResp.Message); err != nil {
 		return code, nil, fmt.Errorf("TRACE_ID: %s, WujieCode: %w, Message: %s",
 			getTraceID(resp), err, vResp.Message)
 	}
@@ -819,7 +1060,13 @@ func (c *Caller) VideoGeneratingInfo(ctx context.Context, keys []string) (WujieC
 }
 
 // CameraTemplateOptions get camera template options
-func (c *Caller) CameraTemplateOptions(ctx context.Context) (WujieCode, []CameraTemplateOption, error) {
+func (c *Caller) CameraTemplateOptions(ctx context.Context) (code WujieCode, result []CameraTemplateOption, err error) {
+	call := &Call{Name: "CameraTemplateOptions", Router: CameraTemplateOptionsWujieRouter}
+	if err = c.beforeCall(ctx, call); err != nil {
+		return ErrorWujieCode, result, fmt.Errorf("c.beforeCall: %w", err)
+	}
+	defer func() { c.afterCall(ctx, call, code, result, err) }()
+
 	resp, err := c.Client.CameraTemplateOptions(ctx)
 	if err != nil {
 		return ErrorWujieCode, nil, fmt.Errorf("c.Client.CameraTemplateOptions: %w", err)
@@ -831,8 +1078,8 @@ func (c *Caller) CameraTemplateOptions(ctx context.Context) (WujieCode, []Camera
 		return ErrorWujieCode, nil, fmt.Errorf("json.NewDecoder: %w", err)
 	}
 
-	code := WujieCode(cResp.Code)
-	if err := code.Err(); err != nil {
+	code = WujieCode(cResp.Code)
+	if err := c.codeErr(ctx, code, cResp.Message); err != nil {
 		return code, nil, fmt.Errorf("TRACE_ID: %s, WujieCode: %w, Message: %s",
 			getTraceID(resp), err, cResp.Message)
 	}
@@ -840,7 +1087,13 @@ func (c *Caller) CameraTemplateOptions(ctx context.Context) (WujieCode, []Camera
 }
 
 // CreateCamera create camera
-func (c *Caller) CreateCamera(ctx context.Context, cReq *CreateCameraRequest) (WujieCode, *CreateCameraResult, error) {
+func (c *Caller) CreateCamera(ctx context.Context, cReq *CreateCameraRequest) (code WujieCode, result *CreateCameraResult, err error) {
+	call := &Call{Name: "CreateCamera", Router: CreateCameraWujieRouter, Request: cReq}
+	if err = c.beforeCall(ctx, call); err != nil {
+		return ErrorWujieCode, result, fmt.Errorf("c.beforeCall: %w", err)
+	}
+	defer func() { c.afterCall(ctx, call, code, result, err) }()
+
 	resp, err := c.Client.CreateCamera(ctx, cReq)
 	if err != nil {
 		return ErrorWujieCode, nil, fmt.Errorf("c.Client.CreateCamera: %w", err)
@@ -852,8 +1105,8 @@ func (c *Caller) CreateCamera(ctx context.Context, cReq *CreateCameraRequest) (W
 		return ErrorWujieCode, nil, fmt.Errorf("json.NewDecoder: %w", err)
 	}
 
-	code := WujieCode(cResp.Code)
-	if err := code.Err(); err != nil {
+	code = WujieCode(cResp.Code)
+	if err := c.codeErr(ctx, code, cResp.Message); err != nil {
 		return code, nil, fmt.Errorf("TRACE_ID: %s, WujieCode: %w, Message: %s, CreateCameraRequest: %s",
 			getTraceID(resp), err, cResp.Message, cReq.String())
 	}
@@ -861,7 +1114,13 @@ func (c *Caller) CreateCamera(ctx context.Context, cReq *CreateCameraRequest) (W
 }
 
 // CameraGeneratingInfo get camera generating info
-func (c *Caller) CameraGeneratingInfo(ctx context.Context, keys []string) (WujieCode, []CameraGeneratingInfo, error) {
+func (c *Caller) CameraGeneratingInfo(ctx context.Context, keys []string) (code WujieCode, result []CameraGeneratingInfo, err error) {
+	call := &Call{Name: "CameraGeneratingInfo", Router: CameraGeneratingInfoWujieRouter, Request: keys}
+	if err = c.beforeCall(ctx, call); err != nil {
+		return ErrorWujieCode, result, fmt.Errorf("c.beforeCall: %w", err)
+	}
+	defer func() { c.afterCall(ctx, call, code, result, err) }()
+
 	resp, err := c.Client.CameraGeneratingInfo(ctx, keys)
 	if err != nil {
 		return ErrorWujieCode, nil, fmt.Errorf("c.Client.CameraGeneratingInfo: %w", err)
@@ -873,8 +1132,8 @@ func (c *Caller) CameraGeneratingInfo(ctx context.Context, keys []string) (Wujie
 		return ErrorWujieCode, nil, fmt.Errorf("json.NewDecoder: %w", err)
 	}
 
-	code := WujieCode(cResp.Code)
-	if err := code.Err(); err != nil {
+	code = WujieCode(cResp.Code)
+	if err := c.codeErr(ctx, code, cResp.Message); err != nil {
 		return code, nil, fmt.Errorf("TRACE_ID: %s, WujieCode: %w, Message: %s",
 			getTraceID(resp), err, cResp.Message)
 	}
@@ -882,7 +1141,13 @@ func (c *Caller) CameraGeneratingInfo(ctx context.Context, keys []string) (Wujie
 }
 
 // CameraInfo get camera info
-func (c *Caller) CameraInfo(ctx context.Context, key string) (WujieCode, *CameraInfo, error) {
+func (c *Caller) CameraInfo(ctx context.Context, key string) (code WujieCode, result *CameraInfo, err error) {
+	call := &Call{Name: "CameraInfo", Router: CameraInfoWujieRouter, Request: key}
+	if err = c.beforeCall(ctx, call); err != nil {
+		return ErrorWujieCode, result, fmt.Errorf("c.beforeCall: %w", err)
+	}
+	defer func() { c.afterCall(ctx, call, code, result, err) }()
+
 	resp, err := c.Client.CameraInfo(ctx, key)
 	if err != nil {
 		return ErrorWujieCode, nil, fmt.Errorf("c.Client.CameraInfo: %w", err)
@@ -894,8 +1159,8 @@ func (c *Caller) CameraInfo(ctx context.Context, key string) (WujieCode, *Camera
 		return ErrorWujieCode, nil, fmt.Errorf("json.NewDecoder: %w", err)
 	}
 
-	code := WujieCode(cResp.Code)
-	if err := code.Err(); err != nil {
+	code = WujieCode(cResp.Code)
+	if err := c.codeErr(ctx, code, cResp.Message); err != nil {
 		return code, nil, fmt.Errorf("TRACE_ID: %s, WujieCode: %w, Message: %s, key: %s",
 			getTraceID(resp), err, cResp.Message, key)
 	}
@@ -903,7 +1168,13 @@ func (c *Caller) CameraInfo(ctx context.Context, key string) (WujieCode, *Camera
 }
 
 // LabOptions get lab options
-func (c *Caller) LabOptions(ctx context.Context, lReq *LabOptionsRequest) (WujieCode, []LabOption, error) {
+func (c *Caller) LabOptions(ctx context.Context, lReq *LabOptionsRequest) (code WujieCode, result []LabOption, err error) {
+	call := &Call{Name: "LabOptions", Router: LabOptionsWujieRouter, Request: lReq}
+	if err = c.beforeCall(ctx, call); err != nil {
+		return ErrorWujieCode, result, fmt.Errorf("c.beforeCall: %w", err)
+	}
+	defer func() { c.afterCall(ctx, call, code, result, err) }()
+
 	resp, err := c.Client.LabOptions(ctx, lReq)
 	if err != nil {
 		return ErrorWujieCode, nil, fmt.Errorf("c.Client.LabOptions: %w", err)
@@ -915,8 +1186,8 @@ func (c *Caller) LabOptions(ctx context.Context, lReq *LabOptionsRequest) (Wujie
 		return ErrorWujieCode, nil, fmt.Errorf("json.NewDecoder: %w", err)
 	}
 
-	code := WujieCode(lResp.Code)
-	if err := code.Err(); err != nil {
+	code = WujieCode(lResp.Code)
+	if err := c.codeErr(ctx, code, lResp.Message); err != nil {
 		return code, nil, fmt.Errorf("TRACE_ID: %s, WujieCode: %w, Message: %s",
 			getTraceID(resp), err, lResp.Message)
 	}
@@ -924,7 +1195,13 @@ func (c *Caller) LabOptions(ctx context.Context, lReq *LabOptionsRequest) (Wujie
 }
 
 // LabInfo get lab info
-func (c *Caller) LabInfo(ctx context.Context, lReq *LabInfoRequest) (WujieCode, *LabInfo, error) {
+func (c *Caller) LabInfo(ctx context.Context, lReq *LabInfoRequest) (code WujieCode, result *LabInfo, err error) {
+	call := &Call{Name: "LabInfo", Router: LabInfoWujieRouter, Request: lReq}
+	if err = c.beforeCall(ctx, call); err != nil {
+		return ErrorWujieCode, result, fmt.Errorf("c.beforeCall: %w", err)
+	}
+	defer func() { c.afterCall(ctx, call, code, result, err) }()
+
 	resp, err := c.Client.LabInfo(ctx, lReq)
 	if err != nil {
 		return ErrorWujieCode, nil, fmt.Errorf("c.Client.LabInfo: %w", err)
@@ -936,8 +1213,8 @@ func (c *Caller) LabInfo(ctx context.Context, lReq *LabInfoRequest) (WujieCode,
 		return ErrorWujieCode, nil, fmt.Errorf("json.NewDecoder: %w", err)
 	}
 
-	code := WujieCode(lResp.Code)
-	if err := code.Err(); err != nil {
+	code = WujieCode(lResp.Code)
+	if err := c.codeErr(ctx, code, lResp.Message); err != nil {
 		return code, nil, fmt.Errorf("TRACE_ID: %s, WujieCode: %w, Message: %s",
 			getTraceID(resp), err, lResp.Message)
 	}
@@ -945,7 +1222,13 @@ func (c *Caller) LabInfo(ctx context.Context, lReq *LabInfoRequest) (WujieCode,
 }
 
 // CreateSegmentation create segmentation
-func (c *Caller) CreateSegmentation(ctx context.Context, cReq *CreateSegmentationRequest) (WujieCode, *CreateSegmentationResult, error) {
+func (c *Caller) CreateSegmentation(ctx context.Context, cReq *CreateSegmentationRequest) (code WujieCode, result *CreateSegmentationResult, err error) {
+	call := &Call{Name: "CreateSegmentation", Router: CreateSegmentationWujieRouter, Request: cReq}
+	if err = c.beforeCall(ctx, call); err != nil {
+		return ErrorWujieCode, result, fmt.Errorf("c.beforeCall: %w", err)
+	}
+	defer func() { c.afterCall(ctx, call, code, result, err) }()
+
 	resp, err := c.Client.CreateSegmentation(ctx, cReq)
 	if err != nil {
 		return ErrorWujieCode, nil, fmt.Errorf("c.Client.CreateSegmentation: %w", err)
@@ -957,8 +1240,8 @@ func (c *Caller) CreateSegmentation(ctx context.Context, cReq *CreateSegmentatio
 		return ErrorWujieCode, nil, fmt.Errorf("json.NewDecoder: %w", err)
 	}
 
-	code := WujieCode(cResp.Code)
-	if err := code.Err(); err != nil {
+	code = WujieCode(cResp.Code)
+	if err := c.codeErr(ctx, code, cResp.Message); err != nil {
 		return code, nil, fmt.Errorf("TRACE_ID: %s, WujieCode: %w, Message: %s, CreateSegmentationRequest: %s",
 			getTraceID(resp), err, cResp.Message, cReq.String())
 	}
@@ -966,7 +1249,13 @@ func (c *Caller) CreateSegmentation(ctx context.Context, cReq *CreateSegmentatio
 }
 
 // CreateInfiniteZoom create infinite zoom
-func (c *Caller) CreateInfiniteZoom(ctx context.Context, cReq *CreateInfiniteZoomRequest) (WujieCode, *CreateInfiniteZoomResult, error) {
+func (c *Caller) CreateInfiniteZoom(ctx context.Context, cReq *CreateInfiniteZoomRequest) (code WujieCode, result *CreateInfiniteZoomResult, err error) {
+	call := &Call{Name: "CreateInfiniteZoom", Router: CreateInfiniteZoomWujieRouter, Request: cReq}
+	if err = c.beforeCall(ctx, call); err != nil {
+		return ErrorWujieCode, result, fmt.Errorf("c.beforeCall: %w", err)
+	}
+	defer func() { c.afterCall(ctx, call, code, result, err) }()
+
 	resp, err := c.Client.CreateInfiniteZoom(ctx, cReq)
 	if err != nil {
 		return ErrorWujieCode, nil, fmt.Errorf("c.Client.CreateInfiniteZoom: %w", err)
@@ -978,8 +1267,8 @@ func (c *Caller) CreateInfiniteZoom(ctx context.Context, cReq *CreateInfiniteZoo
 		return ErrorWujieCode, nil, fmt.Errorf("json.NewDecoder: %w", err)
 	}
 
-	code := WujieCode(cResp.Code)
-	if err := code.Err(); err != nil {
+	code = WujieCode(cResp.Code)
+	if err := c.codeErr(ctx, code, cResp.Message); err != nil {
 		return code, nil, fmt.Errorf("TRACE_ID: %s, WujieCode: %w, Message: %s, CreateInfiniteZoomRequest: %s",
 			getTraceID(resp), err, cResp.Message, cReq.String())
 	}
@@ -987,7 +1276,13 @@ func (c *Caller) CreateInfiniteZoom(ctx context.Context, cReq *CreateInfiniteZoo
 }
 
 // CreateVectorStudio create vector studio
-func (c *Caller) CreateVectorStudio(ctx context.Context, cReq *CreateVectorStudioRequest) (WujieCode, *CreateVectorStudioResult, error) {
+func (c *Caller) CreateVectorStudio(ctx context.Context, cReq *CreateVectorStudioRequest) (code WujieCode, result *CreateVectorStudioResult, err error) {
+	call := &Call{Name: "CreateVectorStudio", Router: CreateVectorStudioWujieRouter, Request: cReq}
+	if err = c.beforeCall(ctx, call); err != nil {
+		return ErrorWujieCode, result, fmt.Errorf("c.beforeCall: %w", err)
+	}
+	defer func() { c.afterCall(ctx, call, code, result, err) }()
+
 	resp, err := c.Client.CreateVectorStudio(ctx, cReq)
 	if err != nil {
 		return ErrorWujieCode, nil, fmt.Errorf("c.Client.CreateVectorStudio: %w", err)
@@ -999,8 +1294,8 @@ func (c *Caller) CreateVectorStudio(ctx context.Context, cReq *CreateVectorStudi
 		return ErrorWujieCode, nil, fmt.Errorf("json.NewDecoder: %w", err)
 	}
 
-	code := WujieCode(cResp.Code)
-	if err := code.Err(); err != nil {
+	code = WujieCode(cResp.Code)
+	if err := c.codeErr(ctx, code, cResp.Message); err != nil {
 		return code, nil, fmt.Errorf("TRACE_ID: %s, WujieCode: %w, Message: %s, CreateVectorStudioRequest: %s",
 			getTraceID(resp), err, cResp.Message, cReq.String())
 	}
@@ -1008,7 +1303,13 @@ func (c *Caller) CreateVectorStudio(ctx context.Context, cReq *CreateVectorStudi
 }
 
 // CreateSVD creates svd
-func (c *Caller) CreateSVD(ctx context.Context, cReq *CreateSVDRequest) (WujieCode, string, error) {
+func (c *Caller) CreateSVD(ctx context.Context, cReq *CreateSVDRequest) (code WujieCode, result string, err error) {
+	call := &Call{Name: "CreateSVD", Router: CreateSVDWujieRouter, Request: cReq}
+	if err = c.beforeCall(ctx, call); err != nil {
+		return ErrorWujieCode, result, fmt.Errorf("c.beforeCall: %w", err)
+	}
+	defer func() { c.afterCall(ctx, call, code, result, err) }()
+
 	resp, err := c.Client.CreateSVD(ctx, cReq)
 	if err != nil {
 		return ErrorWujieCode, "", fmt.Errorf("c.Client.CreateSVD: %w", err)
@@ -1020,8 +1321,8 @@ func (c *Caller) CreateSVD(ctx context.Context, cReq *CreateSVDRequest) (WujieCo
 		return ErrorWujieCode, "", fmt.Errorf("json.NewDecoder: %w", err)
 	}
 
-	code := WujieCode(cResp.Code)
-	if err := code.Err(); err != nil {
+	code = WujieCode(cResp.Code)
+	if err := c.codeErr(ctx, code, cResp.Message); err != nil {
 		return code, "", fmt.Errorf("TRACE_ID: %s, WujieCode: %w, Message: %s, CreateSVDRequest: %s",
 			getTraceID(resp), err, cResp.Message, cReq.String())
 	}
@@ -1029,7 +1330,13 @@ func (c *Caller) CreateSVD(ctx context.Context, cReq *CreateSVDRequest) (WujieCo
 }
 
 // SVDInfo get svd info
-func (c *Caller) SVDInfo(ctx context.Context, key string) (WujieCode, *SVDInfo, error) {
+func (c *Caller) SVDInfo(ctx context.Context, key string) (code WujieCode, result *SVDInfo, err error) {
+	call := &Call{Name: "SVDInfo", Router: SVDInfoWujieRouter, Request: key}
+	if err = c.beforeCall(ctx, call); err != nil {
+		return ErrorWujieCode, result, fmt.Errorf("c.beforeCall: %w", err)
+	}
+	defer func() { c.afterCall(ctx, call, code, result, err) }()
+
 	resp, err := c.Client.SVDInfo(ctx, key)
 	if err != nil {
 		return ErrorWujieCode, nil, fmt.Errorf("c.Client.SVDInfo: %w", err)
@@ -1041,8 +1348,8 @@ func (c *Caller) SVDInfo(ctx context.Context, key string) (WujieCode, *SVDInfo,
 		return ErrorWujieCode, nil, fmt.Errorf("json.NewDecoder: %w", err)
 	}
 
-	code := WujieCode(sResp.Code)
-	if err := code.Err(); err != nil {
+	code = WujieCode(sResp.Code)
+	if err := c.codeErr(ctx, code, sResp.Message); err != nil {
 		return code, nil, fmt.Errorf("TRACE_ID: %s, WujieCode: %w, Message: %s, key: %s",
 			getTraceID(resp), err, sResp.Message, key)
 	}
@@ -1050,7 +1357,13 @@ func (c *Caller) SVDInfo(ctx context.Context, key string) (WujieCode, *SVDInfo,
 }
 
 // CreateMidjourney create midjourney image
-func (c *Caller) CreateMidjourney(ctx context.Context, cReq *CreateMidjourneyRequest) (WujieCode, *CreateMidjourneyResponse, error) {
+func (c *Caller) CreateMidjourney(ctx context.Context, cReq *CreateMidjourneyRequest) (code WujieCode, result *CreateMidjourneyResponse, err error) {
+	call := &Call{Name: "CreateMidjourney", Router: CreateMidjourneyWujieRouter, Request: cReq}
+	if err = c.beforeCall(ctx, call); err != nil {
+		return ErrorWujieCode, result, fmt.Errorf("c.beforeCall: %w", err)
+	}
+	defer func() { c.afterCall(ctx, call, code, result, err) }()
+
 	resp, err := c.Client.CreateMidjourney(ctx, cReq)
 	if err != nil {
 		return ErrorWujieCode, nil, fmt.Errorf("c.Client.CreateMidjourney: %w", err)
@@ -1062,8 +1375,8 @@ func (c *Caller) CreateMidjourney(ctx context.Context, cReq *CreateMidjourneyReq
 		return ErrorWujieCode, nil, fmt.Errorf("json.NewDecoder: %w", err)
 	}
 
-	code := WujieCode(cResp.Code)
-	if err := code.Err(); err != nil {
+	code = WujieCode(cResp.Code)
+	if err := c.codeErr(ctx, code, cResp.Message); err != nil {
 		return code, nil, fmt.Errorf("TRACE_ID: %s, WujieCode: %w, Message: %s, CreateMidjourneyRequest: %s",
 			getTraceID(resp), err, cResp.Message, cReq.String())
 	}
@@ -1071,7 +1384,13 @@ func (c *Caller) CreateMidjourney(ctx context.Context, cReq *CreateMidjourneyReq
 }
 
 // CreateFlux create flux image
-func (c *Caller) CreateFlux(ctx context.Context, cReq *CreateFluxRequest) (WujieCode, *CreateFluxResponse, error) {
+func (c *Caller) CreateFlux(ctx context.Context, cReq *CreateFluxRequest) (code WujieCode, result *CreateFluxResponse, err error) {
+	call := &Call{Name: "CreateFlux", Router: CreateFluxWujieRouter, Request: cReq}
+	if err = c.beforeCall(ctx, call); err != nil {
+		return ErrorWujieCode, result, fmt.Errorf("c.beforeCall: %w", err)
+	}
+	defer func() { c.afterCall(ctx, call, code, result, err) }()
+
 	resp, err := c.Client.CreateFlux(ctx, cReq)
 	if err != nil {
 		return ErrorWujieCode, nil, fmt.Errorf("c.Client.CreateFlux: %w", err)
@@ -1083,14 +1402,24 @@ func (c *Caller) CreateFlux(ctx context.Context, cReq *CreateFluxRequest) (Wujie
 		return ErrorWujieCode, nil, fmt.Errorf("json.NewDecoder: %w", err)
 	}
 
-	code := WujieCode(cResp.Code)
-	if err := code.Err(); err != nil {
+	code = WujieCode(cResp.Code)
+	if err := c.codeErr(ctx, code, cResp.Message); err != nil {
 		return code, nil, fmt.Errorf("TRACE_ID: %s, WujieCode: %w, Message: %s, CreateFluxRequest: %s",
 			getTraceID(resp), err, cResp.Message, cReq.String())
 	}
 	return code, &cResp, nil
 }
 
+// codeErr get the error of code in the language selected by ctx or Client,
+// message of the response is used for codes which have no message in the catalog
+func (c *Caller) codeErr(ctx context.Context, code WujieCode, message string) error {
+	catalog, lang := c.Client.messageCatalog(), c.Client.language(ctx)
+	if code != OKWujieCode && message != "" && !catalog.Has(code) {
+		return &WujieCodeError{Code: code, Language: lang, Message: message}
+	}
+	return code.ErrIn(catalog, lang)
+}
+
 func getTraceID(resp *http.Response) string {
 	return resp.Header.Get(TraceID)
 }
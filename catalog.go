@@ -0,0 +1,541 @@
+package wujiesdk
+
+// @Title        catalog.go
+// @Description  catalog of models, resources and options with lookup, search and capability queries
+// @Create       XdpCs 2026-10-19 16:05
+// @Update       XdpCs 2026-10-20 13:00
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ModelCode is the code of model, codes are numbered by each api, so codes of ModelBaseInfos, ModelBaseInfosPro,
+// video, lab and controlnet options are different models
+type ModelCode int
+
+// DefaultCatalogInterval is how often Catalog.Run refreshes the catalog
+const DefaultCatalogInterval = 30 * time.Minute
+
+// ParseControlNetSupport parse free-form controlnet_support of model base info
+func ParseControlNetSupport(s string) bool {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "1", "true", "yes", "y", "support", "supported", "是", "支持":
+		return true
+	}
+	return false
+}
+
+// CatalogModel is a model of ModelBaseInfos or ModelBaseInfosPro
+type CatalogModel struct {
+	Code       ModelCode
+	Type       int
+	Version    string
+	Desc       string
+	Pro        bool
+	ControlNet bool
+	Resource   *DefaultResourceModelData // nil for pro models or when DefaultResourceModel of the model is not available
+	// Menu is the option menu of the model, nil when it is not known, open api has no option menu of pro models,
+	// so menus of pro models are merged from non-pro models of the same version
+	Menu *CreateOptionMenu
+}
+
+// Capability get capability of the model for validating requests
+func (c *CatalogModel) Capability() *ModelCapability {
+	return &ModelCapability{ModelCode: c.Code, ModelVersion: c.Version, Resource: c.Resource}
+}
+
+// CatalogItemKind is the kind of searchable item
+type CatalogItemKind string
+
+const (
+	StyleCatalogItemKind       CatalogItemKind = "style"
+	ArtistCatalogItemKind      CatalogItemKind = "artist"
+	CharacterCatalogItemKind   CatalogItemKind = "character"
+	ModelFusionCatalogItemKind CatalogItemKind = "model_fusion"
+	StyleModelCatalogItemKind  CatalogItemKind = "style_model"
+	SpellCatalogItemKind       CatalogItemKind = "spell"
+)
+
+// CatalogItem is a searchable item of catalog, ModelCode is zero for spells
+type CatalogItem struct {
+	Kind      CatalogItemKind
+	Key       string
+	Name      string
+	EnName    string
+	Category  string
+	Url       string
+	ModelCode ModelCode
+}
+
+type catalogData struct {
+	loadedAt        time.Time
+	models          map[ModelCode]*CatalogModel
+	proModels       map[ModelCode]*CatalogModel
+	styleModels     []StyleModel
+	labOptions      map[LabOptionType][]LabOption
+	videoTable      *VideoOptionMenuAndPriceTable
+	cameraTemplates []CameraTemplateOption
+	spells          []QuerySpellData
+	items           []CatalogItem
+}
+
+// Catalog loads models, default resources, style models, lab options, video price table,
+// camera templates and spells by Caller, it is safe for concurrent use
+type Catalog struct {
+	Caller   *Caller
+	Interval time.Duration
+	Clock    Clock
+
+	mu   sync.RWMutex
+	data *catalogData
+}
+
+// NewCatalog new catalog, call Refresh or Run before lookup
+func NewCatalog(c *Caller) *Catalog {
+	return &Catalog{Caller: c, Interval: DefaultCatalogInterval, Clock: SystemClock{}}
+}
+
+// Run refresh catalog every Interval until ctx is done, refresh errors are logged
+func (c *Catalog) Run(ctx context.Context) error {
+	for {
+		if err := c.Refresh(ctx); err != nil {
+			c.Caller.Client.WriteLog(LogWarn, "Catalog: refresh error: %v\n", err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-c.Clock.After(c.Interval):
+		}
+	}
+}
+
+// LoadedAt get when the catalog was refreshed, zero if never
+func (c *Catalog) LoadedAt() time.Time {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.data == nil {
+		return time.Time{}
+	}
+	return c.data.loadedAt
+}
+
+// Refresh load everything again, the catalog is replaced only when all lists are loaded,
+// DefaultResourceModel of a single model is skipped with a warning when it fails
+func (c *Catalog) Refresh(ctx context.Context) error {
+	data := &catalogData{
+		models:     make(map[ModelCode]*CatalogModel),
+		proModels:  make(map[ModelCode]*CatalogModel),
+		labOptions: make(map[LabOptionType][]LabOption),
+	}
+	_, infos, err := c.Caller.ModelBaseInfos(ctx)
+	if err != nil {
+		return fmt.Errorf("c.Caller.ModelBaseInfos: %w", err)
+	}
+	for _, info := range infos {
+		data.models[info.ModelCode] = &CatalogModel{
+			Code:       info.ModelCode,
+			Type:       int(info.ModelType),
+			Version:    info.ModelVersion,
+			Desc:       info.ModelDesc,
+			ControlNet: ParseControlNetSupport(info.ControlNetSupport),
+		}
+	}
+	_, proInfos, err := c.Caller.ModelBaseInfosPro(ctx)
+	if err != nil {
+		return fmt.Errorf("c.Caller.ModelBaseInfosPro: %w", err)
+	}
+	for _, info := range proInfos {
+		data.proModels[info.ModelCode] = &CatalogModel{
+			Code:       info.ModelCode,
+			Type:       info.Type,
+			Version:    info.ModelVersion,
+			Desc:       info.ModelDesc,
+			Pro:        true,
+			ControlNet: ParseControlNetSupport(info.ControlnetSupport),
+		}
+	}
+	// DefaultResourceModel serves models of ModelBaseInfos only, pro models with the same code are other models
+	for code, model := range data.models {
+		if _, model.Resource, err = c.Caller.DefaultResourceModel(ctx, code); err != nil {
+			c.Caller.Client.WriteLog(LogWarn, "Catalog: model: %d, get default resource error: %v\n", code, err)
+		}
+		if model.Resource != nil {
+			model.Menu = &model.Resource.CreateOptionMenu
+		}
+	}
+	for _, model := range data.proModels {
+		model.Menu = data.proMenu(model.Version)
+	}
+	if _, data.styleModels, err = c.Caller.DefaultResourceStyleModel(ctx); err != nil {
+		return fmt.Errorf("c.Caller.DefaultResourceStyleModel: %w", err)
+	}
+	for _, optionType := range []LabOptionType{InfiniteZoomModelLabOptionType, InfiniteZoomSamplerLabOptionType,
+		SegmentAnythingModelLabOptionType, VectorStudioStyleLabOptionType} {
+		_, options, err := c.Caller.LabOptions(ctx, NewLabOptionsRequest(optionType))
+		if err != nil {
+			return fmt.Errorf("c.Caller.LabOptions: option type: %s, error: %w", optionType, err)
+		}
+		data.labOptions[optionType] = options
+	}
+	if _, data.videoTable, err = c.Caller.VideoOptionMenuAndPriceTable(ctx); err != nil {
+		return fmt.Errorf("c.Caller.VideoOptionMenuAndPriceTable: %w", err)
+	}
+	if _, data.cameraTemplates, err = c.Caller.CameraTemplateOptions(ctx); err != nil {
+		return fmt.Errorf("c.Caller.CameraTemplateOptions: %w", err)
+	}
+	if _, data.spells, err = c.Caller.QuerySpell(ctx); err != nil {
+		return fmt.Errorf("c.Caller.QuerySpell: %w", err)
+	}
+	data.items = data.buildItems()
+	data.loadedAt = c.now()
+	c.mu.Lock()
+	c.data = data
+	c.mu.Unlock()
+	return nil
+}
+
+// modelCodes get codes of non-pro models in order
+func (d *catalogData) modelCodes() []ModelCode {
+	codes := make([]ModelCode, 0, len(d.models))
+	for code := range d.models {
+		codes = append(codes, code)
+	}
+	sort.Slice(codes, func(i, j int) bool { return codes[i] < codes[j] })
+	return codes
+}
+
+// proMenu merge styles, artists, characters and model fusions of non-pro models of version,
+// nil if no non-pro model of version has a menu
+func (d *catalogData) proMenu(version string) *CreateOptionMenu {
+	var menu *CreateOptionMenu
+	for _, code := range d.modelCodes() {
+		m := d.models[code]
+		if m.Menu == nil || !strings.EqualFold(m.Version, version) {
+			continue
+		}
+		if menu == nil {
+			menu = &CreateOptionMenu{}
+		}
+		menu.Style = mergeResourceOptions(menu.Style, m.Menu.Style)
+		menu.Artist = mergeResourceOptions(menu.Artist, m.Menu.Artist)
+		menu.Character = mergeFusionOptions(menu.Character, m.Menu.Character)
+		menu.ModelFusion = mergeFusionOptions(menu.ModelFusion, m.Menu.ModelFusion)
+	}
+	return menu
+}
+
+func mergeResourceOptions(options, more []ResourceOption) []ResourceOption {
+	for _, o := range more {
+		found := false
+		for _, existing := range options {
+			if existing.Name == o.Name {
+				found = true
+				break
+			}
+		}
+		if !found {
+			options = append(options, o)
+		}
+	}
+	return options
+}
+
+func mergeFusionOptions(options, more []FusionOption) []FusionOption {
+	for _, o := range more {
+		found := false
+		for _, existing := range options {
+			if existing.Key == o.Key {
+				found = true
+				break
+			}
+		}
+		if !found {
+			options = append(options, o)
+		}
+	}
+	return options
+}
+
+func (d *catalogData) buildItems() []CatalogItem {
+	var items []CatalogItem
+	for _, code := range d.modelCodes() {
+		resource := d.models[code].Resource
+		if resource == nil {
+			continue
+		}
+		menu := resource.CreateOptionMenu
+		for _, s := range menu.Style {
+			items = append(items, CatalogItem{Kind: StyleCatalogItemKind, Name: s.Name, Category: s.Category, Url: s.Url, ModelCode: code})
+		}
+		for _, a := range menu.Artist {
+			items = append(items, CatalogItem{Kind: ArtistCatalogItemKind, Name: a.Name, Category: a.Category, Url: a.Url, ModelCode: code})
+		}
+		for _, f := range menu.Character {
+			items = append(items, CatalogItem{Kind: CharacterCatalogItemKind, Key: f.Key, Name: f.Name, Category: f.Category, ModelCode: code})
+		}
+		for _, f := range menu.ModelFusion {
+			items = append(items, CatalogItem{Kind: ModelFusionCatalogItemKind, Key: f.Key, Name: f.Name, Category: f.Category, ModelCode: code})
+		}
+	}
+	for _, s := range d.styleModels {
+		items = append(items, CatalogItem{Kind: StyleModelCatalogItemKind, Key: s.Key, Name: s.Name, Url: s.SampleImageURL, ModelCode: s.ModelCode})
+	}
+	for _, s := range d.spells {
+		items = append(items, CatalogItem{Kind: SpellCatalogItemKind, Name: s.SpellName, EnName: s.SpellEnName, Category: s.Category, Url: s.Icon})
+	}
+	return items
+}
+
+func (c *Catalog) now() time.Time {
+	if c.Clock == nil {
+		return time.Now()
+	}
+	return c.Clock.Now()
+}
+
+func (c *Catalog) snapshot() *catalogData {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.data == nil {
+		return &catalogData{}
+	}
+	return c.data
+}
+
+// Model get model of ModelBaseInfos by code
+func (c *Catalog) Model(code ModelCode) (*CatalogModel, bool) {
+	m, ok := c.snapshot().models[code]
+	return m, ok
+}
+
+// ProModel get model of ModelBaseInfosPro by code
+func (c *Catalog) ProModel(code ModelCode) (*CatalogModel, bool) {
+	m, ok := c.snapshot().proModels[code]
+	return m, ok
+}
+
+// ModelByName get model whose version or desc equals name ignoring case, pro selects pro models,
+// the model of the lowest code is returned when several models match
+func (c *Catalog) ModelByName(name string, pro bool) (*CatalogModel, bool) {
+	for _, m := range c.Models(pro) {
+		if strings.EqualFold(m.Version, name) || strings.EqualFold(m.Desc, name) {
+			return m, true
+		}
+	}
+	return nil, false
+}
+
+// Models get models sorted by code, pro selects pro models
+func (c *Catalog) Models(pro bool) []*CatalogModel {
+	models := c.snapshot().models
+	if pro {
+		models = c.snapshot().proModels
+	}
+	result := make([]*CatalogModel, 0, len(models))
+	for _, m := range models {
+		result = append(result, m)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Code < result[j].Code })
+	return result
+}
+
+// StyleModel get style model by key or name
+func (c *Catalog) StyleModel(keyOrName string) (*StyleModel, bool) {
+	styleModels := c.snapshot().styleModels
+	for i := range styleModels {
+		if styleModels[i].Key == keyOrName || styleModels[i].Name == keyOrName {
+			return &styleModels[i], true
+		}
+	}
+	return nil, false
+}
+
+// LabOption get lab option of optionType by code
+func (c *Catalog) LabOption(optionType LabOptionType, code int) (*LabOption, bool) {
+	options := c.snapshot().labOptions[optionType]
+	for i := range options {
+		if options[i].Code == code {
+			return &options[i], true
+		}
+	}
+	return nil, false
+}
+
+// LabOptions get lab options of optionType
+func (c *Catalog) LabOptions(optionType LabOptionType) []LabOption {
+	return c.snapshot().labOptions[optionType]
+}
+
+// VideoModel get video model by code or name, name is used when code is zero
+func (c *Catalog) VideoModel(code ModelCode, name string) (*VideoModelOption, bool) {
+	table := c.snapshot().videoTable
+	if table == nil {
+		return nil, false
+	}
+	for i, option := range table.AiVideoModelOptionVos {
+		if (code != 0 && option.ModelCode == code) || (code == 0 && option.Name == name) {
+			return &table.AiVideoModelOptionVos[i], true
+		}
+	}
+	return nil, false
+}
+
+// VideoPriceTable get video option menu and price table
+func (c *Catalog) VideoPriceTable() *VideoOptionMenuAndPriceTable {
+	return c.snapshot().videoTable
+}
+
+// CameraTemplate get camera template option by key
+func (c *Catalog) CameraTemplate(key string) (*CameraTemplateOption, bool) {
+	templates := c.snapshot().cameraTemplates
+	for i := range templates {
+		if templates[i].Key == key {
+			return &templates[i], true
+		}
+	}
+	return nil, false
+}
+
+// CameraTemplates get camera template options
+func (c *Catalog) CameraTemplates() []CameraTemplateOption {
+	return c.snapshot().cameraTemplates
+}
+
+// Search search items whose name, english name, key or category contains query ignoring case,
+// exact matches come first, then prefix matches, kinds selects kinds of items and empty kinds selects all
+func (c *Catalog) Search(query string, kinds ...CatalogItemKind) []CatalogItem {
+	query = strings.ToLower(strings.TrimSpace(query))
+	type scored struct {
+		item  CatalogItem
+		score int
+	}
+	var matches []scored
+	for _, item := range c.snapshot().items {
+		if len(kinds) > 0 && !containsKind(kinds, item.Kind) {
+			continue
+		}
+		if score := matchScore(query, item); score > 0 {
+			matches = append(matches, scored{item: item, score: score})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].score > matches[j].score })
+	items := make([]CatalogItem, 0, len(matches))
+	for _, m := range matches {
+		items = append(items, m.item)
+	}
+	return items
+}
+
+func containsKind(kinds []CatalogItemKind, kind CatalogItemKind) bool {
+	for _, k := range kinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+func matchScore(query string, item CatalogItem) int {
+	if query == "" {
+		return 1
+	}
+	best := 0
+	for _, field := range []string{item.Name, item.EnName, item.Key, item.Category} {
+		field = strings.ToLower(field)
+		switch {
+		case field == "":
+		case field == query:
+			return 3
+		case strings.HasPrefix(field, query) && best < 2:
+			best = 2
+		case strings.Contains(field, query) && best < 1:
+			best = 1
+		}
+	}
+	return best
+}
+
+// SupportsControlNet report whether model supports controlnet, pro selects pro models
+func (c *Catalog) SupportsControlNet(code ModelCode, pro bool) bool {
+	m, ok := c.model(code, pro)
+	return ok && m.ControlNet
+}
+
+// SupportedResolutions get resolutions of model's default resource
+func (c *Catalog) SupportedResolutions(code ModelCode) []Resolution {
+	m, ok := c.Model(code)
+	if !ok || m.Resource == nil {
+		return nil
+	}
+	menu := m.Resource.CreateOptionMenu
+	resolutions := append([]Resolution(nil), menu.Resolution...)
+	for _, r := range menu.ResolutionNew.ResolutionList {
+		if !containsResolution(resolutions, r.Width, r.Height) {
+			resolutions = append(resolutions, Resolution{Width: r.Width, Height: r.Height,
+				SuperSizeMultiple: r.SuperSizeMultiple, Url: r.Url, SizeRatio: r.SizeRatio})
+		}
+	}
+	return resolutions
+}
+
+func containsResolution(resolutions []Resolution, width, height int) bool {
+	for _, r := range resolutions {
+		if r.Width == width && r.Height == height {
+			return true
+		}
+	}
+	return false
+}
+
+// CompatibleFusions get model fusions of Menu which support the version of model, pro selects pro models
+func (c *Catalog) CompatibleFusions(code ModelCode, pro bool) []FusionOption {
+	m, ok := c.model(code, pro)
+	if !ok || m.Menu == nil {
+		return nil
+	}
+	capability := m.Capability()
+	var fusions []FusionOption
+	for _, f := range m.Menu.ModelFusion {
+		if capability.supportsVersion(f.SupportModelVersions) {
+			fusions = append(fusions, f)
+		}
+	}
+	return fusions
+}
+
+// CompatibleCharacters get characters of Menu which support the version of model, pro selects pro models
+func (c *Catalog) CompatibleCharacters(code ModelCode, pro bool) []FusionOption {
+	m, ok := c.model(code, pro)
+	if !ok || m.Menu == nil {
+		return nil
+	}
+	capability := m.Capability()
+	var characters []FusionOption
+	for _, f := range m.Menu.Character {
+		if capability.supportsVersion(f.SupportModelVersions) {
+			characters = append(characters, f)
+		}
+	}
+	return characters
+}
+
+// Capability get capability of model for validating requests, pro selects pro models
+func (c *Catalog) Capability(code ModelCode, pro bool) (*ModelCapability, bool) {
+	m, ok := c.model(code, pro)
+	if !ok {
+		return nil, false
+	}
+	return m.Capability(), true
+}
+
+func (c *Catalog) model(code ModelCode, pro bool) (*CatalogModel, bool) {
+	if pro {
+		return c.ProModel(code)
+	}
+	return c.Model(code)
+}
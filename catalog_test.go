@@ -0,0 +1,39 @@
+package wujiesdk_test
+
+// @Title        catalog_test.go
+// @Description  test catalog against fake server
+// @Create       XdpCs 2026-10-20 13:00
+// @Update       XdpCs 2026-10-20 13:00
+
+import (
+	"context"
+	"testing"
+
+	"github.com/XdpCs/wujiesdk"
+	"github.com/XdpCs/wujiesdk/wujietest"
+)
+
+func TestCatalogModelByName(t *testing.T) {
+	f := wujietest.DefaultFixtures()
+	// models of the same version are returned by the lowest code whatever the order of the map is
+	for _, code := range []wujiesdk.ModelCode{9, 5, 7} {
+		f.ModelBaseInfos = append(f.ModelBaseInfos, wujiesdk.ModelBaseInfo{ModelCode: code, ModelVersion: "SD2"})
+	}
+	s := wujietest.NewServer(wujietest.WithTiming(0, 0), wujietest.WithFixtures(f))
+	defer s.Close()
+	catalog := wujiesdk.NewCatalog(s.Caller())
+	if err := catalog.Refresh(context.Background()); err != nil {
+		t.Fatalf("Refresh error: %v", err)
+	}
+	for i := 0; i < 10; i++ {
+		if m, ok := catalog.ModelByName("sd2", false); !ok || m.Code != 5 {
+			t.Fatalf("ModelByName(sd2): %+v, %v, want model 5", m, ok)
+		}
+	}
+	if m, ok := catalog.ModelByName("SD1.5", true); !ok || !m.Pro || m.Code != 1 {
+		t.Errorf("ModelByName(SD1.5, pro): %+v, %v, want pro model 1", m, ok)
+	}
+	if m, ok := catalog.VideoModel(2, ""); !ok || m.Name != "写实" {
+		t.Errorf("VideoModel(2): %+v, %v", m, ok)
+	}
+}
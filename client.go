@@ -3,7 +3,7 @@ package wujiesdk
 // @Title        client.go
 // @Description  request wujie's api
 // @Create       XdpCs 2023-09-10 20:47
-// @Update       XdpCs 2024-01-29 14:18
+// @Update       XdpCs 2026-10-20 13:00
 
 import (
 	"bytes"
@@ -35,6 +35,8 @@ type Client struct {
 	HttpHooks     HttpHooks    // hook before and after request
 	Credentials   *Credentials
 	Logger        *Logger
+	Language      Language        // language of WujieCode's message, DefaultLanguage if empty
+	Messages      *MessageCatalog // catalog of WujieCode's message, DefaultMessageCatalog if nil
 }
 
 // Logger is the logger for wujie's api
@@ -204,6 +206,38 @@ func (c *Client) SetHTTPClient(httpClient *http.Client) {
 	c.httpClient = httpClient
 }
 
+// SetTransport set transport of http client, http client is copied so that others sharing it are not affected
+func (c *Client) SetTransport(rt http.RoundTripper) {
+	httpClient := &http.Client{}
+	if c.httpClient != nil {
+		*httpClient = *c.httpClient
+	}
+	httpClient.Transport = rt
+	c.httpClient = httpClient
+}
+
+// SetLanguage set the language of WujieCode's message
+func (c *Client) SetLanguage(lang Language) {
+	c.Language = lang
+}
+
+func (c *Client) language(ctx context.Context) Language {
+	if lang, ok := LanguageFromContext(ctx); ok {
+		return lang
+	}
+	if c.Language != "" {
+		return c.Language
+	}
+	return DefaultLanguage
+}
+
+func (c *Client) messageCatalog() *MessageCatalog {
+	if c.Messages != nil {
+		return c.Messages
+	}
+	return DefaultMessageCatalog
+}
+
 // WriteLog output log function
 func (c *Client) WriteLog(LogLevel int, format string, a ...interface{}) {
 	if c.Logger == nil {
@@ -312,7 +346,7 @@ func (c *Client) DefaultResourceStyleModel(ctx context.Context) (*http.Response,
 }
 
 // DefaultResourceModel get model's default resource
-func (c *Client) DefaultResourceModel(ctx context.Context, model int32) (*http.Response, error) {
+func (c *Client) DefaultResourceModel(ctx context.Context, model ModelCode) (*http.Response, error) {
 	values := url.Values{
 		"model": []string{fmt.Sprintf("%d", model)},
 	}
@@ -430,7 +464,7 @@ func (c *Client) CreateParams(ctx context.Context, keys []string) (*http.Respons
 }
 
 // ImageModelQueueInfo get image model queue info
-func (c *Client) ImageModelQueueInfo(ctx context.Context, model int32) (*http.Response, error) {
+func (c *Client) ImageModelQueueInfo(ctx context.Context, model ModelCode) (*http.Response, error) {
 	values := url.Values{
 		"model": []string{fmt.Sprintf("%d", model)},
 	}
@@ -806,7 +840,7 @@ func (c *Client) VideoOptionMenuAndPriceTable(ctx context.Context) (*http.Respon
 }
 
 // VideoModelQueueInfo get video queue info
-func (c *Client) VideoModelQueueInfo(ctx context.Context, model int32) (*http.Response, error) {
+func (c *Client) VideoModelQueueInfo(ctx context.Context, model ModelCode) (*http.Response, error) {
 	values := url.Values{
 		"modelCode": []string{fmt.Sprintf("%d", model)},
 	}
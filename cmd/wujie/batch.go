@@ -0,0 +1,72 @@
+package main
+
+// @Title        batch.go
+// @Description  run create requests of jsonl or yaml manifest
+// @Create       XdpCs 2026-10-19 23:40
+// @Update       XdpCs 2026-10-20 11:10
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/XdpCs/wujiesdk"
+)
+
+var batchCommands = []command{
+	{name: "run", args: "MANIFEST", desc: "submit create requests of jsonl or yaml manifest and wait for them", setup: func(fs *flag.FlagSet) runFunc {
+		results := fs.String("results", "", "results manifest in jsonl, succeeded items in it are skipped (default MANIFEST.results.jsonl)")
+		concurrency := fs.Int("concurrency", wujiesdk.DefaultBatchConcurrency, "items running at the same time")
+		points := fs.Int("budget-points", 0, "limit of points spent by this run, 0 means no limit")
+		seconds := fs.Int("budget-seconds", 0, "limit of pro duration spent by this run, 0 means no limit")
+		prices := fs.String("prices", "", `price table as json for items open api can not price with budget, such as {"ProDurationPerImage": 10}, @file reads it from file`)
+		return func(ctx context.Context, e *env) (interface{}, error) {
+			if len(e.args) != 1 {
+				return nil, usagef("MANIFEST is required")
+			}
+			items, err := wujiesdk.LoadBatchManifest(e.args[0])
+			if err != nil {
+				return nil, err
+			}
+			b := wujiesdk.NewBatchRunner(e.caller)
+			b.Concurrency = *concurrency
+			b.Budget = wujiesdk.Cost{Points: *points, ProDuration: *seconds}
+			if !b.Budget.IsZero() {
+				var table wujiesdk.PriceTable
+				if err := e.readJSON(*prices, &table); err != nil {
+					return nil, err
+				}
+				b.Pricer = wujiesdk.NewCallerPricer(e.caller, table)
+			}
+			b.PollInterval = e.interval
+			b.DownloadDir = e.download
+			b.ResultsPath = *results
+			if b.ResultsPath == "" {
+				b.ResultsPath = strings.TrimSuffix(e.args[0], filepath.Ext(e.args[0])) + ".results.jsonl"
+			}
+			b.OnProgress = func(p wujiesdk.BatchProgress) {
+				line := fmt.Sprintf("[%d/%d] %s %s", p.Done, p.Total, p.Last.Status, p.Last.ID)
+				if p.Last.Error != "" {
+					line += ": " + p.Last.Error
+				}
+				fmt.Fprintln(e.stderr, line)
+			}
+			out, err := b.Run(ctx, items)
+			if err != nil {
+				return out, err
+			}
+			var failed int
+			for _, r := range out {
+				if r.Status != wujiesdk.SucceededBatchItemStatus {
+					failed++
+				}
+			}
+			if failed > 0 {
+				return out, fmt.Errorf("batch: %d of %d items not succeeded, results: %s", failed, len(out), b.ResultsPath)
+			}
+			return out, nil
+		}
+	}},
+}
@@ -0,0 +1,862 @@
+package main
+
+// @Title        commands.go
+// @Description  commands of command line tool grouped as README
+// @Create       XdpCs 2026-10-19 23:05
+// @Update       XdpCs 2026-10-20 13:00
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/XdpCs/wujiesdk"
+)
+
+var groups = []group{
+	{name: "account", desc: "points balance and exchange", commands: accountCommands},
+	{name: "image", desc: "ai images, super size, prompt optimization and youthify", commands: imageCommands},
+	{name: "pro", desc: "pro images, ControlNet, midjourney, flux and pro balance", commands: proCommands},
+	{name: "avatar", desc: "avatar training and avatar artworks", commands: avatarCommands},
+	{name: "spell", desc: "spells and spell analysis", commands: spellCommands},
+	{name: "dice", desc: "magic dice", commands: diceCommands},
+	{name: "video", desc: "video to video", commands: videoCommands},
+	{name: "camera", desc: "personal camera", commands: cameraCommands},
+	{name: "lab", desc: "ai lab, segmentation, infinite zoom, vector studio and image to video", commands: labCommands},
+	{name: "batch", desc: "batch jobs of manifest", commands: batchCommands},
+}
+
+var accountCommands = []command{
+	{name: "balance", desc: "get available points balance", setup: func(fs *flag.FlagSet) runFunc {
+		return func(ctx context.Context, e *env) (interface{}, error) {
+			_, balance, err := e.caller.AvailableIntegralBalance(ctx)
+			return balance, err
+		}
+	}},
+	{name: "exchange", desc: "exchange points to user of mobile", setup: func(fs *flag.FlagSet) runFunc {
+		mobile := fs.String("mobile", "", "mobile of target user")
+		amount := fs.Int("amount", 0, "points to exchange")
+		return func(ctx context.Context, e *env) (interface{}, error) {
+			if *mobile == "" || *amount <= 0 {
+				return nil, usagef("--mobile and --amount are required")
+			}
+			_, ok, err := e.caller.ExchangePoint(ctx, &wujiesdk.ExchangePointRequest{ExchangeTargetMobile: *mobile, Amount: *amount})
+			return ok, err
+		}
+	}},
+}
+
+var imageCommands = []command{
+	{name: "models", desc: "list models", setup: func(fs *flag.FlagSet) runFunc {
+		return func(ctx context.Context, e *env) (interface{}, error) {
+			_, models, err := e.caller.ModelBaseInfos(ctx)
+			return models, err
+		}
+	}},
+	{name: "resources", desc: "get default resources of model", setup: func(fs *flag.FlagSet) runFunc {
+		model := fs.Int("model", 0, "model code")
+		return func(ctx context.Context, e *env) (interface{}, error) {
+			_, data, err := e.caller.DefaultResourceModel(ctx, wujiesdk.ModelCode(*model))
+			return data, err
+		}
+	}},
+	{name: "style-models", desc: "list style models", setup: func(fs *flag.FlagSet) runFunc {
+		return func(ctx context.Context, e *env) (interface{}, error) {
+			_, models, err := e.caller.DefaultResourceStyleModel(ctx)
+			return models, err
+		}
+	}},
+	{name: "create", desc: "create images", setup: func(fs *flag.FlagSet) runFunc {
+		req := imageRequestFlags(fs)
+		return func(ctx context.Context, e *env) (interface{}, error) {
+			r, err := req(e)
+			if err != nil {
+				return nil, err
+			}
+			job, err := e.caller.SubmitImage(ctx, r)
+			if err != nil {
+				return nil, err
+			}
+			return e.finish(ctx, submitted(job), wujiesdk.ImageProduct, job.Keys...)
+		}
+	}},
+	{name: "price", desc: "compute points of creating images", setup: func(fs *flag.FlagSet) runFunc {
+		req := imageRequestFlags(fs)
+		return func(ctx context.Context, e *env) (interface{}, error) {
+			r, err := req(e)
+			if err != nil {
+				return nil, err
+			}
+			_, data, err := e.caller.ImagePriceInfo(ctx, &wujiesdk.ImagePriceInfoRequest{CreateImageRequest: *r})
+			return data, err
+		}
+	}},
+	{name: "info", args: "KEY...", desc: "get generating info of images", setup: func(fs *flag.FlagSet) runFunc {
+		return func(ctx context.Context, e *env) (interface{}, error) {
+			if len(e.args) == 0 {
+				return nil, usagef("KEY is required")
+			}
+			return e.query(ctx, wujiesdk.ImageProduct, e.args...)
+		}
+	}},
+	{name: "detail", args: "KEY", desc: "get detail of a finished image", setup: func(fs *flag.FlagSet) runFunc {
+		return func(ctx context.Context, e *env) (interface{}, error) {
+			key, err := e.key()
+			if err != nil {
+				return nil, err
+			}
+			_, data, err := e.caller.ImageInfo(ctx, key)
+			if err == nil && e.download != "" {
+				err = e.downloadAll(ctx, data)
+			}
+			return data, err
+		}
+	}},
+	{name: "params", args: "KEY...", desc: "get create params of images, 6 keys at most", setup: func(fs *flag.FlagSet) runFunc {
+		return func(ctx context.Context, e *env) (interface{}, error) {
+			if len(e.args) == 0 {
+				return nil, usagef("KEY is required")
+			}
+			_, params, err := e.caller.CreateParams(ctx, e.args)
+			return params, err
+		}
+	}},
+	{name: "queue", desc: "get queue info of model", setup: func(fs *flag.FlagSet) runFunc {
+		model := fs.Int("model", 0, "model code")
+		return func(ctx context.Context, e *env) (interface{}, error) {
+			_, data, err := e.caller.ImageModelQueueInfo(ctx, wujiesdk.ModelCode(*model))
+			return data, err
+		}
+	}},
+	{name: "cancel", args: "KEY", desc: "cancel an image in queue", setup: func(fs *flag.FlagSet) runFunc {
+		return func(ctx context.Context, e *env) (interface{}, error) {
+			key, err := e.key()
+			if err != nil {
+				return nil, err
+			}
+			_, data, err := e.caller.CancelImage(ctx, key)
+			return data, err
+		}
+	}},
+	{name: "accelerate", args: "KEY", desc: "accelerate an image", setup: func(fs *flag.FlagSet) runFunc {
+		steps := fs.Int("steps", 0, "step num")
+		return func(ctx context.Context, e *env) (interface{}, error) {
+			key, err := e.key()
+			if err != nil {
+				return nil, err
+			}
+			_, ok, err := e.caller.AccelerateImage(ctx, &wujiesdk.AccelerateImageRequest{Key: key, StepNum: *steps})
+			if err != nil {
+				return nil, err
+			}
+			return e.finish(ctx, ok, wujiesdk.ImageProduct, key)
+		}
+	}},
+	{name: "supersize", desc: "super size an image", setup: func(fs *flag.FlagSet) runFunc {
+		body := jsonFlag(fs)
+		u := fs.String("url", "", "url of image")
+		multiple := fs.Float64("multiple", 0, "multiple of super size")
+		return func(ctx context.Context, e *env) (interface{}, error) {
+			r := &wujiesdk.PostSuperSizeRequest{}
+			if err := e.readJSON(*body, r); err != nil {
+				return nil, err
+			}
+			setString(&r.URL, *u)
+			setFloat(&r.Multiple, *multiple)
+			_, key, err := e.caller.PostSuperSize(ctx, r)
+			if err != nil {
+				return nil, err
+			}
+			return e.finish(ctx, key, wujiesdk.SuperSizeProduct, key)
+		}
+	}},
+	{name: "supersize-info", args: "KEY...", desc: "get super size results", setup: func(fs *flag.FlagSet) runFunc {
+		return func(ctx context.Context, e *env) (interface{}, error) {
+			if len(e.args) == 0 {
+				return nil, usagef("KEY is required")
+			}
+			return e.query(ctx, wujiesdk.SuperSizeProduct, e.args...)
+		}
+	}},
+	{name: "optimize", args: "PROMPT", desc: "submit a prompt optimization task", setup: func(fs *flag.FlagSet) runFunc {
+		taskID := fs.String("task-id", "", "task id, random if empty")
+		typ := fs.Int("type", int(wujiesdk.CommonPromptSubmitType), "1 common, 2 color, 3 anime")
+		english := fs.Bool("english", false, "prompt is in english")
+		callback := fs.String("callback-url", "", "callback url")
+		return func(ctx context.Context, e *env) (interface{}, error) {
+			prompt, err := e.key()
+			if err != nil {
+				return nil, err
+			}
+			r := &wujiesdk.PromptOptimizeSubmitRequest{
+				TaskID:      *taskID,
+				Type:        wujiesdk.PromptSubmitType(*typ),
+				Original:    prompt,
+				Language:    wujiesdk.ChinesePromptSubmitLanguage,
+				CallbackURL: *callback,
+			}
+			if *english {
+				r.Language = wujiesdk.EnglishPromptSubmitLanguage
+			}
+			if r.TaskID == "" {
+				r.TaskID = randomID()
+			}
+			if _, _, err := e.caller.PromptOptimizeSubmit(ctx, r); err != nil {
+				return nil, err
+			}
+			return e.finish(ctx, &wujiesdk.PromptOptimizeResultData{TaskID: r.TaskID}, wujiesdk.PromptOptimizeProduct, r.TaskID)
+		}
+	}},
+	{name: "optimize-result", args: "TASK_ID", desc: "get result of a prompt optimization task", setup: func(fs *flag.FlagSet) runFunc {
+		return func(ctx context.Context, e *env) (interface{}, error) {
+			taskID, err := e.key()
+			if err != nil {
+				return nil, err
+			}
+			return e.query(ctx, wujiesdk.PromptOptimizeProduct, taskID)
+		}
+	}},
+	{name: "youthify", desc: "make people in image younger", setup: func(fs *flag.FlagSet) runFunc {
+		body := jsonFlag(fs)
+		u := fs.String("url", "", "url of image")
+		return func(ctx context.Context, e *env) (interface{}, error) {
+			r := &wujiesdk.YouthifyRequest{}
+			if err := e.readJSON(*body, r); err != nil {
+				return nil, err
+			}
+			setString(&r.ImageURL, *u)
+			_, data, err := e.caller.Youthify(ctx, r)
+			return data, err
+		}
+	}},
+}
+
+var proCommands = []command{
+	{name: "balance", desc: "get pro balance in seconds", setup: func(fs *flag.FlagSet) runFunc {
+		return func(ctx context.Context, e *env) (interface{}, error) {
+			_, balance, err := e.caller.AccountBalancePro(ctx)
+			return balance, err
+		}
+	}},
+	{name: "models", desc: "list pro models", setup: func(fs *flag.FlagSet) runFunc {
+		return func(ctx context.Context, e *env) (interface{}, error) {
+			_, models, err := e.caller.ModelBaseInfosPro(ctx)
+			return models, err
+		}
+	}},
+	{name: "controlnet", desc: "list ControlNet types, preprocessors and models", setup: func(fs *flag.FlagSet) runFunc {
+		return func(ctx context.Context, e *env) (interface{}, error) {
+			_, options, err := e.caller.ControlNetOptionPro(ctx)
+			return options, err
+		}
+	}},
+	{name: "create", desc: "create pro images", setup: func(fs *flag.FlagSet) runFunc {
+		body := jsonFlag(fs)
+		prompt := fs.String("prompt", "", "prompt")
+		model := fs.Int("model", 0, "model code")
+		width := fs.Int("width", 0, "width")
+		height := fs.Int("height", 0, "height")
+		count := fs.Int("count", 0, "batch count")
+		return func(ctx context.Context, e *env) (interface{}, error) {
+			r := &wujiesdk.CreateImageProRequest{}
+			if err := e.readJSON(*body, r); err != nil {
+				return nil, err
+			}
+			setString(&r.Prompt, *prompt)
+			setModelCode(&r.ModelCode, *model)
+			setInt(&r.Width, *width)
+			setInt(&r.Height, *height)
+			setInt(&r.BatchCount, *count)
+			_, results, err := e.caller.CreateImagePro(ctx, r)
+			if err != nil {
+				return nil, err
+			}
+			keys := make([]string, 0, len(results))
+			for _, result := range results {
+				keys = append(keys, result.Key)
+			}
+			return e.finish(ctx, results, wujiesdk.ProProduct, keys...)
+		}
+	}},
+	{name: "info", args: "KEY...", desc: "get generating info of pro images", setup: func(fs *flag.FlagSet) runFunc {
+		return func(ctx context.Context, e *env) (interface{}, error) {
+			if len(e.args) == 0 {
+				return nil, usagef("KEY is required")
+			}
+			return e.query(ctx, wujiesdk.ProProduct, e.args...)
+		}
+	}},
+	{name: "detail", args: "KEY", desc: "get detail of a pro image", setup: func(fs *flag.FlagSet) runFunc {
+		return func(ctx context.Context, e *env) (interface{}, error) {
+			key, err := e.key()
+			if err != nil {
+				return nil, err
+			}
+			_, data, err := e.caller.ImageInfoPro(ctx, key)
+			return data, err
+		}
+	}},
+	{name: "midjourney", desc: "create midjourney images", setup: func(fs *flag.FlagSet) runFunc {
+		body := jsonFlag(fs)
+		prompt := fs.String("prompt", "", "prompt")
+		num := fs.Int("num", 0, "number of images")
+		return func(ctx context.Context, e *env) (interface{}, error) {
+			r := &wujiesdk.CreateMidjourneyRequest{}
+			if err := e.readJSON(*body, r); err != nil {
+				return nil, err
+			}
+			setString(&r.Prompt, *prompt)
+			setInt(&r.Num, *num)
+			_, resp, err := e.caller.CreateMidjourney(ctx, r)
+			if err != nil {
+				return nil, err
+			}
+			return e.finish(ctx, resp.Data, wujiesdk.ImageProduct, createdKeys(&resp.Data)...)
+		}
+	}},
+	{name: "flux", desc: "create flux images", setup: func(fs *flag.FlagSet) runFunc {
+		body := jsonFlag(fs)
+		prompt := fs.String("prompt", "", "prompt")
+		model := fs.Int("model", 0, "model code")
+		num := fs.Int("num", 0, "number of images")
+		return func(ctx context.Context, e *env) (interface{}, error) {
+			r := &wujiesdk.CreateFluxRequest{}
+			if err := e.readJSON(*body, r); err != nil {
+				return nil, err
+			}
+			setString(&r.Prompt, *prompt)
+			setModelCode(&r.Model, *model)
+			setInt(&r.Num, *num)
+			_, resp, err := e.caller.CreateFlux(ctx, r)
+			if err != nil {
+				return nil, err
+			}
+			return e.finish(ctx, resp.Data, wujiesdk.ImageProduct, createdKeys(&resp.Data)...)
+		}
+	}},
+}
+
+var avatarCommands = []command{
+	{name: "check", args: "URL...", desc: "check training images", setup: func(fs *flag.FlagSet) runFunc {
+		return func(ctx context.Context, e *env) (interface{}, error) {
+			if len(e.args) == 0 {
+				return nil, usagef("URL is required")
+			}
+			_, infos, err := e.caller.ImageBatchCheck(ctx, e.args)
+			return infos, err
+		}
+	}},
+	{name: "create", args: "URL...", desc: "train an avatar with images", setup: func(fs *flag.FlagSet) runFunc {
+		notify := fs.String("notify-url", "", "notify url")
+		return func(ctx context.Context, e *env) (interface{}, error) {
+			if len(e.args) == 0 {
+				return nil, usagef("URL is required")
+			}
+			_, data, err := e.caller.CreateAvatar(ctx, &wujiesdk.CreateAvatarRequest{TrainImageUrlList: e.args, NotifyUrl: *notify})
+			if err != nil {
+				return nil, err
+			}
+			return e.finish(ctx, data, wujiesdk.AvatarProduct, data.Key)
+		}
+	}},
+	{name: "info", args: "KEY", desc: "get info of an avatar", setup: func(fs *flag.FlagSet) runFunc {
+		return func(ctx context.Context, e *env) (interface{}, error) {
+			key, err := e.key()
+			if err != nil {
+				return nil, err
+			}
+			return e.query(ctx, wujiesdk.AvatarProduct, key)
+		}
+	}},
+	{name: "delete", args: "KEY", desc: "delete an avatar", setup: func(fs *flag.FlagSet) runFunc {
+		return func(ctx context.Context, e *env) (interface{}, error) {
+			key, err := e.key()
+			if err != nil {
+				return nil, err
+			}
+			_, ok, err := e.caller.DeleteAvatar(ctx, key)
+			return ok, err
+		}
+	}},
+	{name: "artwork", desc: "create artworks of an avatar", setup: func(fs *flag.FlagSet) runFunc {
+		body := jsonFlag(fs)
+		avatarKey := fs.String("avatar-key", "", "avatar key")
+		prompt := fs.String("prompt", "", "prompt")
+		return func(ctx context.Context, e *env) (interface{}, error) {
+			r := &wujiesdk.CreateAvatarArtworkRequest{}
+			if err := e.readJSON(*body, r); err != nil {
+				return nil, err
+			}
+			setString(&r.AvatarKey, *avatarKey)
+			setString(&r.Prompt, *prompt)
+			_, data, err := e.caller.CreateAvatarArtwork(ctx, r)
+			if err != nil {
+				return nil, err
+			}
+			return e.finish(ctx, data, wujiesdk.ImageProduct, createdKeys(&wujiesdk.CreateImageData{Keys: data.Keys, Results: data.Results})...)
+		}
+	}},
+	{name: "resources", desc: "get default resources of avatar artworks", setup: func(fs *flag.FlagSet) runFunc {
+		return func(ctx context.Context, e *env) (interface{}, error) {
+			_, data, err := e.caller.AvatarDefaultResource(ctx)
+			return data, err
+		}
+	}},
+}
+
+var spellCommands = []command{
+	{name: "list", desc: "list spells", setup: func(fs *flag.FlagSet) runFunc {
+		return func(ctx context.Context, e *env) (interface{}, error) {
+			_, spells, err := e.caller.QuerySpell(ctx)
+			return spells, err
+		}
+	}},
+	{name: "analyze", args: "URL", desc: "analyze spell of an image", setup: func(fs *flag.FlagSet) runFunc {
+		notify := fs.String("notify-url", "", "notify url")
+		return func(ctx context.Context, e *env) (interface{}, error) {
+			u, err := e.key()
+			if err != nil {
+				return nil, err
+			}
+			_, key, err := e.caller.CreateSpellAnalysis(ctx, &wujiesdk.CreateSpellAnalysisRequest{ImageURL: u, NotifyURL: *notify})
+			if err != nil {
+				return nil, err
+			}
+			return e.finish(ctx, key, wujiesdk.SpellAnalysisProduct, key)
+		}
+	}},
+	{name: "info", args: "KEY", desc: "get result of spell analysis", setup: func(fs *flag.FlagSet) runFunc {
+		return func(ctx context.Context, e *env) (interface{}, error) {
+			key, err := e.key()
+			if err != nil {
+				return nil, err
+			}
+			return e.query(ctx, wujiesdk.SpellAnalysisProduct, key)
+		}
+	}},
+}
+
+var diceCommands = []command{
+	{name: "themes", desc: "list magic dice themes", setup: func(fs *flag.FlagSet) runFunc {
+		return func(ctx context.Context, e *env) (interface{}, error) {
+			_, themes, err := e.caller.MagicDiceTheme(ctx)
+			return themes, err
+		}
+	}},
+	{name: "create", desc: "roll magic dice for a prompt", setup: func(fs *flag.FlagSet) runFunc {
+		theme := fs.Int("theme", 0, "theme id")
+		keyword := fs.String("keyword", "", "keyword")
+		typ := fs.String("type", string(wujiesdk.MagicDicCreateMagicDiceType), "MAGIC_DICE or SMART_MAGIC")
+		model := fs.String("model", string(wujiesdk.StableDiffusionCreateMagicDiceModel), "STABLE_DIFFUSION, ANIME_DIFFUSION or STYLE")
+		language := fs.String("language", string(wujiesdk.ChineseCreateMagicDiceLanguage), "CHINESE or ENGLISH")
+		return func(ctx context.Context, e *env) (interface{}, error) {
+			_, data, err := e.caller.CreateMagicDice(ctx, &wujiesdk.CreateMagicDiceRequest{
+				Type:     wujiesdk.CreateMagicDiceType(*typ),
+				Model:    wujiesdk.CreateMagicDiceModel(*model),
+				Keyword:  *keyword,
+				ThemeId:  *theme,
+				Language: wujiesdk.CreateMagicDiceLanguage(*language),
+			})
+			return data, err
+		}
+	}},
+	{name: "surprise", desc: "roll magic dice and create images of it", setup: func(fs *flag.FlagSet) runFunc {
+		theme := fs.Int("theme", 0, "theme id, random if zero")
+		keyword := fs.String("keyword", "", "keyword")
+		num := fs.Int("num", 0, "number of images")
+		width := fs.Int("width", 0, "width")
+		height := fs.Int("height", 0, "height")
+		return func(ctx context.Context, e *env) (interface{}, error) {
+			options := []wujiesdk.SurpriseOption{wujiesdk.WithSurpriseKeyword(*keyword)}
+			if *theme > 0 {
+				options = append(options, wujiesdk.WithSurpriseTheme(*theme))
+			}
+			if *num > 0 {
+				options = append(options, wujiesdk.WithSurpriseNum(*num))
+			}
+			if *width > 0 && *height > 0 {
+				options = append(options, wujiesdk.WithSurpriseSize(*width, *height))
+			}
+			job, dice, err := e.caller.SurpriseMe(ctx, options...)
+			if err != nil {
+				return nil, err
+			}
+			created := submitted(job)
+			created.MagicDice = dice
+			return e.finish(ctx, created, wujiesdk.ImageProduct, job.Keys...)
+		}
+	}},
+}
+
+var videoCommands = []command{
+	{name: "menu", desc: "list video models and price table", setup: func(fs *flag.FlagSet) runFunc {
+		return func(ctx context.Context, e *env) (interface{}, error) {
+			_, menu, err := e.caller.VideoOptionMenuAndPriceTable(ctx)
+			return menu, err
+		}
+	}},
+	{name: "queue", desc: "get queue info of video model", setup: func(fs *flag.FlagSet) runFunc {
+		model := fs.Int("model", 0, "model code")
+		return func(ctx context.Context, e *env) (interface{}, error) {
+			_, data, err := e.caller.VideoModelQueueInfo(ctx, wujiesdk.ModelCode(*model))
+			return data, err
+		}
+	}},
+	{name: "create", args: "URL", desc: "create a video from a video", setup: func(fs *flag.FlagSet) runFunc {
+		duration := fs.Int("duration", 0, "duration of video in seconds")
+		model := fs.Int("model", 0, "model code")
+		queue := fs.Int("queue-type", 0, "queue type, 1 for night queue")
+		notify := fs.String("notify-url", "", "notify url")
+		return func(ctx context.Context, e *env) (interface{}, error) {
+			u, err := e.key()
+			if err != nil {
+				return nil, err
+			}
+			_, key, err := e.caller.CreateVideo(ctx, &wujiesdk.CreateVideoRequest{
+				OriginVideoUrl: u,
+				VideoDuration:  *duration,
+				ModelCode:      wujiesdk.ModelCode(*model),
+				QueueType:      *queue,
+				NotifyUrl:      *notify,
+			})
+			if err != nil {
+				return nil, err
+			}
+			return e.finish(ctx, key, wujiesdk.VideoProduct, key)
+		}
+	}},
+	{name: "info", args: "KEY", desc: "get info of a video", setup: func(fs *flag.FlagSet) runFunc {
+		return func(ctx context.Context, e *env) (interface{}, error) {
+			key, err := e.key()
+			if err != nil {
+				return nil, err
+			}
+			return e.query(ctx, wujiesdk.VideoProduct, key)
+		}
+	}},
+	{name: "progress", args: "KEY...", desc: "get generating info of videos", setup: func(fs *flag.FlagSet) runFunc {
+		return func(ctx context.Context, e *env) (interface{}, error) {
+			if len(e.args) == 0 {
+				return nil, usagef("KEY is required")
+			}
+			_, data, err := e.caller.VideoGeneratingInfo(ctx, e.args)
+			return data, err
+		}
+	}},
+}
+
+var cameraCommands = []command{
+	{name: "templates", desc: "list camera templates", setup: func(fs *flag.FlagSet) runFunc {
+		return func(ctx context.Context, e *env) (interface{}, error) {
+			_, templates, err := e.caller.CameraTemplateOptions(ctx)
+			return templates, err
+		}
+	}},
+	{name: "create", desc: "create artworks with an avatar and a template", setup: func(fs *flag.FlagSet) runFunc {
+		body := jsonFlag(fs)
+		avatarKey := fs.String("avatar-key", "", "avatar key")
+		template := fs.String("template", "", "template key")
+		count := fs.Int("count", 0, "number of artworks")
+		return func(ctx context.Context, e *env) (interface{}, error) {
+			r := &wujiesdk.CreateCameraRequest{}
+			if err := e.readJSON(*body, r); err != nil {
+				return nil, err
+			}
+			setString(&r.AvtarKey, *avatarKey)
+			if *template != "" || *count > 0 {
+				if r.TemplateCreateParam == nil {
+					r.TemplateCreateParam = &wujiesdk.TemplateCreateParam{}
+				}
+				setString(&r.TemplateCreateParam.TemplateKey, *template)
+				setInt(&r.TemplateCreateParam.Count, *count)
+			}
+			_, data, err := e.caller.CreateCamera(ctx, r)
+			if err != nil {
+				return nil, err
+			}
+			return e.finish(ctx, data, wujiesdk.CameraProduct, data.Keys...)
+		}
+	}},
+	{name: "progress", args: "KEY...", desc: "get generating info of camera artworks", setup: func(fs *flag.FlagSet) runFunc {
+		return func(ctx context.Context, e *env) (interface{}, error) {
+			if len(e.args) == 0 {
+				return nil, usagef("KEY is required")
+			}
+			return e.query(ctx, wujiesdk.CameraProduct, e.args...)
+		}
+	}},
+	{name: "info", args: "KEY", desc: "get info of a camera artwork", setup: func(fs *flag.FlagSet) runFunc {
+		return func(ctx context.Context, e *env) (interface{}, error) {
+			key, err := e.key()
+			if err != nil {
+				return nil, err
+			}
+			_, data, err := e.caller.CameraInfo(ctx, key)
+			if err == nil && e.download != "" {
+				err = e.downloadAll(ctx, data)
+			}
+			return data, err
+		}
+	}},
+}
+
+var labCommands = []command{
+	{name: "options", desc: "list lab options of type", setup: func(fs *flag.FlagSet) runFunc {
+		typ := fs.String("type", "", "INFINITE_ZOOM_MODEL, INFINITE_ZOOM_SAMPLER, SEGMENT_ANYTHING_MODEL or VECTOR_STUDIO_STYLE")
+		return func(ctx context.Context, e *env) (interface{}, error) {
+			if *typ == "" {
+				return nil, usagef("--type is required")
+			}
+			_, options, err := e.caller.LabOptions(ctx, &wujiesdk.LabOptionsRequest{
+				Input: &wujiesdk.LabOptionsInput{OptionType: wujiesdk.LabOptionType(*typ)},
+			})
+			return options, err
+		}
+	}},
+	{name: "info", args: "KEY", desc: "get info of a lab job", setup: func(fs *flag.FlagSet) runFunc {
+		typ := fs.String("type", "", "ai type, such as AI_LAB_SEGMENTATION, AI_LAB_INFINITE_ZOOM or VECTOR")
+		return func(ctx context.Context, e *env) (interface{}, error) {
+			key, err := e.key()
+			if err != nil {
+				return nil, err
+			}
+			if *typ == "" {
+				return nil, usagef("--type is required")
+			}
+			product, ok := labProduct(wujiesdk.LabInfoType(*typ))
+			if !ok {
+				return nil, usagef("--type %s is not one of AI_LAB_SEGMENTATION, AI_LAB_INFINITE_ZOOM and VECTOR", *typ)
+			}
+			return e.query(ctx, product, key)
+		}
+	}},
+	{name: "segment", desc: "segment anything", setup: func(fs *flag.FlagSet) runFunc {
+		body := jsonFlag(fs)
+		return func(ctx context.Context, e *env) (interface{}, error) {
+			r := &wujiesdk.CreateSegmentationRequest{}
+			if err := e.readJSON(*body, r); err != nil {
+				return nil, err
+			}
+			_, data, err := e.caller.CreateSegmentation(ctx, r)
+			if err != nil {
+				return nil, err
+			}
+			return e.finish(ctx, data, wujiesdk.SegmentationProduct, data.Key)
+		}
+	}},
+	{name: "zoom", desc: "create an infinite zoom video", setup: func(fs *flag.FlagSet) runFunc {
+		body := jsonFlag(fs)
+		return func(ctx context.Context, e *env) (interface{}, error) {
+			r := &wujiesdk.CreateInfiniteZoomRequest{}
+			if err := e.readJSON(*body, r); err != nil {
+				return nil, err
+			}
+			_, data, err := e.caller.CreateInfiniteZoom(ctx, r)
+			if err != nil {
+				return nil, err
+			}
+			return e.finish(ctx, data, wujiesdk.InfiniteZoomProduct, data.Key)
+		}
+	}},
+	{name: "vector", desc: "vectorize an image", setup: func(fs *flag.FlagSet) runFunc {
+		body := jsonFlag(fs)
+		return func(ctx context.Context, e *env) (interface{}, error) {
+			r := &wujiesdk.CreateVectorStudioRequest{}
+			if err := e.readJSON(*body, r); err != nil {
+				return nil, err
+			}
+			_, data, err := e.caller.CreateVectorStudio(ctx, r)
+			if err != nil {
+				return nil, err
+			}
+			return e.finish(ctx, data, wujiesdk.VectorStudioProduct, data.Key)
+		}
+	}},
+	{name: "svd", args: "URL", desc: "create a video from an image", setup: func(fs *flag.FlagSet) runFunc {
+		body := jsonFlag(fs)
+		duration := fs.Int("duration", 0, "duration of video in seconds")
+		return func(ctx context.Context, e *env) (interface{}, error) {
+			r := &wujiesdk.CreateSVDRequest{}
+			if err := e.readJSON(*body, r); err != nil {
+				return nil, err
+			}
+			if len(e.args) > 0 {
+				r.InitImageUrl = e.args[0]
+			}
+			setInt(&r.Duration, *duration)
+			_, key, err := e.caller.CreateSVD(ctx, r)
+			if err != nil {
+				return nil, err
+			}
+			return e.finish(ctx, key, wujiesdk.SVDProduct, key)
+		}
+	}},
+	{name: "svd-info", args: "KEY", desc: "get info of an image to video job", setup: func(fs *flag.FlagSet) runFunc {
+		return func(ctx context.Context, e *env) (interface{}, error) {
+			key, err := e.key()
+			if err != nil {
+				return nil, err
+			}
+			return e.query(ctx, wujiesdk.SVDProduct, key)
+		}
+	}},
+}
+
+// imageRequestFlags define flags of CreateImageRequest, flags override fields of --json
+func imageRequestFlags(fs *flag.FlagSet) func(e *env) (*wujiesdk.CreateImageRequest, error) {
+	body := jsonFlag(fs)
+	prompt := fs.String("prompt", "", "prompt")
+	ucPrompt := fs.String("uc-prompt", "", "negative prompt")
+	model := fs.Int("model", 0, "model code")
+	num := fs.Int("num", 0, "number of images, 1 if not set")
+	width := fs.Int("width", 0, "width")
+	height := fs.Int("height", 0, "height")
+	var styles, artists stringsFlag
+	fs.Var(&styles, "style", "style, repeatable")
+	fs.Var(&artists, "artist", "artist, repeatable")
+	notify := fs.String("notify-url", "", "notify url")
+	return func(e *env) (*wujiesdk.CreateImageRequest, error) {
+		r := &wujiesdk.CreateImageRequest{}
+		if err := e.readJSON(*body, r); err != nil {
+			return nil, err
+		}
+		setString(&r.Prompt, *prompt)
+		setString(&r.UcPrompt, *ucPrompt)
+		setModelCode(&r.Model, *model)
+		setInt(&r.Num, *num)
+		setInt(&r.Width, *width)
+		setInt(&r.Height, *height)
+		setString(&r.NotifyURL, *notify)
+		r.Style = append(r.Style, styles...)
+		r.Artists = append(r.Artists, artists...)
+		if r.Num == 0 {
+			r.Num = 1
+		}
+		return r, nil
+	}
+}
+
+func jsonFlag(fs *flag.FlagSet) *string {
+	return fs.String("json", "", "request body as json, @file reads it from file and - from stdin, flags override it")
+}
+
+// readJSON decode --json into v, unknown fields are rejected to catch typos
+func (e *env) readJSON(s string, v interface{}) error {
+	if s == "" {
+		return nil
+	}
+	var data []byte
+	switch {
+	case s == "-":
+		b, err := io.ReadAll(e.stdin)
+		if err != nil {
+			return fmt.Errorf("io.ReadAll: stdin: error: %w", err)
+		}
+		data = b
+	case strings.HasPrefix(s, "@"):
+		b, err := os.ReadFile(s[1:])
+		if err != nil {
+			return fmt.Errorf("os.ReadFile: path: %v, error: %w", s[1:], err)
+		}
+		data = b
+	default:
+		data = []byte(s)
+	}
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.DisallowUnknownFields()
+	if err := d.Decode(v); err != nil {
+		return usagef("--json: %v", err)
+	}
+	return nil
+}
+
+// key get the only positional argument
+func (e *env) key() (string, error) {
+	if len(e.args) != 1 {
+		return "", usagef("exactly one argument is required, got %d", len(e.args))
+	}
+	return e.args[0], nil
+}
+
+// submittedImages is printed for ImageJob, which holds caller and credentials
+type submittedImages struct {
+	Keys                 []string                        `json:"keys"`
+	ExpectedSecond       int                             `json:"expected_second"`
+	ExpectedIntegralCost int                             `json:"expected_integral_cost"`
+	Request              *wujiesdk.CreateImageRequest    `json:"request"`
+	MagicDice            *wujiesdk.CreateMagicDiceResult `json:"magic_dice,omitempty"`
+}
+
+func submitted(job *wujiesdk.ImageJob) *submittedImages {
+	return &submittedImages{
+		Keys:                 job.Keys,
+		ExpectedSecond:       job.ExpectedSecond,
+		ExpectedIntegralCost: job.ExpectedIntegralCost,
+		Request:              job.Request,
+	}
+}
+
+// createdKeys get keys of created images from keys or results
+func createdKeys(data *wujiesdk.CreateImageData) []string {
+	if len(data.Keys) > 0 {
+		return data.Keys
+	}
+	keys := make([]string, 0, len(data.Results))
+	for _, r := range data.Results {
+		keys = append(keys, r.Key)
+	}
+	return keys
+}
+
+func randomID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return strconv.FormatInt(time.Now().UnixNano(), 16)
+	}
+	return hex.EncodeToString(b)
+}
+
+func setString(dst *string, v string) {
+	if v != "" {
+		*dst = v
+	}
+}
+
+func setInt(dst *int, v int) {
+	if v != 0 {
+		*dst = v
+	}
+}
+
+func setModelCode(dst *wujiesdk.ModelCode, v int) {
+	if v != 0 {
+		*dst = wujiesdk.ModelCode(v)
+	}
+}
+
+func setFloat(dst *float64, v float64) {
+	if v != 0 {
+		*dst = v
+	}
+}
+
+// stringsFlag is a repeatable string flag
+type stringsFlag []string
+
+func (s *stringsFlag) String() string {
+	return strings.Join(*s, ",")
+}
+
+func (s *stringsFlag) Set(v string) error {
+	*s = append(*s, v)
+	return nil
+}
@@ -0,0 +1,117 @@
+package main
+
+// @Title        config.go
+// @Description  credentials of command line tool from env vars or config file
+// @Create       XdpCs 2026-10-19 23:05
+// @Update       XdpCs 2026-10-19 23:05
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/XdpCs/wujiesdk"
+)
+
+// config is the content of config file, env vars take precedence over it
+type config struct {
+	AppID          string `json:"app_id"`           // $WUJIE_APP_ID
+	PrivateKey     string `json:"private_key"`      // $WUJIE_PRIVATE_KEY, base64 of PKCS8 private key
+	PrivateKeyFile string `json:"private_key_file"` // $WUJIE_PRIVATE_KEY_FILE, PEM or base64 of PKCS8 private key, used if private key is empty
+	Language       string `json:"language"`         // $WUJIE_LANGUAGE, language of WujieCode's message
+	MaxRetryTimes  int    `json:"max_retry_times"`  // 3 if zero
+}
+
+// loadConfig load config from path, $WUJIE_CONFIG or wujie/config.json in user config dir,
+// only a missing file at default path is not an error
+func loadConfig(path string, getenv func(string) string) (*config, error) {
+	explicit := path != ""
+	if path == "" {
+		path = getenv("WUJIE_CONFIG")
+		explicit = path != ""
+	}
+	if path == "" {
+		if dir, err := os.UserConfigDir(); err == nil {
+			path = filepath.Join(dir, "wujie", "config.json")
+		}
+	}
+
+	cfg := &config{}
+	if path != "" {
+		data, err := os.ReadFile(path)
+		switch {
+		case err == nil:
+			if err := json.Unmarshal(data, cfg); err != nil {
+				return nil, fmt.Errorf("json.Unmarshal: config: %v, error: %w", path, err)
+			}
+		case explicit || !errors.Is(err, fs.ErrNotExist):
+			return nil, fmt.Errorf("os.ReadFile: config: %v, error: %w", path, err)
+		}
+	}
+
+	for name, field := range map[string]*string{
+		"WUJIE_APP_ID":           &cfg.AppID,
+		"WUJIE_PRIVATE_KEY":      &cfg.PrivateKey,
+		"WUJIE_PRIVATE_KEY_FILE": &cfg.PrivateKeyFile,
+		"WUJIE_LANGUAGE":         &cfg.Language,
+	} {
+		if v := getenv(name); v != "" {
+			*field = v
+		}
+	}
+	// private key wins over private key file
+	if cfg.PrivateKey == "" && cfg.PrivateKeyFile != "" {
+		data, err := os.ReadFile(cfg.PrivateKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("os.ReadFile: private key file: %v, error: %w", cfg.PrivateKeyFile, err)
+		}
+		cfg.PrivateKey = parsePrivateKey(string(data))
+	}
+	if cfg.AppID == "" || cfg.PrivateKey == "" {
+		return nil, errors.New("missing credentials, set WUJIE_APP_ID and WUJIE_PRIVATE_KEY or WUJIE_PRIVATE_KEY_FILE, or app_id and private_key in config file")
+	}
+	return cfg, nil
+}
+
+// parsePrivateKey get base64 of private key from PEM, other content is trimmed and used as it is
+func parsePrivateKey(s string) string {
+	if !strings.Contains(s, "-----BEGIN") {
+		return strings.TrimSpace(s)
+	}
+	var b strings.Builder
+	for _, line := range strings.Split(s, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "-----") {
+			continue
+		}
+		b.WriteString(line)
+	}
+	return b.String()
+}
+
+// newCaller new caller of cfg, logs go to stderr so that stdout only holds results
+func newCaller(cfg *config, stderr io.Writer, debug bool) (*wujiesdk.Caller, error) {
+	c, err := wujiesdk.NewCredentials(cfg.AppID, cfg.PrivateKey)
+	if err != nil {
+		return nil, fmt.Errorf("wujiesdk.NewCredentials: app id: %v, error: %w", cfg.AppID, err)
+	}
+	client := wujiesdk.NewDefaultClient(c)
+	level := wujiesdk.LogInfo
+	if debug {
+		level = wujiesdk.LogDebug
+	}
+	client.Logger = wujiesdk.NewLogger(level, log.New(stderr, "", log.LstdFlags))
+	if cfg.MaxRetryTimes > 0 {
+		client.MaxRetryTimes = cfg.MaxRetryTimes
+	}
+	if cfg.Language != "" {
+		client.SetLanguage(wujiesdk.Language(cfg.Language))
+	}
+	return wujiesdk.NewCaller(client), nil
+}
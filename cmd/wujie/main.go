@@ -0,0 +1,251 @@
+package main
+
+// @Title        main.go
+// @Description  command line tool of wujie's api
+// @Create       XdpCs 2026-10-19 23:05
+// @Update       XdpCs 2026-10-20 11:30
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/XdpCs/wujiesdk"
+)
+
+const (
+	exitOK    = 0
+	exitError = 1
+	exitUsage = 2
+)
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	a := &app{
+		stdin:     os.Stdin,
+		stdout:    os.Stdout,
+		stderr:    os.Stderr,
+		getenv:    os.Getenv,
+		newCaller: newCaller,
+	}
+	os.Exit(a.run(ctx, os.Args[1:]))
+}
+
+// app runs one command line, its fields are replaced in tests of the tool
+type app struct {
+	stdin     io.Reader
+	stdout    io.Writer
+	stderr    io.Writer
+	getenv    func(string) string
+	newCaller func(cfg *config, stderr io.Writer, debug bool) (*wujiesdk.Caller, error)
+}
+
+// env is what a command runs with
+type env struct {
+	*app
+	caller   *wujiesdk.Caller
+	args     []string
+	wait     bool
+	interval time.Duration
+	download string
+}
+
+// runFunc runs a command and returns the result to print
+type runFunc func(ctx context.Context, e *env) (interface{}, error)
+
+// command is a leaf of the command tree, setup defines flags of the command and returns the runner
+type command struct {
+	name  string
+	args  string
+	desc  string
+	setup func(fs *flag.FlagSet) runFunc
+}
+
+// group is a group of commands as the README groups the api
+type group struct {
+	name     string
+	desc     string
+	commands []command
+}
+
+func (a *app) run(ctx context.Context, args []string) int {
+	if len(args) == 0 || isHelp(args[0]) {
+		a.usage()
+		return exitUsage
+	}
+	g, ok := findGroup(args[0])
+	if !ok {
+		fmt.Fprintf(a.stderr, "wujie: unknown command %q\n", args[0])
+		a.usage()
+		return exitUsage
+	}
+	if len(args) == 1 || isHelp(args[1]) {
+		a.groupUsage(g)
+		return exitUsage
+	}
+	cmd, ok := g.find(args[1])
+	if !ok {
+		fmt.Fprintf(a.stderr, "wujie %s: unknown command %q\n", g.name, args[1])
+		a.groupUsage(g)
+		return exitUsage
+	}
+
+	fs := flag.NewFlagSet("wujie "+g.name+" "+cmd.name, flag.ContinueOnError)
+	fs.SetOutput(a.stderr)
+	configPath := fs.String("config", "", "config file, $WUJIE_CONFIG or wujie/config.json in user config dir by default")
+	output := fs.String("o", "json", "output format, json or table")
+	debug := fs.Bool("debug", false, "log http requests and responses to stderr")
+	timeout := fs.Duration("timeout", 0, "timeout of the whole command, 0 means no timeout")
+	e := &env{app: a}
+	fs.BoolVar(&e.wait, "wait", false, "poll created or queried jobs until they finish")
+	fs.DurationVar(&e.interval, "interval", wujiesdk.DefaultJobPollInterval, "interval of polling with --wait")
+	fs.StringVar(&e.download, "download", "", "download results to `dir`, implies --wait")
+	runner := cmd.setup(fs)
+	fs.Usage = func() {
+		synopsis := fs.Name() + " [flags]"
+		if cmd.args != "" {
+			synopsis += " " + cmd.args
+		}
+		fmt.Fprintf(a.stderr, "Usage: %s\n\n%s\n\nFlags:\n", synopsis, cmd.desc)
+		fs.PrintDefaults()
+	}
+	positional, err := parseFlags(fs, args[2:])
+	if err != nil {
+		// flag reported the error and printed usage
+		return exitUsage
+	}
+	e.args = positional
+	e.wait = e.wait || e.download != ""
+	if *output != "json" && *output != "table" {
+		fmt.Fprintf(a.stderr, "wujie: unknown output format %q\n", *output)
+		return exitUsage
+	}
+
+	cfg, err := loadConfig(*configPath, a.getenv)
+	if err != nil {
+		fmt.Fprintf(a.stderr, "wujie: %v\n", err)
+		return exitError
+	}
+	e.caller, err = a.newCaller(cfg, a.stderr, *debug)
+	if err != nil {
+		fmt.Fprintf(a.stderr, "wujie: %v\n", err)
+		return exitError
+	}
+	if *timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
+
+	result, err := runner(ctx, e)
+	var usageErr *usageError
+	if errors.As(err, &usageErr) {
+		fmt.Fprintf(a.stderr, "wujie %s %s: %v\n", g.name, cmd.name, usageErr.msg)
+		fs.Usage()
+		return exitUsage
+	}
+	// failed jobs still have a result worth printing
+	if result != nil {
+		if perr := writeResult(a.stdout, *output, result); perr != nil {
+			fmt.Fprintf(a.stderr, "wujie: %v\n", perr)
+			return exitError
+		}
+	}
+	if err != nil {
+		fmt.Fprintf(a.stderr, "wujie %s %s: %v\n", g.name, cmd.name, err)
+		return exitError
+	}
+	return exitOK
+}
+
+// usageError is returned by commands called with wrong arguments
+type usageError struct {
+	msg string
+}
+
+func (u *usageError) Error() string {
+	return u.msg
+}
+
+func usagef(format string, a ...interface{}) error {
+	return &usageError{msg: fmt.Sprintf(format, a...)}
+}
+
+// parseFlags parse flags mixed with positional arguments, such as "info KEY --wait",
+// arguments after "--" are positional even if they start with "-"
+func parseFlags(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		rest := fs.Args()
+		if consumed := len(args) - len(rest); consumed > 0 && args[consumed-1] == "--" {
+			return append(positional, rest...), nil
+		}
+		if len(rest) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, rest[0])
+		args = rest[1:]
+	}
+}
+
+func isHelp(arg string) bool {
+	return arg == "help" || arg == "-h" || arg == "-help" || arg == "--help"
+}
+
+func findGroup(name string) (*group, bool) {
+	for i := range groups {
+		if groups[i].name == name {
+			return &groups[i], true
+		}
+	}
+	return nil, false
+}
+
+func (g *group) find(name string) (*command, bool) {
+	for i := range g.commands {
+		if g.commands[i].name == name {
+			return &g.commands[i], true
+		}
+	}
+	return nil, false
+}
+
+func (a *app) usage() {
+	fmt.Fprintln(a.stderr, "Usage: wujie <group> <command> [flags] [args]")
+	fmt.Fprintln(a.stderr)
+	fmt.Fprintln(a.stderr, "Groups:")
+	for _, g := range groups {
+		fmt.Fprintf(a.stderr, "  %-8s %s\n", g.name, g.desc)
+	}
+	fmt.Fprintln(a.stderr)
+	fmt.Fprintln(a.stderr, "Credentials are read from $WUJIE_APP_ID and $WUJIE_PRIVATE_KEY or $WUJIE_PRIVATE_KEY_FILE,")
+	fmt.Fprintln(a.stderr, "or from the config file, run \"wujie <group> <command> -h\" for flags of a command.")
+}
+
+func (a *app) groupUsage(g *group) {
+	fmt.Fprintf(a.stderr, "Usage: wujie %s <command> [flags] [args]\n\n%s\n\nCommands:\n", g.name, g.desc)
+	names := make([]string, 0, len(g.commands))
+	width := 0
+	for _, c := range g.commands {
+		names = append(names, c.name)
+		if len(c.name) > width {
+			width = len(c.name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		c, _ := g.find(name)
+		fmt.Fprintf(a.stderr, "  %s%s  %s\n", name, strings.Repeat(" ", width-len(name)), c.desc)
+	}
+}
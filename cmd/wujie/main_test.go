@@ -0,0 +1,128 @@
+package main
+
+// @Title        main_test.go
+// @Description  test command line tool against fake server
+// @Create       XdpCs 2026-10-20 11:30
+// @Update       XdpCs 2026-10-20 11:30
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/XdpCs/wujiesdk"
+	"github.com/XdpCs/wujiesdk/wujietest"
+)
+
+func TestParseFlags(t *testing.T) {
+	tests := []struct {
+		args       []string
+		positional []string
+		wait       bool
+	}{
+		{args: []string{"KEY", "--wait"}, positional: []string{"KEY"}, wait: true},
+		{args: []string{"--wait", "A", "B"}, positional: []string{"A", "B"}, wait: true},
+		{args: []string{"A", "--", "-B", "--wait"}, positional: []string{"A", "-B", "--wait"}},
+		{args: []string{"--", "-A"}, positional: []string{"-A"}},
+		{args: []string{"A", "--"}, positional: []string{"A"}},
+	}
+	for _, tt := range tests {
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		wait := fs.Bool("wait", false, "")
+		positional, err := parseFlags(fs, tt.args)
+		if err != nil {
+			t.Fatalf("parseFlags(%q) error: %v", tt.args, err)
+		}
+		if !reflect.DeepEqual(positional, tt.positional) || *wait != tt.wait {
+			t.Errorf("parseFlags(%q) = %q, wait: %v, want %q, wait: %v", tt.args, positional, *wait, tt.positional, tt.wait)
+		}
+	}
+}
+
+// newTestApp new app whose caller talks to s
+func newTestApp(t *testing.T, s *wujietest.Server) (*app, *bytes.Buffer, *bytes.Buffer) {
+	t.Helper()
+	configPath := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(configPath, []byte(`{"app_id": "app", "private_key": "key"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	a := &app{
+		stdin:  strings.NewReader(""),
+		stdout: stdout,
+		stderr: stderr,
+		getenv: func(name string) string {
+			if name == "WUJIE_CONFIG" {
+				return configPath
+			}
+			return ""
+		},
+		newCaller: func(_ *config, _ io.Writer, _ bool) (*wujiesdk.Caller, error) {
+			return s.Caller(), nil
+		},
+	}
+	return a, stdout, stderr
+}
+
+func TestRunImageCreateWait(t *testing.T) {
+	s := wujietest.NewServer(wujietest.WithTiming(0, 0))
+	defer s.Close()
+	a, stdout, stderr := newTestApp(t, s)
+	code := a.run(context.Background(), []string{"image", "create", "--model", "1", "--prompt", "a cat", "--num", "2", "--wait", "--interval", "10ms"})
+	if code != exitOK {
+		t.Fatalf("exit code: %d, stderr: %s", code, stderr)
+	}
+	var infos []wujiesdk.ImageGeneratingInfo
+	if err := json.Unmarshal(stdout.Bytes(), &infos); err != nil {
+		t.Fatalf("json.Unmarshal: %v, stdout: %s", err, stdout)
+	}
+	if len(infos) != 2 {
+		t.Fatalf("got %d infos, want 2", len(infos))
+	}
+	for _, info := range infos {
+		if wujiesdk.JobStatus(info.Status) != wujiesdk.SuccessJobStatus {
+			t.Errorf("key: %s, status: %d, want success", info.Key, info.Status)
+		}
+	}
+}
+
+func TestRunImageInfoFlagsAfterKeys(t *testing.T) {
+	s := wujietest.NewServer(wujietest.WithTiming(0, 0))
+	defer s.Close()
+	job, err := s.Caller().SubmitImage(context.Background(), &wujiesdk.CreateImageRequest{Model: 1, Prompt: "a cat", Num: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, stdout, stderr := newTestApp(t, s)
+	code := a.run(context.Background(), append(append([]string{"image", "info"}, job.Keys...), "--wait", "--interval", "10ms", "-o", "table"))
+	if code != exitOK {
+		t.Fatalf("exit code: %d, stderr: %s", code, stderr)
+	}
+	if !strings.Contains(stdout.String(), job.Keys[0]) {
+		t.Errorf("stdout: %s, want key %s", stdout, job.Keys[0])
+	}
+}
+
+func TestRunUsage(t *testing.T) {
+	s := wujietest.NewServer(wujietest.WithTiming(time.Hour, time.Hour))
+	defer s.Close()
+	for _, args := range [][]string{
+		{"image", "info"},
+		{"lab", "info", "KEY", "--type", "PICTURE"},
+		{"image", "info", "KEY", "-o", "xml"},
+		{"nothing"},
+	} {
+		a, _, stderr := newTestApp(t, s)
+		if code := a.run(context.Background(), args); code != exitUsage {
+			t.Errorf("run(%q) exit code: %d, want %d, stderr: %s", args, code, exitUsage, stderr)
+		}
+	}
+}
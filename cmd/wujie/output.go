@@ -0,0 +1,150 @@
+package main
+
+// @Title        output.go
+// @Description  print results as json or table
+// @Create       XdpCs 2026-10-19 23:05
+// @Update       XdpCs 2026-10-19 23:05
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+// writeResult write result in format json or table
+func writeResult(w io.Writer, format string, result interface{}) error {
+	if format == "table" {
+		return writeTable(w, result)
+	}
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(result); err != nil {
+		return fmt.Errorf("json.Encoder.Encode: error: %w", err)
+	}
+	return nil
+}
+
+// writeTable write a slice of structs as rows of their scalar fields, a struct or map as rows of name and value,
+// and anything else as it is, nested values are written as json
+func writeTable(w io.Writer, result interface{}) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	v := indirect(reflect.ValueOf(result))
+	switch {
+	case !v.IsValid():
+	case v.Kind() == reflect.Slice && indirect(reflect.New(v.Type().Elem()).Elem()).Kind() == reflect.Struct:
+		writeRows(tw, v)
+	case v.Kind() == reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			fmt.Fprintln(tw, cell(v.Index(i)))
+		}
+	case v.Kind() == reflect.Struct:
+		for _, f := range fieldsOf(v) {
+			fmt.Fprintf(tw, "%s\t%s\n", f.name, cell(f.value))
+		}
+	case v.Kind() == reflect.Map:
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return fmt.Sprint(keys[i].Interface()) < fmt.Sprint(keys[j].Interface())
+		})
+		for _, k := range keys {
+			fmt.Fprintf(tw, "%v\t%s\n", k.Interface(), cell(v.MapIndex(k)))
+		}
+	default:
+		fmt.Fprintln(tw, cell(v))
+	}
+	if err := tw.Flush(); err != nil {
+		return fmt.Errorf("tabwriter.Writer.Flush: error: %w", err)
+	}
+	return nil
+}
+
+func writeRows(w io.Writer, v reflect.Value) {
+	var columns []string
+	for i := 0; i < v.Len(); i++ {
+		var row []string
+		for _, f := range fieldsOf(indirect(v.Index(i))) {
+			if !isScalar(f.value) {
+				continue
+			}
+			if i == 0 {
+				columns = append(columns, strings.ToUpper(f.name))
+			}
+			row = append(row, cell(f.value))
+		}
+		if i == 0 {
+			fmt.Fprintln(w, strings.Join(columns, "\t"))
+		}
+		fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+}
+
+type field struct {
+	name  string
+	value reflect.Value
+}
+
+// fieldsOf get exported fields of struct named by json tag, fields of embedded structs are flattened
+func fieldsOf(v reflect.Value) []field {
+	if v.Kind() != reflect.Struct {
+		return []field{{name: "value", value: v}}
+	}
+	var fields []field
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if sf.PkgPath != "" {
+			continue
+		}
+		name := strings.Split(sf.Tag.Get("json"), ",")[0]
+		if name == "-" {
+			continue
+		}
+		if sf.Anonymous && name == "" && indirect(v.Field(i)).Kind() == reflect.Struct {
+			fields = append(fields, fieldsOf(indirect(v.Field(i)))...)
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+		fields = append(fields, field{name: name, value: v.Field(i)})
+	}
+	return fields
+}
+
+func indirect(v reflect.Value) reflect.Value {
+	for v.IsValid() && (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+func isScalar(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Struct, reflect.Slice, reflect.Array, reflect.Map, reflect.Interface, reflect.Ptr:
+		return false
+	}
+	return true
+}
+
+// cell format value in one line
+func cell(v reflect.Value) string {
+	if !v.IsValid() {
+		return ""
+	}
+	if isScalar(v) {
+		return fmt.Sprint(v.Interface())
+	}
+	data, err := json.Marshal(v.Interface())
+	if err != nil {
+		return fmt.Sprint(v.Interface())
+	}
+	return string(data)
+}
@@ -0,0 +1,82 @@
+package main
+
+// @Title        wait.go
+// @Description  poll jobs until they finish and download their results
+// @Create       XdpCs 2026-10-19 23:05
+// @Update       XdpCs 2026-10-20 11:10
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/XdpCs/wujiesdk"
+)
+
+// finish return created as it is without --wait, otherwise poll keys of product until they finish and download results
+func (e *env) finish(ctx context.Context, created interface{}, product wujiesdk.Product, keys ...string) (interface{}, error) {
+	if !e.wait {
+		return created, nil
+	}
+	return e.poll(ctx, product, keys)
+}
+
+// query poll keys of product until they finish with --wait, otherwise get the current result of them once,
+// err is set with the result when a finished job failed
+func (e *env) query(ctx context.Context, product wujiesdk.Product, keys ...string) (interface{}, error) {
+	if !e.wait {
+		state, err := e.caller.PollJob(ctx, product, keys)
+		if err != nil {
+			return nil, err
+		}
+		return state.Result, state.Err
+	}
+	return e.poll(ctx, product, keys)
+}
+
+func (e *env) poll(ctx context.Context, product wujiesdk.Product, keys []string) (interface{}, error) {
+	state, err := e.caller.WaitJob(ctx, product, keys, e.interval)
+	if err != nil {
+		if state != nil {
+			return state.Result, err
+		}
+		return nil, err
+	}
+	err = state.Err
+	if e.download != "" {
+		if derr := e.downloadAll(ctx, state.Result); derr != nil && err == nil {
+			err = derr
+		}
+	}
+	return state.Result, err
+}
+
+// downloadAll download assets of result to --download dir and report saved files to stderr
+func (e *env) downloadAll(ctx context.Context, result interface{}) error {
+	d := wujiesdk.NewDownloader(e.caller.Client)
+	var failed int
+	for _, r := range d.DownloadAll(ctx, wujiesdk.AssetsOf(result), e.download) {
+		if r.Err != nil {
+			failed++
+			fmt.Fprintf(e.stderr, "download %s: %v\n", r.Asset.Url, r.Err)
+			continue
+		}
+		fmt.Fprintf(e.stderr, "saved %s\n", r.Path)
+	}
+	if failed > 0 {
+		return fmt.Errorf("download: %d assets failed", failed)
+	}
+	return nil
+}
+
+// labProduct get product of lab jobs of aiType
+func labProduct(aiType wujiesdk.LabInfoType) (wujiesdk.Product, bool) {
+	switch aiType {
+	case wujiesdk.SegmentationLabInfoType:
+		return wujiesdk.SegmentationProduct, true
+	case wujiesdk.InfiniteZoomLabInfoType:
+		return wujiesdk.InfiniteZoomProduct, true
+	case wujiesdk.VectorLabInfoType:
+		return wujiesdk.VectorStudioProduct, true
+	}
+	return "", false
+}
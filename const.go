@@ -3,13 +3,10 @@ package wujiesdk
 // @Title        const.go
 // @Description  wujie sdk's const
 // @Create       XdpCs 2023-09-10 20:47
-// @Update       XdpCs 2024-01-29 14:18
+// @Update       XdpCs 2026-10-19 10:12
 
 import (
-	"fmt"
 	"time"
-
-	"errors"
 )
 
 type WujieRouter string
@@ -143,50 +140,28 @@ const (
 	SideFaceDetectedWujieCode                    WujieCode = "20110021"
 )
 
+// String get the message of WujieCode in DefaultLanguage
 func (w WujieCode) String() string {
-	switch w {
-	case OKWujieCode:
-		return "请求成功"
-	case InvalidParameterWujieCode:
-		return "非法参数"
-	case UnsupportedResolutionWujieCode:
-		return "暂时无法支持的尺寸/分辨率"
-	case LockRaceConditionWujieCode:
-		return "由锁竞争导致的作画失败（需要重新发起）"
-	case PromptTranslationFailedWujieCode:
-		return "文本语言翻译失败"
-	case PromptContainsSensitiveWordsWujieCode:
-		return "画面描述含有敏感词"
-	case InitImageLinkIncorrectOrUnsupportedWujieCode:
-		return "底图链接信息有误或不支持"
-	case InitImageContainsSensitiveInfoWujieCode:
-		return "参考图含有敏感信息"
-	case InsufficientPointsBalanceWujieCode:
-		return "积分余额不足"
-	case CheckResourcesWujieCode:
-		return "检测资源"
-	case ImageRecognitionAbnormalityWujieCode:
-		return "图片识别异常"
-	case NoFaceOrFaceIsSmallWujieCode:
-		return "未检测到人脸或人脸太小"
-	case MultipleFacesDetectedWujieCode:
-		return "检测到多张人脸"
-	case SideFaceDetectedWujieCode:
-		return "检测到侧脸"
-	case JobNotInQueueAndCannotCancelWujieCode:
-		return "该作品不在排队中，无法撤销"
-	case ErrorWujieCode:
-		return "非无界报错返回"
-	case ImageStatusChange:
-		return "作品状态改变，请刷新后查看"
-	default:
-		return fmt.Sprintf("code: %v 未知错误", string(w))
-	}
+	return w.Message(DefaultLanguage)
+}
+
+// Message get the message of WujieCode in lang from DefaultMessageCatalog
+func (w WujieCode) Message(lang Language) string {
+	return DefaultMessageCatalog.Message(lang, w)
 }
 
+// Err get the error of WujieCode in DefaultLanguage, OKWujieCode has no error
 func (w WujieCode) Err() error {
-	if w != OKWujieCode {
-		return errors.New(w.String())
+	return w.ErrIn(DefaultMessageCatalog, DefaultLanguage)
+}
+
+// ErrIn get the error of WujieCode whose message is from catalog in lang, OKWujieCode has no error
+func (w WujieCode) ErrIn(catalog *MessageCatalog, lang Language) error {
+	if w == OKWujieCode {
+		return nil
+	}
+	if catalog == nil {
+		catalog = DefaultMessageCatalog
 	}
-	return nil
+	return &WujieCodeError{Code: w, Language: lang, Message: catalog.Message(lang, w)}
 }
@@ -0,0 +1,334 @@
+package wujiesdk
+
+// @Title        controlnet.go
+// @Description  resolve controlnet param by options of ControlNetOptionPro
+// @Create       XdpCs 2026-10-19 16:40
+// @Update       XdpCs 2026-10-20 14:00
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/patrickmn/go-cache"
+)
+
+// Available reports whether the range applies, preprocessors without such setting have zero range
+func (c *ControlNetRange) Available() bool {
+	return c.Min != 0 || c.Max != 0
+}
+
+// Clamp clamp value into [Min, Max] and round it to the nearest step from Min
+func (c *ControlNetRange) Clamp(value int) int {
+	if !c.Available() {
+		return 0
+	}
+	if value < c.Min {
+		value = c.Min
+	}
+	if value > c.Max {
+		value = c.Max
+	}
+	if c.Step > 1 {
+		steps := (value - c.Min + c.Step/2) / c.Step
+		value = c.Min + steps*c.Step
+		if value > c.Max {
+			value -= c.Step
+		}
+	}
+	return value
+}
+
+// Contains report whether value is in [Min, Max] and on a step from Min
+func (c *ControlNetRange) Contains(value int) bool {
+	if !c.Available() {
+		return value == 0
+	}
+	if value < c.Min || value > c.Max {
+		return false
+	}
+	return c.Step <= 1 || (value-c.Min)%c.Step == 0
+}
+
+func (c *ControlNetRange) validate(v *ValidationErrors, field string, value int) {
+	if c.Contains(value) {
+		return
+	}
+	if !c.Available() {
+		v.add(field, "%d is not supported by the preprocessor", value)
+		return
+	}
+	v.add(field, "%d is not in [%d, %d] with step %d", value, c.Min, c.Max, c.Step)
+}
+
+// ControlNetParamOption is option of ControlNetResolver.Resolve
+type ControlNetParamOption func(*controlNetSpec)
+
+type controlNetSpec struct {
+	preprocessor string
+	model        string
+	processorRes *int
+	thresholdA   *int
+	thresholdB   *int
+	weight       *int
+	endingStep   *int
+	param        ControlNetParam
+}
+
+// defaultControlWeight and defaultEndingControlStep are used when ControlNetOptionPro has no such defaults,
+// control applies with full weight until the last step
+const (
+	defaultControlWeight     = 1
+	defaultEndingControlStep = 1
+)
+
+// WithControlNetPreprocessor select preprocessor by name or code, default preprocessor of the type is used without it
+func WithControlNetPreprocessor(nameOrCode string) ControlNetParamOption {
+	return func(c *controlNetSpec) {
+		c.preprocessor = nameOrCode
+	}
+}
+
+// WithControlNetModel select model by name or code, default model of the type is used without it
+func WithControlNetModel(nameOrCode string) ControlNetParamOption {
+	return func(c *controlNetSpec) {
+		c.model = nameOrCode
+	}
+}
+
+// WithControlNetProcessorRes set processor resolution, default value of the preprocessor is used without it
+func WithControlNetProcessorRes(res int) ControlNetParamOption {
+	return func(c *controlNetSpec) {
+		c.processorRes = &res
+	}
+}
+
+// WithControlNetThresholdA set threshold a, default value of the preprocessor is used without it
+func WithControlNetThresholdA(threshold int) ControlNetParamOption {
+	return func(c *controlNetSpec) {
+		c.thresholdA = &threshold
+	}
+}
+
+// WithControlNetThresholdB set threshold b, default value of the preprocessor is used without it
+func WithControlNetThresholdB(threshold int) ControlNetParamOption {
+	return func(c *controlNetSpec) {
+		c.thresholdB = &threshold
+	}
+}
+
+// WithControlNetImage set control image and its size
+func WithControlNetImage(url string, width, height int) ControlNetParamOption {
+	return func(c *controlNetSpec) {
+		c.param.ImageUrl, c.param.ImageWidth, c.param.ImageHeight = url, width, height
+	}
+}
+
+// WithControlNetMask set mask and mask url
+func WithControlNetMask(mask, maskUrl string) ControlNetParamOption {
+	return func(c *controlNetSpec) {
+		c.param.Mask, c.param.MaskUrl = mask, maskUrl
+	}
+}
+
+// WithControlNetWeight set control weight, 1 is used without it
+func WithControlNetWeight(weight int) ControlNetParamOption {
+	return func(c *controlNetSpec) {
+		c.weight = &weight
+	}
+}
+
+// WithControlNetSteps set starting and ending control step, 0 and 1 are used without it
+func WithControlNetSteps(starting, ending int) ControlNetParamOption {
+	return func(c *controlNetSpec) {
+		c.param.StartingControlStep, c.endingStep = starting, &ending
+	}
+}
+
+// WithControlNetMode set control mode and resize mode
+func WithControlNetMode(controlMode, resizeMode int) ControlNetParamOption {
+	return func(c *controlNetSpec) {
+		c.param.ControlMode, c.param.ResizeMode = controlMode, resizeMode
+	}
+}
+
+// WithControlNetPixelPerfect set pixel perfect
+func WithControlNetPixelPerfect(pixelPerfect bool) ControlNetParamOption {
+	return func(c *controlNetSpec) {
+		c.param.PixelPerfect = pixelPerfect
+	}
+}
+
+// ControlNetResolver resolves ControlNetParam from control type by cached ControlNetOptionPro,
+// values out of advertised ranges are clamped, or rejected when Strict is true
+type ControlNetResolver struct {
+	Caller *Caller
+	Strict bool
+	cache  *cache.Cache
+	once   sync.Once
+}
+
+// NewControlNetResolver new controlnet resolver, options are fetched by c and cached
+func NewControlNetResolver(c *Caller) *ControlNetResolver {
+	return &ControlNetResolver{Caller: c}
+}
+
+// Options get cached ControlNetOptionPro
+func (r *ControlNetResolver) Options(ctx context.Context) ([]ControlNetOptionPro, error) {
+	const cacheKey = "controlnet_options"
+	if options, found := r.optionCache().Get(cacheKey); found {
+		return options.([]ControlNetOptionPro), nil
+	}
+	_, options, err := r.Caller.ControlNetOptionPro(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("r.Caller.ControlNetOptionPro: %w", err)
+	}
+	r.optionCache().Set(cacheKey, options, cache.DefaultExpiration)
+	return options, nil
+}
+
+// ControlType get option of control type by name or code
+func (r *ControlNetResolver) ControlType(ctx context.Context, nameOrCode string) (*ControlNetOptionPro, error) {
+	options, err := r.Options(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range options {
+		if matchNameOrCode(nameOrCode, options[i].Name, options[i].Code) {
+			return &options[i], nil
+		}
+	}
+	return nil, &ValidationError{Field: "type", Reason: fmt.Sprintf("unknown control type %s", nameOrCode)}
+}
+
+// Resolve get ControlNetParam of control type with default preprocessor, model, ranges, weight and steps filled
+func (r *ControlNetResolver) Resolve(ctx context.Context, controlType string, options ...ControlNetParamOption) (*ControlNetParam, error) {
+	option, err := r.ControlType(ctx, controlType)
+	if err != nil {
+		return nil, err
+	}
+	spec := &controlNetSpec{}
+	for _, o := range options {
+		o(spec)
+	}
+	var v ValidationErrors
+	preprocessor := selectPreprocessor(&v, option, spec.preprocessor)
+	model := selectModel(&v, option, spec.model)
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+	param := spec.param
+	param.Type = option.Code
+	if model != nil {
+		param.Model = model.Code
+	}
+	if preprocessor == nil {
+		preprocessor = &ControlNetPreprocessorOption{}
+	}
+	param.Preprocessor = preprocessor.Code
+	param.ProcessorRes = r.rangeValue(&v, "processor_res", &preprocessor.Resolution, spec.processorRes)
+	param.ThresholdA = r.rangeValue(&v, "threshold_a", &preprocessor.ThresholdA, spec.thresholdA)
+	param.ThresholdB = r.rangeValue(&v, "threshold_b", &preprocessor.ThresholdB, spec.thresholdB)
+	param.ControlWeight = intOr(spec.weight, defaultControlWeight)
+	param.EndingControlStep = intOr(spec.endingStep, defaultEndingControlStep)
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+	return &param, nil
+}
+
+// Validate check preprocessor, model and ranges of param against ControlNetOptionPro
+func (r *ControlNetResolver) Validate(ctx context.Context, param *ControlNetParam) error {
+	option, err := r.ControlType(ctx, strconv.Itoa(param.Type))
+	if err != nil {
+		return err
+	}
+	var v ValidationErrors
+	preprocessor := selectPreprocessor(&v, option, optionCode(param.Preprocessor, len(option.Preprocessor)))
+	selectModel(&v, option, optionCode(int(param.Model), len(option.Model)))
+	if preprocessor != nil {
+		preprocessor.Resolution.validate(&v, "processor_res", param.ProcessorRes)
+		preprocessor.ThresholdA.validate(&v, "threshold_a", param.ThresholdA)
+		preprocessor.ThresholdB.validate(&v, "threshold_b", param.ThresholdB)
+	}
+	return v.err()
+}
+
+// optionCode get code to select from options, zero code of control type without options means none
+func optionCode(code, options int) string {
+	if code == 0 && options == 0 {
+		return ""
+	}
+	return strconv.Itoa(code)
+}
+
+func (r *ControlNetResolver) rangeValue(v *ValidationErrors, field string, c *ControlNetRange, value *int) int {
+	if value == nil {
+		return c.Value
+	}
+	if r.Strict {
+		c.validate(v, field, *value)
+		return *value
+	}
+	return c.Clamp(*value)
+}
+
+func intOr(value *int, defaultValue int) int {
+	if value == nil {
+		return defaultValue
+	}
+	return *value
+}
+
+func selectPreprocessor(v *ValidationErrors, option *ControlNetOptionPro, nameOrCode string) *ControlNetPreprocessorOption {
+	for i, p := range option.Preprocessor {
+		if (nameOrCode == "" && p.IsDefault) || (nameOrCode != "" && matchNameOrCode(nameOrCode, p.Name, p.Code)) {
+			return &option.Preprocessor[i]
+		}
+	}
+	if nameOrCode == "" {
+		// control type without preprocessor uses zero code
+		if len(option.Preprocessor) > 0 {
+			return &option.Preprocessor[0]
+		}
+		return nil
+	}
+	v.add("preprocessor", "%s is not compatible with control type %s", nameOrCode, option.Name)
+	return nil
+}
+
+func selectModel(v *ValidationErrors, option *ControlNetOptionPro, nameOrCode string) *ControlNetModelOption {
+	for i, m := range option.Model {
+		if (nameOrCode == "" && m.IsDefault) || (nameOrCode != "" && matchNameOrCode(nameOrCode, m.Name, int(m.Code))) {
+			return &option.Model[i]
+		}
+	}
+	if nameOrCode == "" {
+		// control type without model uses zero code
+		if len(option.Model) > 0 {
+			return &option.Model[0]
+		}
+		return nil
+	}
+	v.add("model", "%s is not compatible with control type %s", nameOrCode, option.Name)
+	return nil
+}
+
+func matchNameOrCode(nameOrCode, name string, code int) bool {
+	if strings.EqualFold(strings.TrimSpace(nameOrCode), name) {
+		return true
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(nameOrCode))
+	return err == nil && n == code
+}
+
+// optionCache get cache of controlnet options, it is created on first use so that the zero value is usable
+func (r *ControlNetResolver) optionCache() *cache.Cache {
+	r.once.Do(func() {
+		r.cache = cache.New(30*time.Minute, 10*time.Minute)
+	})
+	return r.cache
+}
@@ -0,0 +1,53 @@
+package wujiesdk_test
+
+// @Title        controlnet_test.go
+// @Description  test controlnet resolver against fake server
+// @Create       XdpCs 2026-10-20 14:00
+// @Update       XdpCs 2026-10-20 14:00
+
+import (
+	"context"
+	"testing"
+
+	"github.com/XdpCs/wujiesdk"
+	"github.com/XdpCs/wujiesdk/wujietest"
+)
+
+func TestControlNetResolverDefaults(t *testing.T) {
+	s := wujietest.NewServer()
+	defer s.Close()
+	// the zero value resolver creates its cache on first use
+	r := &wujiesdk.ControlNetResolver{Caller: s.Caller()}
+	param, err := r.Resolve(context.Background(), "canny")
+	if err != nil {
+		t.Fatalf("Resolve error: %v", err)
+	}
+	if param.Model != 1 || param.Preprocessor != 1 || param.ProcessorRes != 512 || param.ThresholdA != 100 || param.ThresholdB != 200 {
+		t.Errorf("resolved param: %+v, want defaults of canny", param)
+	}
+	if param.ControlWeight != 1 || param.StartingControlStep != 0 || param.EndingControlStep != 1 {
+		t.Errorf("resolved weight and steps: %d, %d, %d, want 1, 0, 1",
+			param.ControlWeight, param.StartingControlStep, param.EndingControlStep)
+	}
+	if err := r.Validate(context.Background(), param); err != nil {
+		t.Errorf("Validate resolved param error: %v", err)
+	}
+}
+
+func TestControlNetResolverOptions(t *testing.T) {
+	s := wujietest.NewServer()
+	defer s.Close()
+	r := wujiesdk.NewControlNetResolver(s.Caller())
+	param, err := r.Resolve(context.Background(), "1",
+		wujiesdk.WithControlNetWeight(0), wujiesdk.WithControlNetSteps(0, 0), wujiesdk.WithControlNetThresholdA(300))
+	if err != nil {
+		t.Fatalf("Resolve error: %v", err)
+	}
+	if param.ControlWeight != 0 || param.EndingControlStep != 0 || param.ThresholdA != 255 {
+		t.Errorf("resolved param: %+v, want weight and steps as set and threshold a clamped", param)
+	}
+	r.Strict = true
+	if _, err := r.Resolve(context.Background(), "canny", wujiesdk.WithControlNetThresholdA(300)); err == nil {
+		t.Error("strict Resolve error: nil, want threshold a out of range")
+	}
+}
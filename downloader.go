@@ -0,0 +1,452 @@
+package wujiesdk
+
+// @Title        downloader.go
+// @Description  download result assets to io.Writer or files with resume, verification and retry
+// @Create       XdpCs 2026-10-19 17:50
+// @Update       XdpCs 2026-10-19 17:50
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// default settings of Downloader
+const (
+	DefaultDownloadConcurrency   = 4
+	DefaultDownloadRetryTimes    = 3
+	DefaultDownloadRetryInterval = time.Second
+)
+
+// ErrDownloadSizeMismatch is the error of downloaded size different from expected size
+var ErrDownloadSizeMismatch = errors.New("downloaded size mismatch")
+
+// DownloadAsset is one url of a job result
+type DownloadAsset struct {
+	Key   string // key of job
+	Kind  string // kind of asset in job, such as picture, cover, vector
+	Index int    // index of asset of the same kind
+	Url   string
+	Size  int64 // expected size, 0 means unknown
+}
+
+// DownloadNameFunc name file of asset without directory, ext has leading dot
+type DownloadNameFunc func(asset DownloadAsset, ext string) string
+
+// DefaultDownloadName name file as key_kind_index.ext, kind and index are omitted when they are empty or zero
+func DefaultDownloadName(asset DownloadAsset, ext string) string {
+	parts := []string{asset.Key}
+	if asset.Key == "" {
+		parts[0] = strings.TrimSuffix(path.Base(urlPath(asset.Url)), path.Ext(urlPath(asset.Url)))
+	}
+	if asset.Kind != "" {
+		parts = append(parts, asset.Kind)
+	}
+	if asset.Index > 0 {
+		parts = append(parts, strconv.Itoa(asset.Index))
+	}
+	return sanitizeFileName(strings.Join(parts, "_")) + ext
+}
+
+// DownloadResult is the result of downloading one asset
+type DownloadResult struct {
+	Asset       DownloadAsset
+	Path        string // empty when written to io.Writer
+	ContentType string
+	Size        int64
+	Resumed     bool // part of asset is from a previous download
+	Err         error
+}
+
+// AssetsOf get assets of job result, result is one of ImageGeneratingInfo, GeneratingInfoPro, ImageInfoData,
+// SuperSizeInfo, VideoInfo, CameraGeneratingInfo, CameraInfo, SVDInfo, LabInfo,
+// CreateImageCallBackSuccessResp, or pointer or slice of them, empty urls are skipped
+func AssetsOf(result interface{}) []DownloadAsset {
+	var assets []DownloadAsset
+	add := func(key, kind string, index int, u string, size int64) {
+		if u != "" {
+			assets = append(assets, DownloadAsset{Key: key, Kind: kind, Index: index, Url: u, Size: size})
+		}
+	}
+	switch r := result.(type) {
+	case ImageGeneratingInfo:
+		add(r.Key, "", 0, r.PictureURL, 0)
+	case GeneratingInfoPro:
+		add(r.Key, "", 0, r.PictureURL, 0)
+	case ImageInfoData:
+		add("", "", 0, r.PictureUrl, 0)
+	case SuperSizeInfo:
+		add(r.Key, "sr", 0, r.SrURL, 0)
+	case VideoInfo:
+		add(r.Key, "", 0, r.AiVideoUrl, int64(r.AiVideoMetaInfo.Size))
+		add(r.Key, "cover", 0, r.AiVideoMetaInfo.Cover.Url, 0)
+	case CameraGeneratingInfo:
+		add(r.Key, "", 0, r.ArtworkUrl, 0)
+	case CameraInfo:
+		add(r.Key, "", 0, r.ArtworkUrl, 0)
+	case SVDInfo:
+		add(r.Key, "", 0, r.VideoUrl, 0)
+	case CreateImageCallBackSuccessResp:
+		add(r.Key, "", 0, r.ArtworkUrl, 0)
+	case LabInfo:
+		for i, u := range r.SegmentInfo.ImageUrls {
+			add(r.ServiceKey, "segment", i, u, 0)
+		}
+		add(r.ServiceKey, "zoom", 0, r.InfiniteZoomInfo.VideoUrl, 0)
+		for i, v := range r.VectorInfo.VectorRes {
+			add(r.ServiceKey, "vector", i, v.Url, 0)
+		}
+	case *ImageGeneratingInfo:
+		return AssetsOf(*r)
+	case *GeneratingInfoPro:
+		return AssetsOf(*r)
+	case *ImageInfoData:
+		return AssetsOf(*r)
+	case *SuperSizeInfo:
+		return AssetsOf(*r)
+	case *VideoInfo:
+		return AssetsOf(*r)
+	case *VideoGeneratingInfo:
+		return AssetsOf(r.List)
+	case *CameraGeneratingInfo:
+		return AssetsOf(*r)
+	case *CameraInfo:
+		return AssetsOf(*r)
+	case *SVDInfo:
+		return AssetsOf(*r)
+	case *CreateImageCallBackSuccessResp:
+		return AssetsOf(*r)
+	case *LabInfo:
+		return AssetsOf(*r)
+	case []ImageGeneratingInfo:
+		for _, i := range r {
+			assets = append(assets, AssetsOf(i)...)
+		}
+	case []GeneratingInfoPro:
+		for _, i := range r {
+			assets = append(assets, AssetsOf(i)...)
+		}
+	case []SuperSizeInfo:
+		for _, i := range r {
+			assets = append(assets, AssetsOf(i)...)
+		}
+	case []VideoInfo:
+		for _, i := range r {
+			assets = append(assets, AssetsOf(i)...)
+		}
+	case []CameraGeneratingInfo:
+		for _, i := range r {
+			assets = append(assets, AssetsOf(i)...)
+		}
+	}
+	return assets
+}
+
+// Downloader downloads assets with bounded concurrency, it resumes by Range requests,
+// verifies size, retries transient failures and names files by Name, it is safe for concurrent use
+type Downloader struct {
+	HTTPClient    *http.Client
+	Concurrency   int
+	MaxRetryTimes int
+	RetryInterval time.Duration // waited retry times multiple of it before each retry
+	Name          DownloadNameFunc
+	Logger        *Logger
+}
+
+// NewDownloader new downloader with http client and logger of c
+func NewDownloader(c *Client) *Downloader {
+	return &Downloader{
+		HTTPClient:    c.HTTPClient(),
+		Concurrency:   DefaultDownloadConcurrency,
+		MaxRetryTimes: DefaultDownloadRetryTimes,
+		RetryInterval: DefaultDownloadRetryInterval,
+		Name:          DefaultDownloadName,
+		Logger:        c.Logger,
+	}
+}
+
+// Download write asset to w
+func (d *Downloader) Download(ctx context.Context, asset DownloadAsset, w io.Writer) (*DownloadResult, error) {
+	result := &DownloadResult{Asset: asset}
+	if err := d.fetch(ctx, asset, 0, w, result); err != nil {
+		result.Err = err
+		return result, err
+	}
+	return result, nil
+}
+
+// DownloadFile download asset into dir, data is written to a .part file first and renamed when it is verified,
+// an existing .part file is resumed
+func (d *Downloader) DownloadFile(ctx context.Context, asset DownloadAsset, dir string) (*DownloadResult, error) {
+	result := &DownloadResult{Asset: asset}
+	err := d.downloadFile(ctx, asset, dir, result)
+	if err != nil {
+		result.Err = err
+		return result, err
+	}
+	return result, nil
+}
+
+// DownloadAll download assets into dir with bounded concurrency, results are in the order of assets
+func (d *Downloader) DownloadAll(ctx context.Context, assets []DownloadAsset, dir string) []DownloadResult {
+	concurrency := d.Concurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	results := make([]DownloadResult, len(assets))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, asset := range assets {
+		wg.Add(1)
+		go func(i int, asset DownloadAsset) {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				results[i] = DownloadResult{Asset: asset, Err: ctx.Err()}
+				return
+			}
+			result, _ := d.DownloadFile(ctx, asset, dir)
+			results[i] = *result
+		}(i, asset)
+	}
+	wg.Wait()
+	return results
+}
+
+func (d *Downloader) downloadFile(ctx context.Context, asset DownloadAsset, dir string, result *DownloadResult) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("os.MkdirAll: dir: %s, error: %w", dir, err)
+	}
+	partPath := filepath.Join(dir, d.name(asset, "")+".part")
+	f, err := os.OpenFile(partPath, os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("os.OpenFile: path: %s, error: %w", partPath, err)
+	}
+	offset, err := f.Seek(0, io.SeekEnd)
+	if err != nil {
+		_ = f.Close()
+		return fmt.Errorf("f.Seek: path: %s, error: %w", partPath, err)
+	}
+	err = d.fetch(ctx, asset, offset, f, result)
+	if closeErr := f.Close(); err == nil && closeErr != nil {
+		err = fmt.Errorf("f.Close: path: %s, error: %w", partPath, closeErr)
+	}
+	if err != nil {
+		if errors.Is(err, ErrDownloadSizeMismatch) {
+			_ = os.Remove(partPath)
+		}
+		return err
+	}
+	result.Path = filepath.Join(dir, d.name(asset, extensionOf(result.ContentType, asset.Url)))
+	if err := os.Rename(partPath, result.Path); err != nil {
+		return fmt.Errorf("os.Rename: path: %s, error: %w", result.Path, err)
+	}
+	return nil
+}
+
+// fetch write asset from offset to w, w already has offset bytes of asset
+func (d *Downloader) fetch(ctx context.Context, asset DownloadAsset, offset int64, w io.Writer, result *DownloadResult) error {
+	if asset.Url == "" {
+		return fmt.Errorf("Downloader.fetch: key: %s, error: url is empty", asset.Key)
+	}
+	retryTimes := d.MaxRetryTimes
+	if retryTimes < 1 {
+		retryTimes = 1
+	}
+	result.Resumed = offset > 0
+	written := offset
+	var err error
+	for i := 0; i < retryTimes; i++ {
+		if i > 0 {
+			d.writeLog(LogWarn, "Downloader: url: %s, retry %d after error: %v\n", asset.Url, i, err)
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(time.Duration(i) * d.RetryInterval):
+			}
+		}
+		var total int64
+		var retry bool
+		total, retry, err = d.fetchOnce(ctx, asset, &written, w, result)
+		if err == nil {
+			expected := asset.Size
+			if expected <= 0 {
+				expected = total
+			}
+			result.Size = written
+			if expected > 0 && written != expected {
+				return fmt.Errorf("Downloader.fetch: url: %s, expected: %d, got: %d, error: %w", asset.Url, expected, written, ErrDownloadSizeMismatch)
+			}
+			return nil
+		}
+		if !retry || ctx.Err() != nil {
+			break
+		}
+	}
+	result.Size = written
+	return err
+}
+
+// fetchOnce request asset from written, it returns total size when server tells it
+func (d *Downloader) fetchOnce(ctx context.Context, asset DownloadAsset, written *int64, w io.Writer,
+	result *DownloadResult) (total int64, retry bool, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, asset.Url, http.NoBody)
+	if err != nil {
+		return 0, false, fmt.Errorf("http.NewRequestWithContext: url: %v, new request error: %w", asset.Url, err)
+	}
+	if *written > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", *written))
+	}
+	client := d.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, true, fmt.Errorf("client.Do: url: %s, error: %w", asset.Url, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	var skip int64
+	switch {
+	case resp.StatusCode == http.StatusPartialContent:
+		total = contentRangeTotal(resp.Header.Get("Content-Range"))
+	case resp.StatusCode == http.StatusRequestedRangeNotSatisfiable && *written > 0:
+		// the previous download has got every byte
+		total = contentRangeTotal(resp.Header.Get("Content-Range"))
+		if total <= 0 {
+			total = *written
+		}
+		return total, false, nil
+	case resp.StatusCode >= http.StatusOK && resp.StatusCode <= 299:
+		// server ignores Range, skip bytes already written
+		skip = *written
+		if resp.ContentLength >= 0 {
+			total = resp.ContentLength
+		}
+	default:
+		retry = resp.StatusCode >= http.StatusInternalServerError || resp.StatusCode == http.StatusTooManyRequests ||
+			resp.StatusCode == http.StatusRequestTimeout
+		return 0, retry, fmt.Errorf("http status code: %d, %s, url: %s", resp.StatusCode, http.StatusText(resp.StatusCode), asset.Url)
+	}
+	body := io.Reader(resp.Body)
+	if result.ContentType == "" {
+		sniffed, contentType, err := sniffContentType(resp)
+		if err != nil {
+			return 0, true, fmt.Errorf("sniffContentType: url: %s, error: %w", asset.Url, err)
+		}
+		body, result.ContentType = sniffed, contentType
+	}
+	if skip > 0 {
+		if _, err := io.CopyN(io.Discard, body, skip); err != nil {
+			return 0, true, fmt.Errorf("io.CopyN: url: %s, error: %w", asset.Url, err)
+		}
+	}
+	n, err := io.Copy(w, body)
+	*written += n
+	if err != nil {
+		return 0, true, fmt.Errorf("io.Copy: url: %s, error: %w", asset.Url, err)
+	}
+	return total, false, nil
+}
+
+func (d *Downloader) name(asset DownloadAsset, ext string) string {
+	if d.Name == nil {
+		return DefaultDownloadName(asset, ext)
+	}
+	return d.Name(asset, ext)
+}
+
+func (d *Downloader) writeLog(level int, format string, v ...interface{}) {
+	if d.Logger == nil || d.Logger.Logger == nil {
+		return
+	}
+	d.Logger.Printf("%s%s", LogTag[level-1], fmt.Sprintf(format, v...))
+}
+
+// sniffContentType get content type by header, or by the first 512 bytes when header is missing or generic
+func sniffContentType(resp *http.Response) (io.Reader, string, error) {
+	contentType, _, _ := mime.ParseMediaType(resp.Header.Get(ContentType))
+	if contentType != "" && contentType != "application/octet-stream" && contentType != "binary/octet-stream" {
+		return resp.Body, contentType, nil
+	}
+	head := make([]byte, 512)
+	n, err := io.ReadFull(resp.Body, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return nil, "", err
+	}
+	head = head[:n]
+	contentType, _, _ = mime.ParseMediaType(http.DetectContentType(head))
+	return io.MultiReader(strings.NewReader(string(head)), resp.Body), contentType, nil
+}
+
+var contentTypeExtensions = map[string]string{
+	"image/png":       ".png",
+	"image/jpeg":      ".jpg",
+	"image/gif":       ".gif",
+	"image/webp":      ".webp",
+	"image/bmp":       ".bmp",
+	"image/svg+xml":   ".svg",
+	"video/mp4":       ".mp4",
+	"video/webm":      ".webm",
+	"video/quicktime": ".mov",
+	"application/pdf": ".pdf",
+	"application/zip": ".zip",
+}
+
+// extensionOf get file extension by content type, then by url
+func extensionOf(contentType, u string) string {
+	if ext, ok := contentTypeExtensions[contentType]; ok {
+		return ext
+	}
+	if ext := path.Ext(urlPath(u)); ext != "" && len(ext) <= 6 {
+		return strings.ToLower(ext)
+	}
+	if exts, err := mime.ExtensionsByType(contentType); err == nil && len(exts) > 0 {
+		return exts[0]
+	}
+	return ".bin"
+}
+
+func contentRangeTotal(contentRange string) int64 {
+	i := strings.LastIndex(contentRange, "/")
+	if i < 0 {
+		return 0
+	}
+	total, err := strconv.ParseInt(strings.TrimSpace(contentRange[i+1:]), 10, 64)
+	if err != nil {
+		return 0
+	}
+	return total
+}
+
+func urlPath(u string) string {
+	parsed, err := url.Parse(u)
+	if err != nil {
+		return u
+	}
+	return parsed.Path
+}
+
+func sanitizeFileName(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '/', '\\', ':', '*', '?', '"', '<', '>', '|':
+			return '_'
+		}
+		return r
+	}, name)
+}
@@ -3,7 +3,7 @@ package wujiesdk
 // @Title        entity.go
 // @Description  entity
 // @Create       XdpCs 2023-09-10 20:47
-// @Update       XdpCs 2023-11-26 15:13
+// @Update       XdpCs 2026-10-20 13:00
 
 import (
 	"fmt"
@@ -15,6 +15,29 @@ type BaseResponse struct {
 	Success bool   `json:"success"`
 }
 
+// FailMessage is the failure of a job, shared by image, pro, video, camera and svd's info
+type FailMessage struct {
+	FailCode    int    `json:"fail_code"`
+	FailMessage string `json:"fail_message"`
+}
+
+// Failed report whether the job failed
+func (f FailMessage) Failed() bool {
+	return f.FailCode != 0 || f.FailMessage != ""
+}
+
+// Err get the failure as error, it returns nil when the job did not fail
+func (f FailMessage) Err() error {
+	if !f.Failed() {
+		return nil
+	}
+	return f
+}
+
+func (f FailMessage) Error() string {
+	return fmt.Sprintf("fail_code: %v, fail_message: %v", f.FailCode, f.FailMessage)
+}
+
 type AvailableIntegralBalanceResponse struct {
 	BaseResponse
 	Data struct {
@@ -37,11 +60,11 @@ type ModelBaseInfosResponse struct {
 }
 
 type ModelBaseInfo struct {
-	ModelType         int32  `json:"type"`
-	ModelCode         int32  `json:"model_code"`
-	ModelVersion      string `json:"model_version"`
-	ModelDesc         string `json:"model_desc"`
-	ControlNetSupport string `json:"controlnet_support"`
+	ModelType         int32     `json:"type"`
+	ModelCode         ModelCode `json:"model_code"`
+	ModelVersion      string    `json:"model_version"`
+	ModelDesc         string    `json:"model_desc"`
+	ControlNetSupport string    `json:"controlnet_support"`
 }
 
 type DefaultResourceStyleModelResponse struct {
@@ -54,10 +77,10 @@ type DefaultResourceStyleModelData struct {
 }
 
 type StyleModel struct {
-	Key            string `json:"key"`
-	Name           string `json:"name"`
-	ModelCode      int    `json:"model_code"`
-	SampleImageURL string `json:"sample_image_url"`
+	Key            string    `json:"key"`
+	Name           string    `json:"name"`
+	ModelCode      ModelCode `json:"model_code"`
+	SampleImageURL string    `json:"sample_image_url"`
 }
 
 type DefaultResourceModelResponse struct {
@@ -66,90 +89,87 @@ type DefaultResourceModelResponse struct {
 }
 
 type DefaultResourceModelData struct {
-	CreateOptionMenu struct {
-		ImageType []struct {
-			Name     string `json:"name"`
-			Url      string `json:"url"`
-			Category string `json:"category"`
-		} `json:"image_type"`
-		PromptTips []struct {
-			Name string `json:"name"`
-		} `json:"prompt_tips"`
-		Resolution []struct {
-			Width              int       `json:"width"`
-			Height             int       `json:"height"`
-			SuperSizeMultiple  float64   `json:"super_size_multiple"`
-			PrefineMultiples   []float64 `json:"prefine_multiples"`
-			SuperSizeMultiples []float64 `json:"super_size_multiples"`
-			SuperSizeDetails   []struct {
-				Multiple      float64 `json:"multiple"`
-				IntegralPrice int     `json:"integral_price"`
-			} `json:"super_size_details"`
-			PrefineDetails []struct {
-				Multiple      float64 `json:"multiple"`
-				IntegralPrice int     `json:"integral_price"`
-			} `json:"prefine_details"`
-			Url       string `json:"url"`
-			SizeRatio string `json:"size_ratio"`
-		} `json:"resolution"`
-		ResolutionNew struct {
-			ResolutionKey  string `json:"resolution_key"`
-			ResolutionList []struct {
-				Width             int     `json:"width"`
-				Height            int     `json:"height"`
-				SuperSizeMultiple float64 `json:"super_size_multiple"`
-				PrefineMultiples  float64 `json:"prefine_multiples"`
-				DisplayResolution string  `json:"display_resolution"`
-				Url               string  `json:"url"`
-				SizeRatio         string  `json:"size_ratio"`
-			} `json:"resolution_list"`
-		} `json:"resolution_new"`
-		Style []struct {
-			Name     string `json:"name"`
-			Url      string `json:"url"`
-			Category string `json:"category"`
-		} `json:"style"`
-		Artist []struct {
-			Name     string `json:"name"`
-			Url      string `json:"url"`
-			Category string `json:"category"`
-		} `json:"artist"`
-		ElementMagic []struct {
-			Key       string `json:"key"`
-			Name      string `json:"name"`
-			ChoiceKey string `json:"choice_key"`
-		} `json:"element_magic"`
-		StyleDecoration []struct {
-			Key       string `json:"key"`
-			Name      string `json:"name"`
-			ChoiceKey string `json:"choice_key"`
-		} `json:"style_decoration"`
-		Character []struct {
-			Key                  string   `json:"key"`
-			Name                 string   `json:"name"`
-			Category             string   `json:"category"`
-			RecommendedWeight    float64  `json:"recommended_weight"`
-			SupportModelVersions []string `json:"support_model_versions"`
-		} `json:"character"`
-		ModelFusion []struct {
-			Key                  string   `json:"key"`
-			Name                 string   `json:"name"`
-			Category             string   `json:"category"`
-			RecommendedWeight    float64  `json:"recommended_weight"`
-			SupportModelVersions []string `json:"support_model_versions"`
-		} `json:"model_fusion"`
-		Patterns []struct {
-			Name string `json:"name"`
-		} `json:"patterns"`
-		SamplerModels []struct {
-			SamplerModelName string `json:"sampler_model_name"`
-			SamplerIndex     int    `json:"sampler_index"`
-		} `json:"sampler_models"`
-	} `json:"create_option_menu"`
+	CreateOptionMenu CreateOptionMenu `json:"create_option_menu"`
+}
+
+type CreateOptionMenu struct {
+	ImageType       []ResourceOption `json:"image_type"`
+	PromptTips      []ResourceName   `json:"prompt_tips"`
+	Resolution      []Resolution     `json:"resolution"`
+	ResolutionNew   ResolutionNew    `json:"resolution_new"`
+	Style           []ResourceOption `json:"style"`
+	Artist          []ResourceOption `json:"artist"`
+	ElementMagic    []ResourceChoice `json:"element_magic"`
+	StyleDecoration []ResourceChoice `json:"style_decoration"`
+	Character       []FusionOption   `json:"character"`
+	ModelFusion     []FusionOption   `json:"model_fusion"`
+	Patterns        []ResourceName   `json:"patterns"`
+	SamplerModels   []SamplerModel   `json:"sampler_models"`
+}
+
+type ResourceOption struct {
+	Name     string `json:"name"`
+	Url      string `json:"url"`
+	Category string `json:"category"`
+}
+
+type ResourceName struct {
+	Name string `json:"name"`
+}
+
+type ResourceChoice struct {
+	Key       string `json:"key"`
+	Name      string `json:"name"`
+	ChoiceKey string `json:"choice_key"`
+}
+
+type FusionOption struct {
+	Key                  string   `json:"key"`
+	Name                 string   `json:"name"`
+	Category             string   `json:"category"`
+	RecommendedWeight    float64  `json:"recommended_weight"`
+	SupportModelVersions []string `json:"support_model_versions"`
+}
+
+type Resolution struct {
+	Width              int             `json:"width"`
+	Height             int             `json:"height"`
+	SuperSizeMultiple  float64         `json:"super_size_multiple"`
+	PrefineMultiples   []float64       `json:"prefine_multiples"`
+	SuperSizeMultiples []float64       `json:"super_size_multiples"`
+	SuperSizeDetails   []MultiplePrice `json:"super_size_details"`
+	PrefineDetails     []MultiplePrice `json:"prefine_details"`
+	Url                string          `json:"url"`
+	SizeRatio          string          `json:"size_ratio"`
+}
+
+type MultiplePrice struct {
+	Multiple      float64 `json:"multiple"`
+	IntegralPrice int     `json:"integral_price"`
+}
+
+type ResolutionNew struct {
+	ResolutionKey  string              `json:"resolution_key"`
+	ResolutionList []ResolutionNewItem `json:"resolution_list"`
+}
+
+type ResolutionNewItem struct {
+	Width             int     `json:"width"`
+	Height            int     `json:"height"`
+	SuperSizeMultiple float64 `json:"super_size_multiple"`
+	PrefineMultiples  float64 `json:"prefine_multiples"`
+	DisplayResolution string  `json:"display_resolution"`
+	Url               string  `json:"url"`
+	SizeRatio         string  `json:"size_ratio"`
+}
+
+type SamplerModel struct {
+	SamplerModelName string `json:"sampler_model_name"`
+	SamplerIndex     int    `json:"sampler_index"`
 }
 
 type CreateImageRequest struct {
-	Model                int             `json:"model"`
+	Model                ModelCode       `json:"model"`
 	Prompt               string          `json:"prompt"`
 	UcPrompt             string          `json:"uc_prompt,omitempty"`
 	FullyCustomUcPrompt  bool            `json:"fully_custom_uc_prompt,omitempty"`
@@ -230,50 +250,56 @@ func (c *CreateImageRequest) String() string {
 }
 
 type MultiDiffusion struct {
-	TiledDiffusion struct {
-		Enabled                     bool   `json:"enabled"`
-		Method                      string `json:"method"`
-		OverwriteSize               bool   `json:"overwrite_size"`
-		KeepInputSize               bool   `json:"keep_input_size"`
-		ImageWidth                  int    `json:"image_width"`
-		ImageHeight                 int    `json:"image_height"`
-		TileWidth                   int    `json:"tile_width"`
-		TileHeight                  int    `json:"tile_height"`
-		Overlap                     int    `json:"overlap"`
-		TileBatchSize               int    `json:"tile_batch_size"`
-		UpscalerName                string `json:"upscaler_name"`
-		ScaleFactor                 int    `json:"scale_factor"`
-		NoiseInverse                bool   `json:"noise_inverse"`
-		NoiseInverseSteps           int    `json:"noise_inverse_steps"`
-		NoiseInverseRetouch         int    `json:"noise_inverse_retouch"`
-		NoiseInverseRenoiseStrength int    `json:"noise_inverse_renoise_strength"`
-		NoiseInverseRenoiseKernel   int    `json:"noise_inverse_renoise_kernel"`
-		ControlTensorCpu            bool   `json:"control_tensor_cpu"`
-		EnableBboxControl           bool   `json:"enable_bbox_control"`
-		DrawBackground              bool   `json:"draw_background"`
-		CausalLayers                bool   `json:"causal_layers"`
-		BboxControlStates           []struct {
-			Enabled      bool    `json:"enabled"`
-			X            float64 `json:"x"`
-			Y            float64 `json:"y"`
-			W            float64 `json:"w"`
-			H            float64 `json:"h"`
-			Prompt       string  `json:"prompt"`
-			NegPrompt    string  `json:"neg_prompt"`
-			BlendMode    string  `json:"blend_mode"`
-			FeatherRatio float64 `json:"feather_ratio"`
-			Seed         int     `json:"seed"`
-		} `json:"bbox_control_states"`
-	} `json:"tiled_diffusion"`
-	TiledVae struct {
-		Enabled         bool `json:"enabled"`
-		EncoderTileSize int  `json:"encoder_tile_size"`
-		DecoderTileSize int  `json:"decoder_tile_size"`
-		VaeToGpu        bool `json:"vae_to_gpu"`
-		FastDecoder     bool `json:"fast_decoder"`
-		FastEncoder     bool `json:"fast_encoder"`
-		ColorFix        bool `json:"color_fix"`
-	} `json:"tiled_vae"`
+	TiledDiffusion TiledDiffusion `json:"tiled_diffusion"`
+	TiledVae       TiledVae       `json:"tiled_vae"`
+}
+
+type TiledDiffusion struct {
+	Enabled                     bool                             `json:"enabled"`
+	Method                      string                           `json:"method"`
+	OverwriteSize               bool                             `json:"overwrite_size"`
+	KeepInputSize               bool                             `json:"keep_input_size"`
+	ImageWidth                  int                              `json:"image_width"`
+	ImageHeight                 int                              `json:"image_height"`
+	TileWidth                   int                              `json:"tile_width"`
+	TileHeight                  int                              `json:"tile_height"`
+	Overlap                     int                              `json:"overlap"`
+	TileBatchSize               int                              `json:"tile_batch_size"`
+	UpscalerName                string                           `json:"upscaler_name"`
+	ScaleFactor                 int                              `json:"scale_factor"`
+	NoiseInverse                bool                             `json:"noise_inverse"`
+	NoiseInverseSteps           int                              `json:"noise_inverse_steps"`
+	NoiseInverseRetouch         int                              `json:"noise_inverse_retouch"`
+	NoiseInverseRenoiseStrength int                              `json:"noise_inverse_renoise_strength"`
+	NoiseInverseRenoiseKernel   int                              `json:"noise_inverse_renoise_kernel"`
+	ControlTensorCpu            bool                             `json:"control_tensor_cpu"`
+	EnableBboxControl           bool                             `json:"enable_bbox_control"`
+	DrawBackground              bool                             `json:"draw_background"`
+	CausalLayers                bool                             `json:"causal_layers"`
+	BboxControlStates           []MultiDiffusionBboxControlState `json:"bbox_control_states"`
+}
+
+type MultiDiffusionBboxControlState struct {
+	Enabled      bool    `json:"enabled"`
+	X            float64 `json:"x"`
+	Y            float64 `json:"y"`
+	W            float64 `json:"w"`
+	H            float64 `json:"h"`
+	Prompt       string  `json:"prompt"`
+	NegPrompt    string  `json:"neg_prompt"`
+	BlendMode    string  `json:"blend_mode"`
+	FeatherRatio float64 `json:"feather_ratio"`
+	Seed         int     `json:"seed"`
+}
+
+type TiledVae struct {
+	Enabled         bool `json:"enabled"`
+	EncoderTileSize int  `json:"encoder_tile_size"`
+	DecoderTileSize int  `json:"decoder_tile_size"`
+	VaeToGpu        bool `json:"vae_to_gpu"`
+	FastDecoder     bool `json:"fast_decoder"`
+	FastEncoder     bool `json:"fast_encoder"`
+	ColorFix        bool `json:"color_fix"`
 }
 
 func (m *MultiDiffusion) String() string {
@@ -305,38 +331,51 @@ type CreateImageResponse struct {
 }
 
 type CreateImageData struct {
-	Keys    []string `json:"keys"`
-	Results []struct {
-		Key            string `json:"key"`
-		ExpectedSecond int    `json:"expected_second"`
-		BatchTaskKey   string `json:"batch_task_key"`
-	} `json:"results"`
-	ExpectedIntegralCost int `json:"expected_integral_cost"`
+	Keys                 []string            `json:"keys"`
+	Results              []CreateImageResult `json:"results"`
+	ExpectedIntegralCost int                 `json:"expected_integral_cost"`
+}
+
+type CreateImageResult struct {
+	Key            string `json:"key"`
+	ExpectedSecond int    `json:"expected_second"`
+	BatchTaskKey   string `json:"batch_task_key,omitempty"`
 }
 
 type CreateImageCallBackSuccessResp struct {
-	ArtworkUrl string `json:"artwork_url"`
-	AuditInfo  struct {
-		CheckFail       bool   `json:"check_fail"`
-		Hit             bool   `json:"hit"`
-		DataId          string `json:"data_id"`
-		TotalSuggestion string `json:"total_suggestion"`
-		ScanSceneDTOS   []struct {
-			Rate       float64 `json:"rate"`
-			Suggestion string  `json:"suggestion"`
-			Label      string  `json:"label"`
-			LabelDesc  string  `json:"label_desc"`
-			Scene      string  `json:"scene"`
-		} `json:"scan_scene_d_t_o_s"`
-		Url string `json:"url"`
-	} `json:"audit_info"`
-	CompleteTime        int    `json:"complete_time"`
-	IntegralCost        int    `json:"integral_cost"`
-	IntegralCostMessage string `json:"integral_cost_message"`
-	InvolveYellow       int    `json:"involve_yellow"`
+	ArtworkUrl          string    `json:"artwork_url"`
+	AuditInfo           AuditInfo `json:"audit_info"`
+	CompleteTime        int       `json:"complete_time"`
+	IntegralCost        int       `json:"integral_cost"`
+	IntegralCostMessage string    `json:"integral_cost_message"`
+	InvolveYellow       int       `json:"involve_yellow"`
 	CreateImageBaseCallBackResp
 }
 
+type AuditInfo struct {
+	CheckFail       bool           `json:"check_fail"`
+	Hit             bool           `json:"hit"`
+	DataId          string         `json:"data_id"`
+	TotalSuggestion string         `json:"total_suggestion"`
+	ScanSceneDTOS   []ScanSceneDTO `json:"scan_scene_d_t_o_s"`
+	Url             string         `json:"url"`
+}
+
+type ScanSceneDTO struct {
+	Scene      string     `json:"scene"`
+	Label      string     `json:"label"`
+	LabelDesc  string     `json:"label_desc"`
+	Suggestion string     `json:"suggestion"`
+	Rate       float64    `json:"rate"`
+	SubLabels  []SubLabel `json:"sub_labels,omitempty"`
+}
+
+type SubLabel struct {
+	SubLabel     string  `json:"sub_label"`
+	SubLabelDesc string  `json:"sub_label_desc"`
+	Rate         float64 `json:"rate"`
+}
+
 type CreateImageCallBackFailedResp struct {
 	FailMessage         string `json:"fail_message"`
 	IntegralCost        int    `json:"integral_cost"`
@@ -361,24 +400,21 @@ type GeneratingInfoData struct {
 }
 
 type ImageGeneratingInfo struct {
-	Key             string  `json:"key"`
-	Status          int     `json:"status"`
-	PictureURL      string  `json:"picture_url"`
-	ExpectedSeconds int     `json:"expected_seconds"`
-	StartGenTime    int     `json:"start_gen_time"`
-	CompleteTime    int     `json:"complete_time"`
-	CompletePercent float64 `json:"complete_percent"`
-	QueueBeforeNum  int     `json:"queue_before_num"`
-	ReduceTime      int     `json:"reduce_time"`
-	InvolveYellow   int     `json:"involve_yellow"`
-	AuditInfo       string  `json:"audit_info"`
-	FailMessage     struct {
-		FailCode    int    `json:"fail_code"`
-		FailMessage string `json:"fail_message"`
-	} `json:"fail_message"`
-	ModelPrompt         string `json:"model_prompt"`
-	IntegralCost        int    `json:"integral_cost"`
-	IntegralCostMessage string `json:"integral_cost_message"`
+	Key                 string      `json:"key"`
+	Status              int         `json:"status"`
+	PictureURL          string      `json:"picture_url"`
+	ExpectedSeconds     int         `json:"expected_seconds"`
+	StartGenTime        int         `json:"start_gen_time"`
+	CompleteTime        int         `json:"complete_time"`
+	CompletePercent     float64     `json:"complete_percent"`
+	QueueBeforeNum      int         `json:"queue_before_num"`
+	ReduceTime          int         `json:"reduce_time"`
+	InvolveYellow       int         `json:"involve_yellow"`
+	AuditInfo           string      `json:"audit_info"`
+	FailMessage         FailMessage `json:"fail_message"`
+	ModelPrompt         string      `json:"model_prompt"`
+	IntegralCost        int         `json:"integral_cost"`
+	IntegralCostMessage string      `json:"integral_cost_message"`
 }
 
 type ImageInfoResponse struct {
@@ -387,96 +423,50 @@ type ImageInfoResponse struct {
 }
 
 type ImageInfoData struct {
-	Prompt              string   `json:"prompt"`
-	UcPrompt            string   `json:"uc_prompt"`
-	Model               int      `json:"model"`
-	Width               int      `json:"width"`
-	Height              int      `json:"height"`
-	Status              int      `json:"status"`
-	PictureUrl          string   `json:"picture_url"`
-	MiniPictureURL      string   `json:"mini_picture_url"`
-	InitImageURL        string   `json:"init_image_url"`
-	InitImageSimilarity int      `json:"init_image_similarity"`
-	CreativityDegree    int      `json:"creativity_degree"`
-	Artist              string   `json:"artist"`
-	Style               string   `json:"style"`
-	ImageType           string   `json:"image_type"`
-	ElementMagic        []string `json:"element_magic"`
-	GenerateTime        int      `json:"generate_time"`
-	StartGenTime        int      `json:"start_gen_time"`
-	CompleteTime        int      `json:"complete_time"`
-	InvolveYellow       int      `json:"involve_yellow"`
-	AuditInfo           string   `json:"audit_info"`
+	Prompt              string    `json:"prompt"`
+	UcPrompt            string    `json:"uc_prompt"`
+	Model               ModelCode `json:"model"`
+	Width               int       `json:"width"`
+	Height              int       `json:"height"`
+	Status              int       `json:"status"`
+	PictureUrl          string    `json:"picture_url"`
+	MiniPictureURL      string    `json:"mini_picture_url"`
+	InitImageURL        string    `json:"init_image_url"`
+	InitImageSimilarity int       `json:"init_image_similarity"`
+	CreativityDegree    int       `json:"creativity_degree"`
+	Artist              string    `json:"artist"`
+	Style               string    `json:"style"`
+	ImageType           string    `json:"image_type"`
+	ElementMagic        []string  `json:"element_magic"`
+	GenerateTime        int       `json:"generate_time"`
+	StartGenTime        int       `json:"start_gen_time"`
+	CompleteTime        int       `json:"complete_time"`
+	InvolveYellow       int       `json:"involve_yellow"`
+	AuditInfo           string    `json:"audit_info"`
 	TechnologyInfo      struct {
 		MachineNo        string  `json:"machine_no"`
 		GpuType          string  `json:"gpu_type"`
 		PowerConsumption float64 `json:"power_consumption"`
 	} `json:"technology_info"`
-	FailMessage struct {
-		FailCode    int    `json:"fail_code"`
-		FailMessage string `json:"fail_message"`
-	} `json:"fail_message"`
-	ModelPrompt      string   `json:"model_prompt"`
-	CharacterOptions []string `json:"character_options"`
-	ModelFusion      []struct {
-		Name   string  `json:"name"`
-		Weight float64 `json:"weight"`
-	} `json:"model_fusion"`
-	StyleModel          string  `json:"style_model"`
-	PretreatmentImage   string  `json:"pretreatment_image"`
-	PretreatmentMethod  string  `json:"pretreatment_method"`
-	Steps               int     `json:"steps"`
-	Cfg                 float64 `json:"cfg"`
-	SamplerIndex        int     `json:"sampler_index"`
-	Seed                string  `json:"seed"`
-	IntegralCost        int     `json:"integral_cost"`
-	IntegralCostMessage string  `json:"integral_cost_message"`
-	MultiDiffusion      struct {
-		TiledDiffusion struct {
-			Enabled                     bool   `json:"enabled"`
-			Method                      string `json:"method"`
-			OverwriteSize               bool   `json:"overwrite_size"`
-			KeepInputSize               bool   `json:"keep_input_size"`
-			ImageWidth                  int    `json:"image_width"`
-			ImageHeight                 int    `json:"image_height"`
-			TileWidth                   int    `json:"tile_width"`
-			TileHeight                  int    `json:"tile_height"`
-			Overlap                     int    `json:"overlap"`
-			TileBatchSize               int    `json:"tile_batch_size"`
-			UpscalerName                string `json:"upscaler_name"`
-			ScaleFactor                 int    `json:"scale_factor"`
-			NoiseInverse                bool   `json:"noise_inverse"`
-			NoiseInverseSteps           int    `json:"noise_inverse_steps"`
-			NoiseInverseRetouch         int    `json:"noise_inverse_retouch"`
-			NoiseInverseRenoiseStrength int    `json:"noise_inverse_renoise_strength"`
-			NoiseInverseRenoiseKernel   int    `json:"noise_inverse_renoise_kernel"`
-			ControlTensorCpu            bool   `json:"control_tensor_cpu"`
-			EnableBboxControl           bool   `json:"enable_bbox_control"`
-			DrawBackground              bool   `json:"draw_background"`
-			CausalLayers                bool   `json:"causal_layers"`
-			BboxControlStates           []struct {
-				Enabled      bool    `json:"enabled"`
-				X            float64 `json:"x"`
-				Y            float64 `json:"y"`
-				W            float64 `json:"w"`
-				H            float64 `json:"h"`
-				Prompt       string  `json:"prompt"`
-				NegPrompt    string  `json:"neg_prompt"`
-				BlendMode    string  `json:"blend_mode"`
-				FeatherRatio float64 `json:"feather_ratio"`
-				Seed         int     `json:"seed"`
-			} `json:"bbox_control_states"`
-		} `json:"tiled_diffusion"`
-		TiledVae struct {
-			Enabled         bool `json:"enabled"`
-			EncoderTileSize int  `json:"encoder_tile_size"`
-			DecoderTileSize int  `json:"decoder_tile_size"`
-			VaeToGpu        bool `json:"vae_to_gpu"`
-			FastDecoder     bool `json:"fast_decoder"`
-			FastEncoder     bool `json:"fast_encoder"`
-			ColorFix        bool `json:"color_fix"`
-		} `json:"tiled_vae"`
-	} `json:"multi_diffusion"`
+	FailMessage         FailMessage       `json:"fail_message"`
+	ModelPrompt         string            `json:"model_prompt"`
+	CharacterOptions    []string          `json:"character_options"`
+	ModelFusion         []ModelFusionInfo `json:"model_fusion"`
+	StyleModel          string            `json:"style_model"`
+	PretreatmentImage   string            `json:"pretreatment_image"`
+	PretreatmentMethod  string            `json:"pretreatment_method"`
+	Steps               int               `json:"steps"`
+	Cfg                 float64           `json:"cfg"`
+	SamplerIndex        int               `json:"sampler_index"`
+	Seed                string            `json:"seed"`
+	IntegralCost        int               `json:"integral_cost"`
+	IntegralCostMessage string            `json:"integral_cost_message"`
+	MultiDiffusion      MultiDiffusion    `json:"multi_diffusion"`
+}
+
+type ModelFusionInfo struct {
+	Name   string  `json:"name"`
+	Weight float64 `json:"weight"`
 }
 
 type ImagePriceInfoRequest struct {
@@ -502,15 +492,27 @@ type ImagePriceInfoData struct {
 		AccelerateTimesUse int `json:"accelerate_times_use"`
 		SuperSizeTimesUse  int `json:"super_size_times_use"`
 	} `json:"vip_rights_use"`
-	IntegralUse struct {
-		IntegralUseOnCreate     int `json:"integral_use_on_create"`
-		IntegralUseOnResolution int `json:"integral_use_on_resolution"`
-		IntegralUseOnStyleModel int `json:"integral_use_on_style_model"`
-		IntegralUseOnSteps      int `json:"integral_use_on_steps"`
-		IntegralUseOnAccelerate int `json:"integral_use_on_accelerate"`
-		IntegralUseOnSuperSize  int `json:"integral_use_on_super_size"`
-		DiscountIntegral        int `json:"discount_integral"`
-	} `json:"integral_use"`
+	IntegralUse IntegralUse `json:"integral_use"`
+}
+
+type IntegralUse struct {
+	IntegralUseOnCreate     int `json:"integral_use_on_create"`
+	IntegralUseOnResolution int `json:"integral_use_on_resolution"`
+	IntegralUseOnStyleModel int `json:"integral_use_on_style_model"`
+	IntegralUseOnSteps      int `json:"integral_use_on_steps"`
+	IntegralUseOnAccelerate int `json:"integral_use_on_accelerate"`
+	IntegralUseOnSuperSize  int `json:"integral_use_on_super_size"`
+	DiscountIntegral        int `json:"discount_integral"`
+}
+
+// Total get points to be spent, which is the sum of all uses minus discount
+func (i *IntegralUse) Total() int {
+	total := i.IntegralUseOnCreate + i.IntegralUseOnResolution + i.IntegralUseOnStyleModel +
+		i.IntegralUseOnSteps + i.IntegralUseOnAccelerate + i.IntegralUseOnSuperSize - i.DiscountIntegral
+	if total < 0 {
+		return 0
+	}
+	return total
 }
 
 type PostSuperSizeRequest struct {
@@ -574,52 +576,52 @@ type CreateParamsResponse struct {
 }
 
 type CreateParams struct {
-	Key                      string  `json:"key"`
-	ArtworkURL               string  `json:"artwork_url"`
-	Model                    int     `json:"model"`
-	ModelAsString            string  `json:"model_as_string"`
-	ModelCode                int     `json:"model_code"`
-	ModelCodeAsString        string  `json:"model_code_as_string"`
-	Pattern                  string  `json:"pattern"`
-	Prompt                   string  `json:"prompt"`
-	UcPrompt                 string  `json:"uc_prompt"`
-	CreativityDegree         int     `json:"creativity_degree"`
-	CreativityDegreeAsString string  `json:"creativity_degree_as_string"`
-	InitImageURL             string  `json:"init_image_url"`
-	InitWidth                int     `json:"init_width"`
-	InitWidthAsString        string  `json:"init_width_as_string"`
-	InitHeight               int     `json:"init_height"`
-	InitHeightAsString       string  `json:"init_height_as_string"`
-	PretreatmentMethod       string  `json:"pretreatment_method"`
-	MaskImageURL             string  `json:"mask_image_url"`
-	MaskZoneImageURL         string  `json:"mask_zone_image_url"`
-	Size                     string  `json:"size"`
-	Nature                   int     `json:"nature"`
-	NatureAsString           string  `json:"nature_as_string"`
-	PromptOptimize           int     `json:"prompt_optimize"`
-	PromptOptimizeAsString   string  `json:"prompt_optimize_as_string"`
-	StyleDecoration          string  `json:"style_decoration"`
-	Character                string  `json:"character"`
-	ModelFusion              string  `json:"model_fusion"`
-	StyleModel               string  `json:"style_model"`
-	ResolutionInfo           string  `json:"resolution_info"`
-	Steps                    int     `json:"steps"`
-	StepsAsString            string  `json:"steps_as_string"`
-	Cfg                      float64 `json:"cfg"`
-	CfgAsString              string  `json:"cfg_as_string"`
-	SamplerIndex             string  `json:"sampler_index"`
-	Seed                     string  `json:"seed"`
-	SuperType                int     `json:"super_type"`
-	SuperTypeAsString        string  `json:"super_type_as_string"`
-	ChatGptOptimize          bool    `json:"chat_gpt_optimize"`
-	ChatGptOptimizeAsString  string  `json:"chat_gpt_optimize_as_string"`
-	ClipSkip                 int     `json:"clip_skip"`
-	ClipSkipAsString         string  `json:"clip_skip_as_string"`
-	Ensd                     float64 `json:"ensd"`
-	EnsdAsString             string  `json:"ensd_as_string"`
-	RepairTheHand            bool    `json:"repair_the_hand"`
-	RepairTheHandAsString    string  `json:"repair_the_hand_as_string"`
-	ConsumedTime             string  `json:"consumed_time"`
+	Key                      string    `json:"key"`
+	ArtworkURL               string    `json:"artwork_url"`
+	Model                    ModelCode `json:"model"`
+	ModelAsString            string    `json:"model_as_string"`
+	ModelCode                ModelCode `json:"model_code"`
+	ModelCodeAsString        string    `json:"model_code_as_string"`
+	Pattern                  string    `json:"pattern"`
+	Prompt                   string    `json:"prompt"`
+	UcPrompt                 string    `json:"uc_prompt"`
+	CreativityDegree         int       `json:"creativity_degree"`
+	CreativityDegreeAsString string    `json:"creativity_degree_as_string"`
+	InitImageURL             string    `json:"init_image_url"`
+	InitWidth                int       `json:"init_width"`
+	InitWidthAsString        string    `json:"init_width_as_string"`
+	InitHeight               int       `json:"init_height"`
+	InitHeightAsString       string    `json:"init_height_as_string"`
+	PretreatmentMethod       string    `json:"pretreatment_method"`
+	MaskImageURL             string    `json:"mask_image_url"`
+	MaskZoneImageURL         string    `json:"mask_zone_image_url"`
+	Size                     string    `json:"size"`
+	Nature                   int       `json:"nature"`
+	NatureAsString           string    `json:"nature_as_string"`
+	PromptOptimize           int       `json:"prompt_optimize"`
+	PromptOptimizeAsString   string    `json:"prompt_optimize_as_string"`
+	StyleDecoration          string    `json:"style_decoration"`
+	Character                string    `json:"character"`
+	ModelFusion              string    `json:"model_fusion"`
+	StyleModel               string    `json:"style_model"`
+	ResolutionInfo           string    `json:"resolution_info"`
+	Steps                    int       `json:"steps"`
+	StepsAsString            string    `json:"steps_as_string"`
+	Cfg                      float64   `json:"cfg"`
+	CfgAsString              string    `json:"cfg_as_string"`
+	SamplerIndex             string    `json:"sampler_index"`
+	Seed                     string    `json:"seed"`
+	SuperType                int       `json:"super_type"`
+	SuperTypeAsString        string    `json:"super_type_as_string"`
+	ChatGptOptimize          bool      `json:"chat_gpt_optimize"`
+	ChatGptOptimizeAsString  string    `json:"chat_gpt_optimize_as_string"`
+	ClipSkip                 int       `json:"clip_skip"`
+	ClipSkipAsString         string    `json:"clip_skip_as_string"`
+	Ensd                     float64   `json:"ensd"`
+	EnsdAsString             string    `json:"ensd_as_string"`
+	RepairTheHand            bool      `json:"repair_the_hand"`
+	RepairTheHandAsString    string    `json:"repair_the_hand_as_string"`
+	ConsumedTime             string    `json:"consumed_time"`
 }
 
 type ImageModelQueueInfoResponse struct {
@@ -703,12 +705,9 @@ type YouthifyResponse struct {
 }
 
 type YouthifyData struct {
-	Keys    string `json:"keys"`
-	Results []struct {
-		Key            string `json:"key"`
-		ExpectedSecond int    `json:"expected_second"`
-	} `json:"results"`
-	ExpectedIntegralCost int `json:"expected_integral_cost"`
+	Keys                 string              `json:"keys"`
+	Results              []CreateImageResult `json:"results"`
+	ExpectedIntegralCost int                 `json:"expected_integral_cost"`
 }
 
 type QuerySpellResponse struct {
@@ -725,131 +724,249 @@ type QuerySpellData struct {
 }
 
 type CreateImageProRequest struct {
-	ModelCode         int     `json:"model_code"`
-	Prompt            string  `json:"prompt"`
-	Width             int     `json:"width"`
-	Height            int     `json:"height"`
-	SupersizeMultiple float64 `json:"supersize_multiple"`
-	PrefineMultiple   float64 `json:"prefine_multiple"`
-	BatchCount        int     `json:"batch_count"`
-	OptionParam       struct {
-		ModelFusion []struct {
-			Key    string  `json:"key"`
-			Weight float64 `json:"weight"`
-		} `json:"model_fusion"`
-		Character []string `json:"character"`
-	} `json:"option_param"`
-	AdvancedParam struct {
-		UcPrompt     string  `json:"uc_prompt"`
-		RestoreFaces bool    `json:"restore_faces"`
-		Tilling      bool    `json:"tilling"`
-		Seed         string  `json:"seed"`
-		VaeFile      string  `json:"vae_file"`
-		Cfg          int     `json:"cfg"`
-		SamplerSteps int     `json:"sampler_steps"`
-		SamplerIndex int     `json:"sampler_index"`
-		ClipSkip     int     `json:"clip_skip"`
-		Ensd         float64 `json:"ensd"`
-	} `json:"advanced_param"`
-	ImgToImgParam struct {
-		InitImageUrl     string `json:"init_image_url"`
-		CreativityDegree int    `json:"creativity_degree"`
-		ResizeMode       int    `json:"resize_mode"`
-	} `json:"img_to_img_param"`
-	ControlNetParams []struct {
-		Type                int    `json:"type"`
-		Preprocessor        int    `json:"preprocessor"`
-		Model               int    `json:"model"`
-		ControlWeight       int    `json:"control_weight"`
-		StartingControlStep int    `json:"starting_control_step"`
-		EndingControlStep   int    `json:"ending_control_step"`
-		ControlMode         int    `json:"control_mode"`
-		ImageUrl            string `json:"image_url"`
-		ImageWidth          int    `json:"image_width"`
-		ImageHeight         int    `json:"image_height"`
-		Mask                string `json:"mask"`
-		MaskUrl             string `json:"mask_url"`
-		ProcessorRes        int    `json:"processor_res"`
-		ThresholdA          int    `json:"threshold_a"`
-		ThresholdB          int    `json:"threshold_b"`
-		ResizeMode          int    `json:"resize_mode"`
-		PixelPerfect        bool   `json:"pixel_perfect"`
-	} `json:"control_net_params"`
-	InpaintingPluginDTO struct {
-		MaskZoneImageUrl      string `json:"mask_zone_image_url"`
-		MaskBlur              int    `json:"mask_blur"`
-		InpaintingFill        int    `json:"inpainting_fill"`
-		InpaintingMaskInvert  bool   `json:"inpainting_mask_invert"`
-		InpaintFullResPadding int    `json:"inpaint_full_res_padding"`
-		InpaintFullRes        bool   `json:"inpaint_full_res"`
-	} `json:"inpainting_plugin_d_t_o"`
-	TiledDiffusionDTO struct {
-		Enabled           bool `json:"enabled"`
-		DrawBackground    bool `json:"draw_background"`
-		BboxControlStates []struct {
-			Enabled             bool    `json:"enabled"`
-			X                   float64 `json:"x"`
-			Y                   float64 `json:"y"`
-			W                   float64 `json:"w"`
-			H                   float64 `json:"h"`
-			Prompt              string  `json:"prompt"`
-			NegPrompt           string  `json:"neg_prompt"`
-			ModelInputPrompt    string  `json:"model_input_prompt"`
-			ModelInputNegPrompt string  `json:"model_input_neg_prompt"`
-			BlendMode           string  `json:"blend_mode"`
-			Seed                int     `json:"seed"`
-			OptionParam         struct {
-				ModelFusion []struct {
-					Key    string  `json:"key"`
-					Weight float64 `json:"weight"`
-				} `json:"model_fusion"`
-				Character []string `json:"character"`
-			} `json:"option_param"`
-		} `json:"bbox_control_states"`
-	} `json:"tiled_diffusion_d_t_o"`
-	FaceEditorDTO struct {
-		Enabled                 bool     `json:"enabled"`
-		UseMinimalArea          bool     `json:"use_minimal_area"`
-		AffectedAreas           []string `json:"affected_areas"`
-		MaskSize                int      `json:"mask_size"`
-		MaskBlur                int      `json:"mask_blur"`
-		MaxFaceCount            int      `json:"max_face_count"`
-		Confidence              float64  `json:"confidence"`
-		FaceMargin              float64  `json:"face_margin"`
-		FaceSize                int      `json:"face_size"`
-		IgnoreLargerFaces       bool     `json:"ignore_larger_faces"`
-		Strength1               float64  `json:"strength1"`
-		ApplyInsideMaskOnly     bool     `json:"apply_inside_mask_only"`
-		Strength2               float64  `json:"strength2"`
-		PromptForFace           string   `json:"prompt_for_face"`
-		ModelInputPromptForFace string   `json:"model_input_prompt_for_face"`
-	} `json:"face_editor_d_t_o"`
-	UltimateUpscaleDTO struct {
-		Enabled         bool    `json:"enabled"`
-		TargetSizeType  int     `json:"target_size_type"`
-		UpscalerIndex   int     `json:"upscaler_index"`
-		RedrawMode      int     `json:"redraw_mode"`
-		TileWidth       int     `json:"tile_width"`
-		TileHeight      int     `json:"tile_height"`
-		MaskBlur        int     `json:"mask_blur"`
-		SeamsFixType    int     `json:"seams_fix_type"`
-		SeamsFixWidth   int     `json:"seams_fix_width"`
-		SeamsFixDenoise float64 `json:"seams_fix_denoise"`
-		SeamsFixPadding int     `json:"seams_fix_padding"`
-	} `json:"ultimate_upscale_d_t_o"`
-	AdetailerDTOS []struct {
-		AdModel                  string `json:"ad_model"`
-		AdNegativePrompt         string `json:"ad_negative_prompt"`
-		AdPrompt                 string `json:"ad_prompt"`
-		ModelInputNegativePrompt string `json:"model_input_negative_prompt"`
-		ModelInputPrompt         string `json:"model_input_prompt"`
-	} `json:"adetailer_d_t_o_s"`
+	ModelCode           ModelCode           `json:"model_code"`
+	Prompt              string              `json:"prompt"`
+	Width               int                 `json:"width"`
+	Height              int                 `json:"height"`
+	SupersizeMultiple   float64             `json:"supersize_multiple"`
+	PrefineMultiple     float64             `json:"prefine_multiple"`
+	BatchCount          int                 `json:"batch_count"`
+	OptionParam         OptionParam         `json:"option_param"`
+	AdvancedParam       AdvancedParam       `json:"advanced_param"`
+	ImgToImgParam       ImgToImgParam       `json:"img_to_img_param"`
+	ControlNetParams    []ControlNetParam   `json:"control_net_params"`
+	InpaintingPluginDTO InpaintingPluginDTO `json:"inpainting_plugin_d_t_o"`
+	TiledDiffusionDTO   TiledDiffusionDTO   `json:"tiled_diffusion_d_t_o"`
+	FaceEditorDTO       FaceEditorDTO       `json:"face_editor_d_t_o"`
+	UltimateUpscaleDTO  UltimateUpscaleDTO  `json:"ultimate_upscale_d_t_o"`
+	AdetailerDTOS       []ADetailer         `json:"adetailer_d_t_o_s"`
+}
+
+type OptionParam struct {
+	ModelFusion []ModelFusion `json:"model_fusion"`
+	Character   []string      `json:"character"`
+}
+
+type AdvancedParam struct {
+	UcPrompt     string  `json:"uc_prompt"`
+	RestoreFaces bool    `json:"restore_faces"`
+	Tilling      bool    `json:"tilling"`
+	Seed         string  `json:"seed"`
+	VaeFile      string  `json:"vae_file"`
+	Cfg          int     `json:"cfg"`
+	SamplerSteps int     `json:"sampler_steps"`
+	SamplerIndex int     `json:"sampler_index"`
+	ClipSkip     int     `json:"clip_skip"`
+	Ensd         float64 `json:"ensd"`
+}
+
+type ImgToImgParam struct {
+	InitImageUrl     string `json:"init_image_url"`
+	CreativityDegree int    `json:"creativity_degree"`
+	ResizeMode       int    `json:"resize_mode"`
+}
+
+type ControlNetParam struct {
+	Type                int       `json:"type"`
+	Preprocessor        int       `json:"preprocessor"`
+	Model               ModelCode `json:"model"`
+	ControlWeight       int       `json:"control_weight"`
+	StartingControlStep int       `json:"starting_control_step"`
+	EndingControlStep   int       `json:"ending_control_step"`
+	ControlMode         int       `json:"control_mode"`
+	ImageUrl            string    `json:"image_url"`
+	ImageWidth          int       `json:"image_width"`
+	ImageHeight         int       `json:"image_height"`
+	Mask                string    `json:"mask"`
+	MaskUrl             string    `json:"mask_url"`
+	ProcessorRes        int       `json:"processor_res"`
+	ThresholdA          int       `json:"threshold_a"`
+	ThresholdB          int       `json:"threshold_b"`
+	ResizeMode          int       `json:"resize_mode"`
+	PixelPerfect        bool      `json:"pixel_perfect"`
+}
+
+func (c *ControlNetParam) String() string {
+	return fmt.Sprintf("%+v", *c)
+}
+
+type InpaintingPluginDTO struct {
+	MaskZoneImageUrl      string `json:"mask_zone_image_url"`
+	MaskBlur              int    `json:"mask_blur"`
+	InpaintingFill        int    `json:"inpainting_fill"`
+	InpaintingMaskInvert  bool   `json:"inpainting_mask_invert"`
+	InpaintFullResPadding int    `json:"inpaint_full_res_padding"`
+	InpaintFullRes        bool   `json:"inpaint_full_res"`
+}
+
+type TiledDiffusionDTO struct {
+	Enabled           bool               `json:"enabled"`
+	DrawBackground    bool               `json:"draw_background"`
+	BboxControlStates []BboxControlState `json:"bbox_control_states"`
+}
+
+type BboxControlState struct {
+	Enabled             bool        `json:"enabled"`
+	X                   float64     `json:"x"`
+	Y                   float64     `json:"y"`
+	W                   float64     `json:"w"`
+	H                   float64     `json:"h"`
+	Prompt              string      `json:"prompt"`
+	NegPrompt           string      `json:"neg_prompt"`
+	ModelInputPrompt    string      `json:"model_input_prompt"`
+	ModelInputNegPrompt string      `json:"model_input_neg_prompt"`
+	BlendMode           string      `json:"blend_mode"`
+	Seed                int         `json:"seed"`
+	OptionParam         OptionParam `json:"option_param"`
+}
+
+type FaceEditorDTO struct {
+	Enabled                 bool     `json:"enabled"`
+	UseMinimalArea          bool     `json:"use_minimal_area"`
+	AffectedAreas           []string `json:"affected_areas"`
+	MaskSize                int      `json:"mask_size"`
+	MaskBlur                int      `json:"mask_blur"`
+	MaxFaceCount            int      `json:"max_face_count"`
+	Confidence              float64  `json:"confidence"`
+	FaceMargin              float64  `json:"face_margin"`
+	FaceSize                int      `json:"face_size"`
+	IgnoreLargerFaces       bool     `json:"ignore_larger_faces"`
+	Strength1               float64  `json:"strength1"`
+	ApplyInsideMaskOnly     bool     `json:"apply_inside_mask_only"`
+	Strength2               float64  `json:"strength2"`
+	PromptForFace           string   `json:"prompt_for_face"`
+	ModelInputPromptForFace string   `json:"model_input_prompt_for_face"`
+}
+
+type UltimateUpscaleDTO struct {
+	Enabled         bool    `json:"enabled"`
+	TargetSizeType  int     `json:"target_size_type"`
+	UpscalerIndex   int     `json:"upscaler_index"`
+	RedrawMode      int     `json:"redraw_mode"`
+	TileWidth       int     `json:"tile_width"`
+	TileHeight      int     `json:"tile_height"`
+	MaskBlur        int     `json:"mask_blur"`
+	SeamsFixType    int     `json:"seams_fix_type"`
+	SeamsFixWidth   int     `json:"seams_fix_width"`
+	SeamsFixDenoise float64 `json:"seams_fix_denoise"`
+	SeamsFixPadding int     `json:"seams_fix_padding"`
+}
+
+type ADetailer struct {
+	AdModel                  string `json:"ad_model"`
+	AdNegativePrompt         string `json:"ad_negative_prompt"`
+	AdPrompt                 string `json:"ad_prompt"`
+	ModelInputNegativePrompt string `json:"model_input_negative_prompt"`
+	ModelInputPrompt         string `json:"model_input_prompt"`
 }
 
 func (c *CreateImageProRequest) String() string {
 	return fmt.Sprintf("%+v", *c)
 }
 
+type CreateImageProOption func(c *CreateImageProRequest)
+
+func NewCreateImageProRequest(modelCode ModelCode, prompt string, width, height int, options ...CreateImageProOption) *CreateImageProRequest {
+	c := &CreateImageProRequest{ModelCode: modelCode, Prompt: prompt, Width: width, Height: height, BatchCount: 1}
+	for _, option := range options {
+		option(c)
+	}
+	return c
+}
+
+func WithProBatchCount(batchCount int) CreateImageProOption {
+	return func(c *CreateImageProRequest) {
+		c.BatchCount = batchCount
+	}
+}
+
+func WithProSupersizeMultiple(multiple float64) CreateImageProOption {
+	return func(c *CreateImageProRequest) {
+		c.SupersizeMultiple = multiple
+	}
+}
+
+func WithProPrefineMultiple(multiple float64) CreateImageProOption {
+	return func(c *CreateImageProRequest) {
+		c.PrefineMultiple = multiple
+	}
+}
+
+func WithProModelFusion(key string, weight float64) CreateImageProOption {
+	return func(c *CreateImageProRequest) {
+		c.OptionParam.ModelFusion = append(c.OptionParam.ModelFusion, ModelFusion{Key: key, Weight: weight})
+	}
+}
+
+func WithProCharacter(keys ...string) CreateImageProOption {
+	return func(c *CreateImageProRequest) {
+		c.OptionParam.Character = append(c.OptionParam.Character, keys...)
+	}
+}
+
+func WithProUcPrompt(ucPrompt string) CreateImageProOption {
+	return func(c *CreateImageProRequest) {
+		c.AdvancedParam.UcPrompt = ucPrompt
+	}
+}
+
+func WithProSeed(seed string) CreateImageProOption {
+	return func(c *CreateImageProRequest) {
+		c.AdvancedParam.Seed = seed
+	}
+}
+
+func WithProAdvancedParam(advancedParam AdvancedParam) CreateImageProOption {
+	return func(c *CreateImageProRequest) {
+		c.AdvancedParam = advancedParam
+	}
+}
+
+func WithProImgToImg(initImageUrl string, creativityDegree int) CreateImageProOption {
+	return func(c *CreateImageProRequest) {
+		c.ImgToImgParam.InitImageUrl = initImageUrl
+		c.ImgToImgParam.CreativityDegree = creativityDegree
+	}
+}
+
+func WithProControlNet(params ...ControlNetParam) CreateImageProOption {
+	return func(c *CreateImageProRequest) {
+		c.ControlNetParams = append(c.ControlNetParams, params...)
+	}
+}
+
+func WithProInpainting(inpainting InpaintingPluginDTO) CreateImageProOption {
+	return func(c *CreateImageProRequest) {
+		c.InpaintingPluginDTO = inpainting
+	}
+}
+
+// WithProBboxControlState add bbox control states and enable tiled diffusion
+func WithProBboxControlState(states ...BboxControlState) CreateImageProOption {
+	return func(c *CreateImageProRequest) {
+		c.TiledDiffusionDTO.Enabled = true
+		c.TiledDiffusionDTO.BboxControlStates = append(c.TiledDiffusionDTO.BboxControlStates, states...)
+	}
+}
+
+func WithProFaceEditor(faceEditor FaceEditorDTO) CreateImageProOption {
+	return func(c *CreateImageProRequest) {
+		c.FaceEditorDTO = faceEditor
+	}
+}
+
+func WithProUltimateUpscale(ultimateUpscale UltimateUpscaleDTO) CreateImageProOption {
+	return func(c *CreateImageProRequest) {
+		c.UltimateUpscaleDTO = ultimateUpscale
+	}
+}
+
+func WithProADetailer(adetailers ...ADetailer) CreateImageProOption {
+	return func(c *CreateImageProRequest) {
+		c.AdetailerDTOS = append(c.AdetailerDTOS, adetailers...)
+	}
+}
+
 type CreateImageProResponse struct {
 	BaseResponse
 	Data CreateImageProData `json:"data"`
@@ -874,19 +991,16 @@ type GeneratingInfoProData struct {
 }
 
 type GeneratingInfoPro struct {
-	Key             string  `json:"key"`
-	Status          int     `json:"status"`
-	PictureURL      string  `json:"picture_url"`
-	ExpectedSeconds int     `json:"expected_seconds"`
-	StartGenTime    int     `json:"start_gen_time"`
-	CompleteTime    int     `json:"complete_time"`
-	CompletePercent float64 `json:"complete_percent"`
-	InvolveYellow   int     `json:"involve_yellow"`
-	AuditInfo       string  `json:"audit_info"`
-	FailMessage     struct {
-		FailCode    int    `json:"fail_code"`
-		FailMessage string `json:"fail_message"`
-	} `json:"fail_message"`
+	Key             string      `json:"key"`
+	Status          int         `json:"status"`
+	PictureURL      string      `json:"picture_url"`
+	ExpectedSeconds int         `json:"expected_seconds"`
+	StartGenTime    int         `json:"start_gen_time"`
+	CompleteTime    int         `json:"complete_time"`
+	CompletePercent float64     `json:"complete_percent"`
+	InvolveYellow   int         `json:"involve_yellow"`
+	AuditInfo       string      `json:"audit_info"`
+	FailMessage     FailMessage `json:"fail_message"`
 }
 
 type CreateAvatarArtworkRequest struct {
@@ -910,12 +1024,9 @@ type CreateAvatarArtworkResponse struct {
 }
 
 type CreateAvatarArtworkData struct {
-	Keys    []string `json:"keys"`
-	Results []struct {
-		Key            string `json:"key"`
-		ExpectedSecond int    `json:"expected_second"`
-	} `json:"results"`
-	ExpectedIntegralCost int `json:"expected_integral_cost"`
+	Keys                 []string            `json:"keys"`
+	Results              []CreateImageResult `json:"results"`
+	ExpectedIntegralCost int                 `json:"expected_integral_cost"`
 }
 
 type AvatarDefaultResourceResponse struct {
@@ -989,20 +1100,17 @@ type CreateMagicDiceResponse struct {
 }
 
 type CreateMagicDiceResult struct {
-	PromptChinese string   `json:"prompt_chinese"`
-	PromptEnglish string   `json:"prompt_english"`
-	Model         string   `json:"model"`
-	ModelCode     int      `json:"model_code"`
-	Cfg           int      `json:"cfg"`
-	ImageType     []string `json:"image_type"`
-	Style         []string `json:"style"`
-	Artists       []string `json:"artists"`
-	ElementMagic  []string `json:"element_magic"`
-	Character     []string `json:"character"`
-	ModelFusion   []struct {
-		Key    string  `json:"key"`
-		Weight float64 `json:"weight"`
-	} `json:"model_fusion"`
+	PromptChinese string        `json:"prompt_chinese"`
+	PromptEnglish string        `json:"prompt_english"`
+	Model         string        `json:"model"`
+	ModelCode     ModelCode     `json:"model_code"`
+	Cfg           int           `json:"cfg"`
+	ImageType     []string      `json:"image_type"`
+	Style         []string      `json:"style"`
+	Artists       []string      `json:"artists"`
+	ElementMagic  []string      `json:"element_magic"`
+	Character     []string      `json:"character"`
+	ModelFusion   []ModelFusion `json:"model_fusion"`
 }
 
 type CreateAvatarRequest struct {
@@ -1051,11 +1159,11 @@ type ImageCheckInfo struct {
 }
 
 type CreateVideoRequest struct {
-	OriginVideoUrl string `json:"origin_video_url"`
-	VideoDuration  int    `json:"video_duration"`
-	ModelCode      int    `json:"model_code"`
-	QueueType      int    `json:"queue_type"`
-	NotifyUrl      string `json:"notify_url"`
+	OriginVideoUrl string    `json:"origin_video_url"`
+	VideoDuration  int       `json:"video_duration"`
+	ModelCode      ModelCode `json:"model_code"`
+	QueueType      int       `json:"queue_type"`
+	NotifyUrl      string    `json:"notify_url"`
 }
 
 func (c *CreateVideoRequest) String() string {
@@ -1075,61 +1183,64 @@ type VideoInfoResponse struct {
 }
 
 type VideoInfo struct {
-	Key             string  `json:"key"`
-	ModelCode       int     `json:"model_code"`
-	ModelName       string  `json:"model_name"`
-	OriginVideoUrl  string  `json:"origin_video_url"`
-	AiVideoUrl      string  `json:"ai_video_url"`
-	Status          int     `json:"status"`
-	CreateTime      int     `json:"create_time"`
-	CompleteTime    int     `json:"complete_time"`
-	ExpectedSeconds int     `json:"expected_seconds"`
-	CompletePercent float64 `json:"complete_percent"`
-	AiVideoMetaInfo struct {
-		Format    string `json:"format"`
-		Width     int    `json:"width"`
-		Height    int    `json:"height"`
-		Duration  int    `json:"duration"`
-		Size      int    `json:"size"`
-		CodecType string `json:"codec_type"`
-		FrameRate int    `json:"frame_rate"`
-		Cover     struct {
-			Url    string `json:"url"`
-			Width  int    `json:"width"`
-			Height int    `json:"height"`
-		} `json:"cover"`
-	} `json:"ai_video_meta_info"`
-	Violation     bool `json:"violation"`
-	ViolationInfo struct {
-		CheckFail       bool   `json:"check_fail"`
-		TotalSuggestion string `json:"total_suggestion"`
-		DataId          string `json:"data_id"`
-		TaskId          string `json:"task_id"`
-		Url             string `json:"url"`
-		SourceResult    string `json:"source_result"`
-		VendorApp       string `json:"vendor_app"`
-		ScreenshotNums  int    `json:"_screenshot_nums"`
-		ScreenshotInfos []struct {
-			Url           string `json:"url"`
-			ScanSceneDTOS []struct {
-				Scene      string  `json:"scene"`
-				Label      string  `json:"label"`
-				LabelDesc  string  `json:"label_desc"`
-				Suggestion string  `json:"suggestion"`
-				Rate       float64 `json:"rate"`
-				SubLabels  []struct {
-					SubLabel     string  `json:"sub_label"`
-					SubLabelDesc string  `json:"sub_label_desc"`
-					Rate         float64 `json:"rate"`
-				} `json:"sub_labels"`
-			} `json:"scan_scene_d_t_o_s"`
-		} `json:"screenshot_infos"`
-	} `json:"violation_info"`
-	FailMessage struct {
-		FailCode    int    `json:"fail_code"`
-		FailMessage string `json:"fail_message"`
-	} `json:"fail_message"`
-	QueueType int `json:"queue_type"`
+	Key             string          `json:"key"`
+	ModelCode       ModelCode       `json:"model_code"`
+	ModelName       string          `json:"model_name"`
+	OriginVideoUrl  string          `json:"origin_video_url"`
+	AiVideoUrl      string          `json:"ai_video_url"`
+	Status          int             `json:"status"`
+	CreateTime      int             `json:"create_time"`
+	CompleteTime    int             `json:"complete_time"`
+	ExpectedSeconds int             `json:"expected_seconds"`
+	CompletePercent float64         `json:"complete_percent"`
+	AiVideoMetaInfo AiVideoMetaInfo `json:"ai_video_meta_info"`
+	Violation       bool            `json:"violation"`
+	ViolationInfo   ViolationInfo   `json:"violation_info"`
+	FailMessage     FailMessage     `json:"fail_message"`
+	QueueType       int             `json:"queue_type"`
+}
+
+type AiVideoMetaInfo struct {
+	Format    string     `json:"format"`
+	Width     int        `json:"width"`
+	Height    int        `json:"height"`
+	Duration  int        `json:"duration"`
+	Size      int        `json:"size"`
+	CodecType string     `json:"codec_type"`
+	FrameRate int        `json:"frame_rate"`
+	Cover     VideoCover `json:"cover"`
+}
+
+type VideoCover struct {
+	Url    string `json:"url"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+}
+
+type ViolationInfo struct {
+	CheckFail       bool             `json:"check_fail"`
+	TotalSuggestion string           `json:"total_suggestion"`
+	DataId          string           `json:"data_id"`
+	TaskId          string           `json:"task_id"`
+	Url             string           `json:"url"`
+	SourceResult    string           `json:"source_result"`
+	VendorApp       string           `json:"vendor_app"`
+	ScreenshotNums  int              `json:"_screenshot_nums"`
+	ScreenshotInfos []ScreenshotInfo `json:"screenshot_infos"`
+}
+
+// ScanScenes get scan scenes of all screenshots
+func (v *ViolationInfo) ScanScenes() []ScanSceneDTO {
+	var scenes []ScanSceneDTO
+	for _, info := range v.ScreenshotInfos {
+		scenes = append(scenes, info.ScanSceneDTOS...)
+	}
+	return scenes
+}
+
+type ScreenshotInfo struct {
+	Url           string         `json:"url"`
+	ScanSceneDTOS []ScanSceneDTO `json:"scan_scene_d_t_o_s"`
 }
 
 type VideoOptionMenuAndPriceTableResponse struct {
@@ -1138,14 +1249,18 @@ type VideoOptionMenuAndPriceTableResponse struct {
 }
 
 type VideoOptionMenuAndPriceTable struct {
-	AiVideoModelOptionVos []struct {
-		ModelCode int    `json:"model_code"`
-		Name      string `json:"name"`
-	} `json:"ai_video_model_option_vos"`
-	PayInfoVo struct {
-		Price      int `json:"price"`
-		NightPrice int `json:"night_price"`
-	} `json:"pay_info_vo"`
+	AiVideoModelOptionVos []VideoModelOption `json:"ai_video_model_option_vos"`
+	PayInfoVo             VideoPayInfo       `json:"pay_info_vo"`
+}
+
+type VideoModelOption struct {
+	ModelCode ModelCode `json:"model_code"`
+	Name      string    `json:"name"`
+}
+
+type VideoPayInfo struct {
+	Price      int `json:"price"`
+	NightPrice int `json:"night_price"`
 }
 
 type VideoModelQueueInfoResponse struct {
@@ -1169,63 +1284,8 @@ type VideoGeneratingInfo struct {
 	NextPollingTime int                         `json:"next_polling_time"`
 }
 
-type VideoGeneratingInfoDetail struct {
-	Key             string  `json:"key"`
-	ModelCode       int     `json:"model_code"`
-	ModelName       string  `json:"model_name"`
-	OriginVideoUrl  string  `json:"origin_video_url"`
-	AiVideoUrl      string  `json:"ai_video_url"`
-	Status          int     `json:"status"`
-	CreateTime      int     `json:"create_time"`
-	CompleteTime    int     `json:"complete_time"`
-	ExpectedSeconds int     `json:"expected_seconds"`
-	CompletePercent float64 `json:"complete_percent"`
-	AiVideoMetaInfo struct {
-		Format    string `json:"format"`
-		Width     int    `json:"width"`
-		Height    int    `json:"height"`
-		Duration  int    `json:"duration"`
-		Size      int    `json:"size"`
-		CodecType string `json:"codec_type"`
-		FrameRate int    `json:"frame_rate"`
-		Cover     struct {
-			Url    string `json:"url"`
-			Width  int    `json:"width"`
-			Height int    `json:"height"`
-		} `json:"cover"`
-	} `json:"ai_video_meta_info"`
-	Violation     bool `json:"violation"`
-	ViolationInfo struct {
-		CheckFail       bool   `json:"check_fail"`
-		TotalSuggestion string `json:"total_suggestion"`
-		DataId          string `json:"data_id"`
-		TaskId          string `json:"task_id"`
-		Url             string `json:"url"`
-		SourceResult    string `json:"source_result"`
-		VendorApp       string `json:"vendor_app"`
-		ScreenshotNums  int    `json:"_screenshot_nums"`
-		ScreenshotInfos []struct {
-			Url           string `json:"url"`
-			ScanSceneDTOS []struct {
-				Scene      string  `json:"scene"`
-				Label      string  `json:"label"`
-				LabelDesc  string  `json:"label_desc"`
-				Suggestion string  `json:"suggestion"`
-				Rate       float64 `json:"rate"`
-				SubLabels  []struct {
-					SubLabel     string  `json:"sub_label"`
-					SubLabelDesc string  `json:"sub_label_desc"`
-					Rate         float64 `json:"rate"`
-				} `json:"sub_labels"`
-			} `json:"scan_scene_d_t_o_s"`
-		} `json:"screenshot_infos"`
-	} `json:"violation_info"`
-	FailMessage struct {
-		FailCode    int    `json:"fail_code"`
-		FailMessage string `json:"fail_message"`
-	} `json:"fail_message"`
-	QueueType int `json:"queue_type"`
-}
+// VideoGeneratingInfoDetail has the same fields as VideoInfo
+type VideoGeneratingInfoDetail = VideoInfo
 
 type AccountBalanceProResponse struct {
 	BaseResponse
@@ -1240,11 +1300,11 @@ type ModelBaseInfosProResponse struct {
 }
 
 type ModelBaseInfoPro struct {
-	Type              int    `json:"type"`
-	ModelCode         int    `json:"model_code"`
-	ModelVersion      string `json:"model_version"`
-	ModelDesc         string `json:"model_desc"`
-	ControlnetSupport string `json:"controlnet_support"`
+	Type              int       `json:"type"`
+	ModelCode         ModelCode `json:"model_code"`
+	ModelVersion      string    `json:"model_version"`
+	ModelDesc         string    `json:"model_desc"`
+	ControlnetSupport string    `json:"controlnet_support"`
 }
 
 type ControlNetOptionProResponse struct {
@@ -1253,42 +1313,34 @@ type ControlNetOptionProResponse struct {
 }
 
 type ControlNetOptionPro struct {
-	Code  int    `json:"code"`
-	Name  string `json:"name"`
-	Model []struct {
-		Code      int    `json:"code"`
-		Name      string `json:"name"`
-		IsDefault bool   `json:"is_default"`
-	} `json:"model"`
-	Preprocessor []struct {
-		Code       int    `json:"code"`
-		Name       string `json:"name"`
-		Resolution struct {
-			Name   string `json:"name"`
-			Min    int    `json:"min"`
-			Max    int    `json:"max"`
-			Step   int    `json:"step"`
-			Value  int    `json:"value"`
-			NameCn string `json:"name_cn"`
-		} `json:"resolution"`
-		ThresholdA struct {
-			Name   string `json:"name"`
-			Min    int    `json:"min"`
-			Max    int    `json:"max"`
-			Step   int    `json:"step"`
-			Value  int    `json:"value"`
-			NameCn string `json:"name_cn"`
-		} `json:"threshold_a"`
-		ThresholdB struct {
-			Name   string `json:"name"`
-			Min    int    `json:"min"`
-			Max    int    `json:"max"`
-			Step   int    `json:"step"`
-			Value  int    `json:"value"`
-			NameCn string `json:"name_cn"`
-		} `json:"threshold_b"`
-		IsDefault bool `json:"is_default"`
-	} `json:"preprocessor"`
+	Code         int                            `json:"code"`
+	Name         string                         `json:"name"`
+	Model        []ControlNetModelOption        `json:"model"`
+	Preprocessor []ControlNetPreprocessorOption `json:"preprocessor"`
+}
+
+type ControlNetModelOption struct {
+	Code      ModelCode `json:"code"`
+	Name      string    `json:"name"`
+	IsDefault bool      `json:"is_default"`
+}
+
+type ControlNetPreprocessorOption struct {
+	Code       int             `json:"code"`
+	Name       string          `json:"name"`
+	Resolution ControlNetRange `json:"resolution"`
+	ThresholdA ControlNetRange `json:"threshold_a"`
+	ThresholdB ControlNetRange `json:"threshold_b"`
+	IsDefault  bool            `json:"is_default"`
+}
+
+type ControlNetRange struct {
+	Name   string `json:"name"`
+	Min    int    `json:"min"`
+	Max    int    `json:"max"`
+	Step   int    `json:"step"`
+	Value  int    `json:"value"`
+	NameCn string `json:"name_cn"`
 }
 
 type ImageInfoProResponse struct {
@@ -1297,19 +1349,16 @@ type ImageInfoProResponse struct {
 }
 
 type ImageInfoPro struct {
-	ModelCode         int     `json:"model_code"`
-	Prompt            string  `json:"prompt"`
-	Width             int     `json:"width"`
-	Height            int     `json:"height"`
-	SupersizeMultiple float64 `json:"supersize_multiple"`
-	PrefineMultiple   float64 `json:"prefine_multiple"`
+	ModelCode         ModelCode `json:"model_code"`
+	Prompt            string    `json:"prompt"`
+	Width             int       `json:"width"`
+	Height            int       `json:"height"`
+	SupersizeMultiple float64   `json:"supersize_multiple"`
+	PrefineMultiple   float64   `json:"prefine_multiple"`
 	OptionInfo        struct {
-		StyleModel  string   `json:"style_model"`
-		Character   []string `json:"character"`
-		ModelFusion []struct {
-			Name   string  `json:"name"`
-			Weight float64 `json:"weight"`
-		} `json:"model_fusion"`
+		StyleModel  string            `json:"style_model"`
+		Character   []string          `json:"character"`
+		ModelFusion []ModelFusionInfo `json:"model_fusion"`
 	} `json:"option_info"`
 	AdvancedInfo struct {
 		UcPrompt     string  `json:"uc_prompt"`
@@ -1332,23 +1381,23 @@ type ImageInfoPro struct {
 		ResizeMode       string `json:"resize_mode"`
 	} `json:"img_to_img_info"`
 	ControlNetInfo []struct {
-		Type                 int    `json:"type"`
-		Preprocessor         int    `json:"preprocessor"`
-		Model                int    `json:"model"`
-		ControlWeight        int    `json:"control_weight"`
-		StartingControlStep  int    `json:"starting_control_step"`
-		EndingControlStep    int    `json:"ending_control_step"`
-		ControlMode          int    `json:"control_mode"`
-		ImageUrl             string `json:"image_url"`
-		ImageWidth           int    `json:"image_width"`
-		ImageHeight          int    `json:"image_height"`
-		MaskUrl              string `json:"mask_url"`
-		ProcessorRes         int    `json:"processor_res"`
-		ThresholdA           int    `json:"threshold_a"`
-		ThresholdB           int    `json:"threshold_b"`
-		ResizeMode           int    `json:"resize_mode"`
-		PixelPerfect         bool   `json:"pixel_perfect"`
-		PretreatmentImageUrl string `json:"pretreatment_image_url"`
+		Type                 int       `json:"type"`
+		Preprocessor         int       `json:"preprocessor"`
+		Model                ModelCode `json:"model"`
+		ControlWeight        int       `json:"control_weight"`
+		StartingControlStep  int       `json:"starting_control_step"`
+		EndingControlStep    int       `json:"ending_control_step"`
+		ControlMode          int       `json:"control_mode"`
+		ImageUrl             string    `json:"image_url"`
+		ImageWidth           int       `json:"image_width"`
+		ImageHeight          int       `json:"image_height"`
+		MaskUrl              string    `json:"mask_url"`
+		ProcessorRes         int       `json:"processor_res"`
+		ThresholdA           int       `json:"threshold_a"`
+		ThresholdB           int       `json:"threshold_b"`
+		ResizeMode           int       `json:"resize_mode"`
+		PixelPerfect         bool      `json:"pixel_perfect"`
+		PretreatmentImageUrl string    `json:"pretreatment_image_url"`
 	} `json:"control_net_info"`
 	CostInfo struct {
 		DurationCost int `json:"duration_cost"`
@@ -1379,10 +1428,7 @@ type ImageInfoPro struct {
 				CharacterOptions []struct {
 					Key string `json:"key"`
 				} `json:"character_options"`
-				ModelFusion []struct {
-					Name   string  `json:"name"`
-					Weight float64 `json:"weight"`
-				} `json:"model_fusion"`
+				ModelFusion []ModelFusionInfo `json:"model_fusion"`
 			} `json:"option_info"`
 		} `json:"bbox_control_states"`
 	} `json:"tiled_diffusion"`
@@ -1491,17 +1537,14 @@ type CameraGeneratingInfoResponse struct {
 }
 
 type CameraGeneratingInfo struct {
-	Key             string  `json:"key"`
-	Status          int     `json:"status"`
-	ArtworkUrl      string  `json:"artwork_url"`
-	ExpectedSeconds int     `json:"expected_seconds"`
-	StartGenTime    int     `json:"start_gen_time"`
-	CompleteTime    int     `json:"complete_time"`
-	CompletePercent float64 `json:"complete_percent"`
-	FailMessage     struct {
-		FailCode    int    `json:"fail_code"`
-		FailMessage string `json:"fail_message"`
-	} `json:"fail_message"`
+	Key             string      `json:"key"`
+	Status          int         `json:"status"`
+	ArtworkUrl      string      `json:"artwork_url"`
+	ExpectedSeconds int         `json:"expected_seconds"`
+	StartGenTime    int         `json:"start_gen_time"`
+	CompleteTime    int         `json:"complete_time"`
+	CompletePercent float64     `json:"complete_percent"`
+	FailMessage     FailMessage `json:"fail_message"`
 }
 
 type CameraInfoResponse struct {
@@ -1510,22 +1553,25 @@ type CameraInfoResponse struct {
 }
 
 type CameraInfo struct {
-	Key         string `json:"key"`
-	Status      int    `json:"status"`
-	ArtworkUrl  string `json:"artwork_url"`
-	Width       int    `json:"width"`
-	Height      int    `json:"height"`
-	Seed        string `json:"seed"`
-	FailMessage struct {
-		FailCode    int    `json:"fail_code"`
-		FailMessage string `json:"fail_message"`
-	} `json:"fail_message"`
+	Key         string      `json:"key"`
+	Status      int         `json:"status"`
+	ArtworkUrl  string      `json:"artwork_url"`
+	Width       int         `json:"width"`
+	Height      int         `json:"height"`
+	Seed        string      `json:"seed"`
+	FailMessage FailMessage `json:"fail_message"`
 }
 
 type LabOptionsRequest struct {
-	Input *struct {
-		OptionType LabOptionType `json:"optionType"`
-	} `json:"input"`
+	Input *LabOptionsInput `json:"input"`
+}
+
+type LabOptionsInput struct {
+	OptionType LabOptionType `json:"optionType"`
+}
+
+func NewLabOptionsRequest(optionType LabOptionType) *LabOptionsRequest {
+	return &LabOptionsRequest{Input: &LabOptionsInput{OptionType: optionType}}
 }
 
 func (l *LabOptionsRequest) String() string {
@@ -1567,35 +1613,23 @@ type LabInfo struct {
 	CompletePercent  int         `json:"completePercent"`
 	FailMessage      interface{} `json:"failMessage"`
 	Status           string      `json:"status"`
-	SegmentInfo      struct {
-		ImageUrl       string   `json:"imageUrl"`
-		ModelCode      int      `json:"modelCode"`
-		ModelName      string   `json:"modelName"`
-		NegativePoints []string `json:"negativePoints"`
-		PositivePoints []struct {
-			X int `json:"x"`
-			Y int `json:"y"`
-		} `json:"positivePoints"`
-		Prompt    interface{} `json:"prompt"`
-		Threshold int         `json:"threshold"`
-		ImageUrls []string    `json:"imageUrls"`
-	} `json:"segmentInfo"`
+	SegmentInfo      SegmentInfo `json:"segmentInfo"`
 	InfiniteZoomInfo struct {
-		InitImageUrl                string `json:"initImageUrl"`
-		ExitImageUrl                string `json:"exitImageUrl"`
-		ModelCode                   int    `json:"modelCode"`
-		ModelName                   string `json:"modelName"`
-		VideoSecond                 int    `json:"videoSecond"`
-		VideoFrameRate              int    `json:"videoFrameRate"`
-		VideoZoomMode               int    `json:"videoZoomMode"`
-		VideoStartFreezeFrameNumber int    `json:"videoStartFreezeFrameNumber"`
-		VideoEndFreezeFrameNumber   int    `json:"videoEndFreezeFrameNumber"`
-		MaskFeathering              int    `json:"maskFeathering"`
-		MovementSpeed               int    `json:"movementSpeed"`
-		Cfg                         int    `json:"cfg"`
-		PromptPrefix                string `json:"promptPrefix"`
-		PromptSuffix                string `json:"promptSuffix"`
-		UcPrompt                    string `json:"ucPrompt"`
+		InitImageUrl                string    `json:"initImageUrl"`
+		ExitImageUrl                string    `json:"exitImageUrl"`
+		ModelCode                   ModelCode `json:"modelCode"`
+		ModelName                   string    `json:"modelName"`
+		VideoSecond                 int       `json:"videoSecond"`
+		VideoFrameRate              int       `json:"videoFrameRate"`
+		VideoZoomMode               int       `json:"videoZoomMode"`
+		VideoStartFreezeFrameNumber int       `json:"videoStartFreezeFrameNumber"`
+		VideoEndFreezeFrameNumber   int       `json:"videoEndFreezeFrameNumber"`
+		MaskFeathering              int       `json:"maskFeathering"`
+		MovementSpeed               int       `json:"movementSpeed"`
+		Cfg                         int       `json:"cfg"`
+		PromptPrefix                string    `json:"promptPrefix"`
+		PromptSuffix                string    `json:"promptSuffix"`
+		UcPrompt                    string    `json:"ucPrompt"`
 		Fps                         []struct {
 			Second int    `json:"second"`
 			Prompt string `json:"prompt"`
@@ -1620,41 +1654,45 @@ type LabInfo struct {
 		NoiseTolerance    int         `json:"noiseTolerance"`
 		Quantize          int         `json:"quantize"`
 		AiArtworkId       interface{} `json:"aiArtworkId"`
-		VectorRes         []struct {
-			Url      string `json:"url"`
-			ShowName string `json:"showName"`
-			Type     string `json:"type"`
-		} `json:"vectorRes"`
+		VectorRes         []VectorRes `json:"vectorRes"`
 	} `json:"vectorInfo"`
 }
 
+type SegmentInfo struct {
+	ImageUrl       string      `json:"imageUrl"`
+	ModelCode      ModelCode   `json:"modelCode"`
+	ModelName      string      `json:"modelName"`
+	NegativePoints []Point     `json:"negativePoints"`
+	PositivePoints []Point     `json:"positivePoints"`
+	Prompt         interface{} `json:"prompt"`
+	Threshold      int         `json:"threshold"`
+	ImageUrls      []string    `json:"imageUrls"`
+}
+
 type CreateSegmentationRequest struct {
 	Input *CreateSegmentationInput `json:"input"`
 }
 
 type CreateSegmentationInput struct {
-	ImageUrl      string `json:"imageUrl"`
-	ImageUrlParam struct {
-		Key string `json:"key"`
-		Url string `json:"url"`
-	} `json:"imageUrlParam"`
-	ImageUrls      []string `json:"imageUrls"`
-	ModelCode      int      `json:"modelCode"`
-	NegativePoints []struct {
-		X struct {
-		} `json:"x"`
-		Y struct {
-		} `json:"y"`
-	} `json:"negativePoints"`
-	NotifyUrl      string `json:"notifyUrl"`
-	PositivePoints []struct {
-		X struct {
-		} `json:"x"`
-		Y struct {
-		} `json:"y"`
-	} `json:"positivePoints"`
-	Prompt    string `json:"prompt"`
-	Threshold int    `json:"threshold"`
+	ImageUrl       string        `json:"imageUrl"`
+	ImageUrlParam  LabImageParam `json:"imageUrlParam"`
+	ImageUrls      []string      `json:"imageUrls"`
+	ModelCode      ModelCode     `json:"modelCode"`
+	NegativePoints []Point       `json:"negativePoints"`
+	NotifyUrl      string        `json:"notifyUrl"`
+	PositivePoints []Point       `json:"positivePoints"`
+	Prompt         string        `json:"prompt"`
+	Threshold      int           `json:"threshold"`
+}
+
+type LabImageParam struct {
+	Key string `json:"key"`
+	Url string `json:"url"`
+}
+
+type Point struct {
+	X int `json:"x"`
+	Y int `json:"y"`
 }
 
 func (c *CreateSegmentationRequest) String() string {
@@ -1676,39 +1714,35 @@ type CreateSegmentationResult struct {
 }
 
 type CreateInfiniteZoomInput struct {
-	Cfg               int    `json:"cfg"`
-	Creativity        int    `json:"creativity"`
-	ExitImageUrl      string `json:"exitImageUrl"`
-	ExitImageUrlParam struct {
-		Key string `json:"key"`
-		Url string `json:"url"`
-	} `json:"exitImageUrlParam"`
-	Fps []struct {
-		Prompt string `json:"prompt"`
-		Second int    `json:"second"`
-	} `json:"fps"`
-	ImageHeight       int    `json:"imageHeight"`
-	ImageSeed         uint   `json:"imageSeed"`
-	ImageWidth        int    `json:"imageWidth"`
-	InitImageUrl      string `json:"initImageUrl"`
-	InitImageUrlParam struct {
-		Key string `json:"key"`
-		Url string `json:"url"`
-	} `json:"initImageUrlParam"`
-	MaskFeathering              int    `json:"maskFeathering"`
-	ModelCode                   int    `json:"modelCode"`
-	MovementSpeed               int    `json:"movementSpeed"`
-	NotifyUrl                   string `json:"notifyUrl"`
-	PromptPrefix                string `json:"promptPrefix"`
-	PromptSuffix                string `json:"promptSuffix"`
-	Sampler                     int    `json:"sampler"`
-	UcPrompt                    string `json:"ucPrompt"`
-	VideoEndFreezeFrameNumber   int    `json:"videoEndFreezeFrameNumber"`
-	VideoFrameRate              int    `json:"videoFrameRate"`
-	VideoSecond                 int    `json:"videoSecond"`
-	VideoStartFreezeFrameNumber int    `json:"videoStartFreezeFrameNumber"`
-	VideoUrl                    string `json:"videoUrl"`
-	VideoZoomMode               int    `json:"videoZoomMode"`
+	Cfg                         int               `json:"cfg"`
+	Creativity                  int               `json:"creativity"`
+	ExitImageUrl                string            `json:"exitImageUrl"`
+	ExitImageUrlParam           LabImageParam     `json:"exitImageUrlParam"`
+	Fps                         []InfiniteZoomFps `json:"fps"`
+	ImageHeight                 int               `json:"imageHeight"`
+	ImageSeed                   uint              `json:"imageSeed"`
+	ImageWidth                  int               `json:"imageWidth"`
+	InitImageUrl                string            `json:"initImageUrl"`
+	InitImageUrlParam           LabImageParam     `json:"initImageUrlParam"`
+	MaskFeathering              int               `json:"maskFeathering"`
+	ModelCode                   ModelCode         `json:"modelCode"`
+	MovementSpeed               int               `json:"movementSpeed"`
+	NotifyUrl                   string            `json:"notifyUrl"`
+	PromptPrefix                string            `json:"promptPrefix"`
+	PromptSuffix                string            `json:"promptSuffix"`
+	Sampler                     int               `json:"sampler"`
+	UcPrompt                    string            `json:"ucPrompt"`
+	VideoEndFreezeFrameNumber   int               `json:"videoEndFreezeFrameNumber"`
+	VideoFrameRate              int               `json:"videoFrameRate"`
+	VideoSecond                 int               `json:"videoSecond"`
+	VideoStartFreezeFrameNumber int               `json:"videoStartFreezeFrameNumber"`
+	VideoUrl                    string            `json:"videoUrl"`
+	VideoZoomMode               int               `json:"videoZoomMode"`
+}
+
+type InfiniteZoomFps struct {
+	Prompt string `json:"prompt"`
+	Second int    `json:"second"`
 }
 
 func (c *CreateInfiniteZoomInput) String() string {
@@ -1738,26 +1772,26 @@ type CreateInfiniteZoomResult struct {
 }
 
 type CreateVectorStudioInput struct {
-	Height         int    `json:"height"`
-	InitImage      string `json:"initImage"`
-	InitImageParam struct {
-		Key string `json:"key"`
-		Url string `json:"url"`
-	} `json:"initImageParam"`
-	NoiseTolerance int    `json:"noiseTolerance"`
-	NotifyUrl      string `json:"notifyUrl"`
-	Quantize       int    `json:"quantize"`
-	Style          int    `json:"style"`
-	Threshold      int    `json:"threshold"`
-	TransparentPNG bool   `json:"transparentPNG"`
-	VectorRes      []struct {
-		Type string `json:"type"`
-		Url  string `json:"url"`
-	} `json:"vectorRes"`
-	Vectorization     bool `json:"vectorization"`
-	WhiteMarginFormat bool `json:"whiteMarginFormat"`
-	WhiteOpaque       bool `json:"whiteOpaque"`
-	Width             int  `json:"width"`
+	Height            int           `json:"height"`
+	InitImage         string        `json:"initImage"`
+	InitImageParam    LabImageParam `json:"initImageParam"`
+	NoiseTolerance    int           `json:"noiseTolerance"`
+	NotifyUrl         string        `json:"notifyUrl"`
+	Quantize          int           `json:"quantize"`
+	Style             int           `json:"style"`
+	Threshold         int           `json:"threshold"`
+	TransparentPNG    bool          `json:"transparentPNG"`
+	VectorRes         []VectorRes   `json:"vectorRes"`
+	Vectorization     bool          `json:"vectorization"`
+	WhiteMarginFormat bool          `json:"whiteMarginFormat"`
+	WhiteOpaque       bool          `json:"whiteOpaque"`
+	Width             int           `json:"width"`
+}
+
+type VectorRes struct {
+	Type     string `json:"type"`
+	Url      string `json:"url"`
+	ShowName string `json:"showName,omitempty"`
 }
 
 type CreateVectorStudioRequest struct {
@@ -1810,36 +1844,33 @@ type SVDInfoResponse struct {
 }
 
 type SVDInfo struct {
-	Key             string  `json:"key"`
-	VideoUrl        string  `json:"video_url"`
-	InitImageUrl    string  `json:"init_image_url"`
-	Duration        int     `json:"duration"`
-	MotionAmplitude int     `json:"motion_amplitude"`
-	NoiseIntensity  float64 `json:"noise_intensity"`
-	RandomSeed      string  `json:"random_seed"`
-	Status          int     `json:"status"`
-	FailMessage     struct {
-		FailCode    int    `json:"fail_code"`
-		FailMessage string `json:"fail_message"`
-	} `json:"fail_message"`
+	Key             string      `json:"key"`
+	VideoUrl        string      `json:"video_url"`
+	InitImageUrl    string      `json:"init_image_url"`
+	Duration        int         `json:"duration"`
+	MotionAmplitude int         `json:"motion_amplitude"`
+	NoiseIntensity  float64     `json:"noise_intensity"`
+	RandomSeed      string      `json:"random_seed"`
+	Status          int         `json:"status"`
+	FailMessage     FailMessage `json:"fail_message"`
 }
 
 type CreateMidjourneyRequest struct {
-	MjParam             *MjParam `json:"mj_param"`
-	Model               int      `json:"model"`
-	Prompt              string   `json:"prompt"`
-	UcPrompt            string   `json:"uc_prompt"`
-	FullyCustomUcPrompt bool     `json:"fully_custom_uc_prompt,omitempty"`
-	Num                 int      `json:"num"`
-	Width               int      `json:"width"`
-	Height              int      `json:"height"`
-	InitImageUrl        string   `json:"init_image_url,omitempty"`
-	InitWidth           int      `json:"init_width,omitempty"`
-	InitHeight          int      `json:"init_height,omitempty"`
-	CreativityDegree    int      `json:"creativity_degree,omitempty"`
-	Seed                string   `json:"seed,omitempty"`
-	Cfg                 float64  `json:"cfg,omitempty"`
-	NotifyUrl           string   `json:"notify_url,omitempty"`
+	MjParam             *MjParam  `json:"mj_param"`
+	Model               ModelCode `json:"model"`
+	Prompt              string    `json:"prompt"`
+	UcPrompt            string    `json:"uc_prompt"`
+	FullyCustomUcPrompt bool      `json:"fully_custom_uc_prompt,omitempty"`
+	Num                 int       `json:"num"`
+	Width               int       `json:"width"`
+	Height              int       `json:"height"`
+	InitImageUrl        string    `json:"init_image_url,omitempty"`
+	InitWidth           int       `json:"init_width,omitempty"`
+	InitHeight          int       `json:"init_height,omitempty"`
+	CreativityDegree    int       `json:"creativity_degree,omitempty"`
+	Seed                string    `json:"seed,omitempty"`
+	Cfg                 float64   `json:"cfg,omitempty"`
+	NotifyUrl           string    `json:"notify_url,omitempty"`
 }
 
 func (c *CreateMidjourneyRequest) String() string {
@@ -1851,7 +1882,7 @@ type CreateMidjourneyResponse struct {
 }
 
 type CreateFluxRequest struct {
-	Model            int            `json:"model"`
+	Model            ModelCode      `json:"model"`
 	Prompt           string         `json:"prompt"`
 	Num              int            `json:"num"`
 	Width            int            `json:"width,omitempty"`
@@ -3,7 +3,7 @@ package wujiesdk
 // @Title        enums.go
 // @Description  enums
 // @Create       XdpCs 2023-10-17 20:48
-// @Update       XdpCs 2023-11-26 15:08
+// @Update       XdpCs 2026-10-19 14:20
 
 // PromptSubmitType prompt submit type /ai/optimize/prompt/submit
 type PromptSubmitType int8
@@ -85,3 +85,26 @@ const (
 	VectorLabInfoType       LabInfoType = "VECTOR"
 	VideoLabInfoType        LabInfoType = "VIDEO"
 )
+
+// VideoQueueType video queue type /ai/video/create
+type VideoQueueType int
+
+const (
+	NormalVideoQueueType VideoQueueType = iota + 1
+	NightVideoQueueType
+)
+
+// JobStatus status of generating info /ai/generating_info /ai/supersize /ai/video/generating_info
+type JobStatus int
+
+const (
+	QueuingJobStatus JobStatus = iota
+	GeneratingJobStatus
+	SuccessJobStatus
+	FailedJobStatus
+)
+
+// Terminal report whether the job is finished
+func (j JobStatus) Terminal() bool {
+	return j == SuccessJobStatus || j == FailedJobStatus
+}
@@ -6,4 +6,7 @@ replace github.com/XdpCs/wujiesdk => ../
 
 require github.com/XdpCs/wujiesdk v1.0.4
 
-require github.com/patrickmn/go-cache v2.1.0+incompatible // indirect
+require (
+	github.com/patrickmn/go-cache v2.1.0+incompatible // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
@@ -3,3 +3,5 @@ module github.com/XdpCs/wujiesdk
 go 1.18
 
 require github.com/patrickmn/go-cache v2.1.0+incompatible
+
+require gopkg.in/yaml.v3 v3.0.1
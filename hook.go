@@ -0,0 +1,58 @@
+package wujiesdk
+
+// @Title        hook.go
+// @Description  hook before and after caller's call
+// @Create       XdpCs 2026-10-19 11:02
+// @Update       XdpCs 2026-10-20 09:40
+
+import (
+	"context"
+	"fmt"
+)
+
+// Call is the information of one Caller's call
+type Call struct {
+	Name    string      // name of Caller's method, such as CreateImage
+	Router  WujieRouter // router requested by the call
+	Request interface{} // request of the call, such as *CreateImageRequest, keys []string, key string or nil
+}
+
+func (c *Call) String() string {
+	return fmt.Sprintf("%s(%s)", c.Name, c.Router)
+}
+
+// CallerHook uses BeforeCall and AfterCall,
+// BeforeCall may change the request in place and stops the call when it returns an error,
+// AfterCall gets the decoded result, result may be a nil pointer when err is not nil,
+// when a BeforeCall stops the call, AfterCall of hooks before it gets the error so that they can release what they reserved
+type CallerHook interface {
+	BeforeCall(ctx context.Context, call *Call) error
+	AfterCall(ctx context.Context, call *Call, code WujieCode, result interface{}, err error)
+}
+
+// CallerHooks is a slice of CallerHook
+type CallerHooks []CallerHook
+
+// AddCallerHooks add hooks, hooks run in the order they are added
+func (c *Caller) AddCallerHooks(hooks ...CallerHook) {
+	c.CallerHooks = append(c.CallerHooks, hooks...)
+}
+
+func (c *Caller) beforeCall(ctx context.Context, call *Call) error {
+	for i, hook := range c.CallerHooks {
+		if err := hook.BeforeCall(ctx, call); err != nil {
+			err = fmt.Errorf("hook.BeforeCall: call: %v, error: %w", call, err)
+			for _, before := range c.CallerHooks[:i] {
+				before.AfterCall(ctx, call, ErrorWujieCode, nil, err)
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *Caller) afterCall(ctx context.Context, call *Call, code WujieCode, result interface{}, err error) {
+	for _, hook := range c.CallerHooks {
+		hook.AfterCall(ctx, call, code, result, err)
+	}
+}
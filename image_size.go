@@ -0,0 +1,244 @@
+package wujiesdk
+
+// @Title        image_size.go
+// @Description  probe image dimensions to fill init width and height of requests
+// @Create       XdpCs 2026-10-19 18:55
+// @Update       XdpCs 2026-10-20 13:30
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"net/http"
+	neturl "net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/patrickmn/go-cache"
+)
+
+// ErrUnsupportedImageFormat is the error of image which is not PNG, JPEG, GIF or WebP
+var ErrUnsupportedImageFormat = errors.New("unsupported image format")
+
+// ImageSize is dimensions of image
+type ImageSize struct {
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+	Format string `json:"format"` // png, jpeg, gif or webp
+}
+
+// ImageProbeError is the error of probing image of Url
+type ImageProbeError struct {
+	Url string
+	Err error
+}
+
+func (i *ImageProbeError) Error() string {
+	return fmt.Sprintf("probe image: url: %s, error: %v", i.Url, i.Err)
+}
+
+func (i *ImageProbeError) Unwrap() error {
+	return i.Err
+}
+
+// ImageProber probes dimensions of images by reading only the header of them, results are cached by url,
+// it is a CallerHook filling missing InitWidth and InitHeight of CreateImageRequest, CreateMidjourneyRequest,
+// CreateFluxRequest and YouthifyRequest, and ImageWidth and ImageHeight of ControlNetParams,
+// add it after UploadHook when local inputs are used
+type ImageProber struct {
+	HTTPClient *http.Client
+	cache      *cache.Cache
+	once       sync.Once
+}
+
+// NewImageProber new image prober with http client of c
+func NewImageProber(c *Client) *ImageProber {
+	return &ImageProber{HTTPClient: c.HTTPClient()}
+}
+
+// Probe get dimensions of image of url, url may be a data: url or a file:// url
+func (p *ImageProber) Probe(ctx context.Context, url string) (ImageSize, error) {
+	if size, found := p.sizes().Get(url); found {
+		return size.(ImageSize), nil
+	}
+	size, err := p.probe(ctx, url)
+	if err != nil {
+		return ImageSize{}, &ImageProbeError{Url: url, Err: err}
+	}
+	p.sizes().Set(url, size, cache.DefaultExpiration)
+	return size, nil
+}
+
+func (p *ImageProber) probe(ctx context.Context, url string) (ImageSize, error) {
+	if strings.HasPrefix(strings.ToLower(url), "data:") {
+		r, err := dataURLReader(url)
+		if err != nil {
+			return ImageSize{}, err
+		}
+		return DecodeImageSize(r)
+	}
+	if IsLocalInput(url) {
+		if strings.HasPrefix(strings.ToLower(url), "file://") {
+			url = url[len("file://"):]
+		}
+		f, err := os.Open(url)
+		if err != nil {
+			return ImageSize{}, fmt.Errorf("os.Open: %w", err)
+		}
+		defer func() { _ = f.Close() }()
+		return DecodeImageSize(f)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, http.NoBody)
+	if err != nil {
+		return ImageSize{}, fmt.Errorf("http.NewRequestWithContext: %w", err)
+	}
+	client := p.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return ImageSize{}, fmt.Errorf("client.Do: %w", err)
+	}
+	// body is closed after the header is decoded, the rest of image is not read
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode < http.StatusOK || resp.StatusCode > 299 {
+		return ImageSize{}, fmt.Errorf("http status code: %d, %s", resp.StatusCode, http.StatusText(resp.StatusCode))
+	}
+	return DecodeImageSize(resp.Body)
+}
+
+// dataURLReader get reader of payload of data: url, such as data:image/png;base64,iVBORw0KGgo...
+func dataURLReader(url string) (io.Reader, error) {
+	comma := strings.IndexByte(url, ',')
+	if comma < 0 {
+		return nil, fmt.Errorf("dataURLReader: error: data url has no comma")
+	}
+	meta, payload := url[len("data:"):comma], url[comma+1:]
+	if strings.HasSuffix(strings.ToLower(meta), ";base64") {
+		return base64.NewDecoder(base64.RawStdEncoding, strings.NewReader(strings.TrimRight(payload, "="))), nil
+	}
+	unescaped, err := neturl.PathUnescape(payload)
+	if err != nil {
+		return nil, fmt.Errorf("url.PathUnescape: %w", err)
+	}
+	return strings.NewReader(unescaped), nil
+}
+
+// DecodeImageSize decode dimensions of PNG, JPEG, GIF or WebP from the header of r
+func DecodeImageSize(r io.Reader) (ImageSize, error) {
+	br := bufio.NewReaderSize(r, 512)
+	head, err := br.Peek(30)
+	if err != nil && err != io.EOF {
+		return ImageSize{}, fmt.Errorf("br.Peek: %w", err)
+	}
+	if len(head) >= 12 && string(head[:4]) == "RIFF" && string(head[8:12]) == "WEBP" {
+		return decodeWebPSize(head)
+	}
+	config, format, err := image.DecodeConfig(br)
+	if err != nil {
+		if errors.Is(err, image.ErrFormat) {
+			return ImageSize{}, ErrUnsupportedImageFormat
+		}
+		return ImageSize{}, fmt.Errorf("image.DecodeConfig: %w", err)
+	}
+	return ImageSize{Width: config.Width, Height: config.Height, Format: format}, nil
+}
+
+// decodeWebPSize parse the first chunk of WebP, head has 30 bytes at least
+func decodeWebPSize(head []byte) (ImageSize, error) {
+	if len(head) < 30 {
+		return ImageSize{}, fmt.Errorf("decodeWebPSize: error: webp header is truncated")
+	}
+	size := ImageSize{Format: "webp"}
+	switch string(head[12:16]) {
+	case "VP8 ":
+		if !bytes.Equal(head[23:26], []byte{0x9d, 0x01, 0x2a}) {
+			return ImageSize{}, fmt.Errorf("decodeWebPSize: error: invalid VP8 start code")
+		}
+		size.Width = int(binary.LittleEndian.Uint16(head[26:28]) & 0x3fff)
+		size.Height = int(binary.LittleEndian.Uint16(head[28:30]) & 0x3fff)
+	case "VP8L":
+		if head[20] != 0x2f {
+			return ImageSize{}, fmt.Errorf("decodeWebPSize: error: invalid VP8L signature")
+		}
+		b := head[21:25]
+		size.Width = 1 + (int(b[0]) | int(b[1]&0x3f)<<8)
+		size.Height = 1 + (int(b[1]>>6) | int(b[2])<<2 | int(b[3]&0x0f)<<10)
+	case "VP8X":
+		size.Width = 1 + (int(head[24]) | int(head[25])<<8 | int(head[26])<<16)
+		size.Height = 1 + (int(head[27]) | int(head[28])<<8 | int(head[29])<<16)
+	default:
+		return ImageSize{}, fmt.Errorf("decodeWebPSize: chunk: %q, error: %w", head[12:16], ErrUnsupportedImageFormat)
+	}
+	return size, nil
+}
+
+// FillImageSizes fill missing dimensions of images referenced by request in place,
+// request is a pointer of CreateImageRequest, CreateImageProRequest, CreateMidjourneyRequest,
+// CreateFluxRequest or YouthifyRequest
+func (p *ImageProber) FillImageSizes(ctx context.Context, request interface{}) error {
+	type target struct {
+		field         string
+		url           string
+		width, height *int
+	}
+	var targets []target
+	switch r := request.(type) {
+	case *CreateImageRequest:
+		targets = append(targets, target{"init_image_url", r.InitImageURL, &r.InitWidth, &r.InitHeight})
+	case *CreateMidjourneyRequest:
+		targets = append(targets, target{"init_image_url", r.InitImageUrl, &r.InitWidth, &r.InitHeight})
+	case *CreateFluxRequest:
+		targets = append(targets, target{"init_image_url", r.InitImageUrl, &r.InitWidth, &r.InitHeight})
+	case *YouthifyRequest:
+		targets = append(targets, target{"image_url", r.ImageURL, &r.InitWidth, &r.InitHeight})
+	case *CreateImageProRequest:
+		for i := range r.ControlNetParams {
+			c := &r.ControlNetParams[i]
+			targets = append(targets, target{fmt.Sprintf("control_net_params[%d].image_url", i), c.ImageUrl, &c.ImageWidth, &c.ImageHeight})
+		}
+	}
+	for _, t := range targets {
+		if t.url == "" || (*t.width > 0 && *t.height > 0) {
+			continue
+		}
+		size, err := p.Probe(ctx, t.url)
+		if err != nil {
+			return fmt.Errorf("p.Probe: %s: %w", t.field, err)
+		}
+		*t.width, *t.height = size.Width, size.Height
+	}
+	return nil
+}
+
+// BeforeCall fill missing dimensions of call.Request
+func (p *ImageProber) BeforeCall(ctx context.Context, call *Call) error {
+	if err := p.FillImageSizes(ctx, call.Request); err != nil {
+		return fmt.Errorf("p.FillImageSizes: %s: %w", call.Name, err)
+	}
+	return nil
+}
+
+// AfterCall do nothing
+func (p *ImageProber) AfterCall(_ context.Context, _ *Call, _ WujieCode, _ interface{}, _ error) {
+}
+
+// sizes get cache of image sizes, it is created on first use so that the zero value is usable
+func (p *ImageProber) sizes() *cache.Cache {
+	p.once.Do(func() {
+		p.cache = cache.New(time.Hour, 10*time.Minute)
+	})
+	return p.cache
+}
@@ -0,0 +1,26 @@
+package wujiesdk_test
+
+// @Title        image_size_test.go
+// @Description  test image prober
+// @Create       XdpCs 2026-10-20 13:30
+// @Update       XdpCs 2026-10-20 13:30
+
+import (
+	"context"
+	"testing"
+
+	"github.com/XdpCs/wujiesdk"
+)
+
+// 1x1 png
+const pixelDataURL = "data:image/png;base64,iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAYAAAAfFcSJAAAADUlEQVR42mNk+M9QDwADhgGAWjR9awAAAABJRU5ErkJggg=="
+
+func TestImageProberZeroValue(t *testing.T) {
+	p := &wujiesdk.ImageProber{}
+	for i := 0; i < 2; i++ {
+		size, err := p.Probe(context.Background(), pixelDataURL)
+		if err != nil || size.Width != 1 || size.Height != 1 {
+			t.Errorf("Probe: %+v, error: %v, want 1x1", size, err)
+		}
+	}
+}
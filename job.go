@@ -0,0 +1,69 @@
+package wujiesdk
+
+// @Title        job.go
+// @Description  handle of created images for polling
+// @Create       XdpCs 2026-10-19 21:15
+// @Update       XdpCs 2026-10-20 11:10
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// DefaultJobPollInterval is the interval of polling generating info
+const DefaultJobPollInterval = 3 * time.Second
+
+// ImageJob is the handle of images created by CreateImage
+type ImageJob struct {
+	Caller               *Caller
+	Request              *CreateImageRequest
+	Keys                 []string
+	ExpectedSecond       int
+	ExpectedIntegralCost int
+}
+
+// SubmitImage create image and get the handle of it
+func (c *Caller) SubmitImage(ctx context.Context, cReq *CreateImageRequest) (*ImageJob, error) {
+	_, data, err := c.CreateImage(ctx, cReq)
+	if err != nil {
+		return nil, fmt.Errorf("c.CreateImage: %w", err)
+	}
+	job := &ImageJob{Caller: c, Request: cReq, Keys: data.Keys, ExpectedIntegralCost: data.ExpectedIntegralCost}
+	for _, r := range data.Results {
+		if len(data.Keys) == 0 {
+			job.Keys = append(job.Keys, r.Key)
+		}
+		if r.ExpectedSecond > job.ExpectedSecond {
+			job.ExpectedSecond = r.ExpectedSecond
+		}
+	}
+	return job, nil
+}
+
+// Status get generating info of images
+func (j *ImageJob) Status(ctx context.Context) ([]ImageGeneratingInfo, error) {
+	_, infos, err := j.Caller.GeneratingInfo(ctx, j.Keys)
+	if err != nil {
+		return nil, fmt.Errorf("j.Caller.GeneratingInfo: %w", err)
+	}
+	return infos, nil
+}
+
+// Wait poll generating info every interval until every image is finished, interval <= 0 uses DefaultJobPollInterval,
+// it returns error of the first failed image with generating info of all images
+func (j *ImageJob) Wait(ctx context.Context, interval time.Duration) ([]ImageGeneratingInfo, error) {
+	state, err := j.Caller.WaitJob(ctx, ImageProduct, j.Keys, interval)
+	if err != nil {
+		if state == nil {
+			return nil, err
+		}
+		infos, _ := state.Result.([]ImageGeneratingInfo)
+		return infos, err
+	}
+	infos, _ := state.Result.([]ImageGeneratingInfo)
+	if state.Err != nil {
+		return infos, fmt.Errorf("ImageJob.Wait: %w", state.Err)
+	}
+	return infos, nil
+}
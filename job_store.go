@@ -0,0 +1,340 @@
+package wujiesdk
+
+// @Title        job_store.go
+// @Description  store of submitted jobs which survives process restarts
+// @Create       XdpCs 2026-10-20 00:15
+// @Update       XdpCs 2026-10-20 11:40
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ErrJobNotFound is returned by JobStore.Get when no job has the id
+var ErrJobNotFound = errors.New("job not found")
+
+// DefaultJobStoreCompactAfter is the number of stale records of FileJobStore which triggers compaction
+const DefaultJobStoreCompactAfter = 1000
+
+// StoredJob is a job submitted by create call, ID is its first key
+type StoredJob struct {
+	ID           string          `json:"id"`
+	Product      Product         `json:"product"`
+	Call         string          `json:"call"`
+	Request      json.RawMessage `json:"request"`
+	Keys         []string        `json:"keys"`
+	Status       JobStatus       `json:"status"`
+	ExpectedCost Cost            `json:"expected_cost"`
+	ActualCost   Cost            `json:"actual_cost"`
+	Error        string          `json:"error,omitempty"`
+	Tenant       Tenant          `json:"tenant"`
+	CreatedAt    time.Time       `json:"created_at"`
+	UpdatedAt    time.Time       `json:"updated_at"`
+}
+
+// DecodeRequest decode Request into the create request of Product, such as *CreateImageRequest
+func (j *StoredJob) DecodeRequest() (interface{}, error) {
+	request, err := j.Product.NewRequest()
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(j.Request, request); err != nil {
+		return nil, fmt.Errorf("json.Unmarshal: id: %s, error: %w", j.ID, err)
+	}
+	return request, nil
+}
+
+// JobFilter selects stored jobs, zero field matches all
+type JobFilter struct {
+	Product    Product
+	TenantID   string
+	Unfinished bool // only jobs whose Status is not terminal
+}
+
+// Match report whether job is selected by filter
+func (f *JobFilter) Match(job *StoredJob) bool {
+	if f.Product != "" && job.Product != f.Product {
+		return false
+	}
+	if f.TenantID != "" && job.Tenant.ID != f.TenantID {
+		return false
+	}
+	if f.Unfinished && job.Status.Terminal() {
+		return false
+	}
+	return true
+}
+
+// JobStore keeps submitted jobs by ID, Put inserts or replaces the job
+type JobStore interface {
+	Put(ctx context.Context, job *StoredJob) error
+	Get(ctx context.Context, id string) (*StoredJob, error)
+	List(ctx context.Context, filter JobFilter) ([]StoredJob, error)
+	Delete(ctx context.Context, id string) error
+}
+
+// jobIndex is jobs by ID, List returns them in the order of CreatedAt
+type jobIndex map[string]StoredJob
+
+func (idx jobIndex) get(id string) (*StoredJob, error) {
+	job, ok := idx[id]
+	if !ok {
+		return nil, fmt.Errorf("id: %s, error: %w", id, ErrJobNotFound)
+	}
+	return &job, nil
+}
+
+func (idx jobIndex) list(filter JobFilter) []StoredJob {
+	var jobs []StoredJob
+	for id := range idx {
+		job := idx[id]
+		if filter.Match(&job) {
+			jobs = append(jobs, job)
+		}
+	}
+	sort.Slice(jobs, func(i, j int) bool {
+		if jobs[i].CreatedAt.Equal(jobs[j].CreatedAt) {
+			return jobs[i].ID < jobs[j].ID
+		}
+		return jobs[i].CreatedAt.Before(jobs[j].CreatedAt)
+	})
+	return jobs
+}
+
+// MemoryJobStore keeps jobs in memory, it is safe for concurrent use
+type MemoryJobStore struct {
+	mu   sync.RWMutex
+	jobs jobIndex
+}
+
+// NewMemoryJobStore new memory job store
+func NewMemoryJobStore() *MemoryJobStore {
+	return &MemoryJobStore{jobs: make(jobIndex)}
+}
+
+// Put insert or replace job
+func (m *MemoryJobStore) Put(_ context.Context, job *StoredJob) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.jobs[job.ID] = *job
+	return nil
+}
+
+// Get get job by id, the error wraps ErrJobNotFound if it does not exist
+func (m *MemoryJobStore) Get(_ context.Context, id string) (*StoredJob, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.jobs.get(id)
+}
+
+// List get jobs selected by filter in the order they are created
+func (m *MemoryJobStore) List(_ context.Context, filter JobFilter) ([]StoredJob, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.jobs.list(filter), nil
+}
+
+// Delete delete job, deleting a missing job is not an error
+func (m *MemoryJobStore) Delete(_ context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.jobs, id)
+	return nil
+}
+
+// jobRecord is one line of FileJobStore, Job is nil for deletion
+type jobRecord struct {
+	ID  string     `json:"id"`
+	Job *StoredJob `json:"job,omitempty"`
+}
+
+// FileJobStore keeps jobs in memory and appends every change to a file as JSON lines,
+// the file is replayed by OpenFileJobStore and rewritten by Compact, it is safe for concurrent use
+type FileJobStore struct {
+	// CompactAfter is the number of stale records which triggers Compact on Put or Delete, compaction is manual if < 0
+	CompactAfter int
+	// Logger logs failures of compaction triggered by Put or Delete, the change itself is saved, nothing is logged if nil
+	Logger *Logger
+
+	mu    sync.Mutex
+	path  string
+	file  *os.File
+	jobs  jobIndex
+	stale int
+}
+
+// OpenFileJobStore open or create the job store file at path and replay it,
+// a torn last line left by a crash is dropped
+func OpenFileJobStore(path string) (*FileJobStore, error) {
+	f := &FileJobStore{CompactAfter: DefaultJobStoreCompactAfter, Logger: NewDefaultLogger(), path: path, jobs: make(jobIndex)}
+	if err := f.replay(); err != nil {
+		return nil, err
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("os.OpenFile: path: %s, error: %w", path, err)
+	}
+	f.file = file
+	return f, nil
+}
+
+func (f *FileJobStore) replay() error {
+	data, err := os.ReadFile(f.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("os.ReadFile: path: %s, error: %w", f.path, err)
+	}
+	reader := bufio.NewReader(bytes.NewReader(data))
+	var offset int64
+	for line := 1; ; line++ {
+		text, err := reader.ReadBytes('\n')
+		if errors.Is(err, io.EOF) {
+			if len(bytes.TrimSpace(text)) > 0 {
+				// the last record was not written completely, cut it so that later records start on a new line
+				if err := os.Truncate(f.path, offset); err != nil {
+					return fmt.Errorf("os.Truncate: path: %s, error: %w", f.path, err)
+				}
+			}
+			return nil
+		}
+		offset += int64(len(text))
+		if len(bytes.TrimSpace(text)) == 0 {
+			continue
+		}
+		var record jobRecord
+		if err := json.Unmarshal(text, &record); err != nil {
+			return fmt.Errorf("json.Unmarshal: path: %s, line: %d, error: %w", f.path, line, err)
+		}
+		f.apply(&record)
+	}
+}
+
+// apply apply record to jobs and count records it makes stale, a deletion counts once
+func (f *FileJobStore) apply(record *jobRecord) {
+	if record.Job == nil {
+		delete(f.jobs, record.ID)
+		f.stale++
+		return
+	}
+	if _, ok := f.jobs[record.ID]; ok {
+		f.stale++
+	}
+	f.jobs[record.ID] = *record.Job
+}
+
+func (f *FileJobStore) write(record *jobRecord) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("json.Marshal: %w", err)
+	}
+	line = append(line, '\n')
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, err := f.file.Write(line); err != nil {
+		return fmt.Errorf("f.file.Write: path: %s, error: %w", f.path, err)
+	}
+	if err := f.file.Sync(); err != nil {
+		return fmt.Errorf("f.file.Sync: path: %s, error: %w", f.path, err)
+	}
+	f.apply(record)
+	// the record is saved already, failed compaction is retried by the next write
+	if f.CompactAfter >= 0 && f.stale > f.CompactAfter {
+		if err := f.compact(); err != nil && f.Logger != nil {
+			f.Logger.Printf("%sFileJobStore: path: %s, compact error: %v\n", LogTag[LogWarn-1], f.path, err)
+		}
+	}
+	return nil
+}
+
+// Put insert or replace job and append it to the file
+func (f *FileJobStore) Put(_ context.Context, job *StoredJob) error {
+	return f.write(&jobRecord{ID: job.ID, Job: job})
+}
+
+// Get get job by id, the error wraps ErrJobNotFound if it does not exist
+func (f *FileJobStore) Get(_ context.Context, id string) (*StoredJob, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.jobs.get(id)
+}
+
+// List get jobs selected by filter in the order they are created
+func (f *FileJobStore) List(_ context.Context, filter JobFilter) ([]StoredJob, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.jobs.list(filter), nil
+}
+
+// Delete delete job and append the deletion to the file, deleting a missing job is not an error
+func (f *FileJobStore) Delete(_ context.Context, id string) error {
+	f.mu.Lock()
+	_, ok := f.jobs[id]
+	f.mu.Unlock()
+	if !ok {
+		return nil
+	}
+	return f.write(&jobRecord{ID: id})
+}
+
+// Compact rewrite the file with one record per job, the file is replaced atomically by rename
+func (f *FileJobStore) Compact() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.compact()
+}
+
+func (f *FileJobStore) compact() error {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, job := range f.jobs.list(JobFilter{}) {
+		job := job
+		if err := enc.Encode(&jobRecord{ID: job.ID, Job: &job}); err != nil {
+			return fmt.Errorf("json.Encoder.Encode: error: %w", err)
+		}
+	}
+	tmp := f.path + ".tmp"
+	file, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("os.OpenFile: path: %s, error: %w", tmp, err)
+	}
+	if _, err := file.Write(buf.Bytes()); err != nil {
+		file.Close()
+		return fmt.Errorf("file.Write: path: %s, error: %w", tmp, err)
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return fmt.Errorf("file.Sync: path: %s, error: %w", tmp, err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("file.Close: path: %s, error: %w", tmp, err)
+	}
+	if err := os.Rename(tmp, f.path); err != nil {
+		return fmt.Errorf("os.Rename: path: %s, error: %w", f.path, err)
+	}
+	appended, err := os.OpenFile(f.path, os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("os.OpenFile: path: %s, error: %w", f.path, err)
+	}
+	f.file.Close()
+	f.file = appended
+	f.stale = 0
+	return nil
+}
+
+// Close close the file
+func (f *FileJobStore) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.file.Close()
+}
@@ -0,0 +1,162 @@
+package wujiesdk
+
+// @Title        job_store_hook.go
+// @Description  record jobs submitted by create calls and resume unfinished jobs after restarts
+// @Create       XdpCs 2026-10-20 00:15
+// @Update       XdpCs 2026-10-20 10:55
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// JobStoreHook is a CallerHook which puts the request and keys of every succeeded create call into Store,
+// jobs stay unfinished in Store until JobResumer sees them finish
+type JobStoreHook struct {
+	Caller *Caller
+	Store  JobStore
+	Now    func() time.Time
+}
+
+// NewJobStoreHook new job store hook
+func NewJobStoreHook(c *Caller, store JobStore) *JobStoreHook {
+	return &JobStoreHook{Caller: c, Store: store, Now: time.Now}
+}
+
+// BeforeCall do nothing
+func (h *JobStoreHook) BeforeCall(_ context.Context, _ *Call) error {
+	return nil
+}
+
+// AfterCall put the job submitted by create call into Store
+func (h *JobStoreHook) AfterCall(ctx context.Context, call *Call, code WujieCode, result interface{}, err error) {
+	if err != nil || code != OKWujieCode || !isCreateCall(call) {
+		return
+	}
+	product, _ := ProductOf(call.Request)
+	keys := createdKeys(result)
+	if r, ok := call.Request.(*PromptOptimizeSubmitRequest); ok {
+		keys = []string{r.TaskID}
+	}
+	if len(keys) == 0 {
+		h.logf("JobStoreHook: call: %v, error: no keys in result\n", call)
+		return
+	}
+	request, err := json.Marshal(call.Request)
+	if err != nil {
+		h.logf("JobStoreHook: call: %v, json.Marshal error: %v\n", call, err)
+		return
+	}
+	now := h.now()
+	job := &StoredJob{
+		ID:        keys[0],
+		Product:   product,
+		Call:      call.Name,
+		Request:   request,
+		Keys:      keys,
+		Status:    QueuingJobStatus,
+		Tenant:    requestTenant(ctx, call.Request),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	job.ExpectedCost, _ = expectedCost(result)
+	if err := h.Store.Put(ctx, job); err != nil {
+		h.logf("JobStoreHook: call: %v, keys: %v, put error: %v\n", call, keys, err)
+	}
+}
+
+func (h *JobStoreHook) now() time.Time {
+	if h.Now != nil {
+		return h.Now()
+	}
+	return time.Now()
+}
+
+func (h *JobStoreHook) logf(format string, a ...interface{}) {
+	if h.Caller != nil && h.Caller.Client != nil {
+		h.Caller.Client.WriteLog(LogWarn, format, a...)
+	}
+}
+
+// JobResumer polls jobs of Store until they finish and saves their status, actual cost and error
+type JobResumer struct {
+	Caller       *Caller
+	Store        JobStore
+	Concurrency  int           // jobs polled at the same time by Resume, DefaultBatchConcurrency if <= 0
+	PollInterval time.Duration // DefaultJobPollInterval if <= 0
+	Now          func() time.Time
+	// OnFinish is called after a finished job is saved, state.Result is the result to collect
+	OnFinish func(ctx context.Context, job *StoredJob, state *JobState)
+}
+
+// NewJobResumer new job resumer
+func NewJobResumer(c *Caller, store JobStore) *JobResumer {
+	return &JobResumer{Caller: c, Store: store, Concurrency: DefaultBatchConcurrency, Now: time.Now}
+}
+
+// Resume reload unfinished jobs selected by filter and wait for them, it returns when they finish or ctx is done,
+// jobs which can not be polled are reported by BatchError with their IDs as keys and stay unfinished in Store
+func (r *JobResumer) Resume(ctx context.Context, filter JobFilter) ([]StoredJob, error) {
+	filter.Unfinished = true
+	jobs, err := r.Store.List(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("r.Store.List: %w", err)
+	}
+	concurrency := r.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultBatchConcurrency
+	}
+	errs := make([]error, len(jobs))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i := range jobs {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			_, errs[i] = r.Wait(ctx, &jobs[i])
+		}(i)
+	}
+	wg.Wait()
+
+	e := &BatchError{}
+	for i, err := range errs {
+		if err != nil {
+			e.Keys = append(e.Keys, jobs[i].ID)
+			e.Errs = append(e.Errs, err)
+		}
+	}
+	if len(e.Keys) > 0 {
+		return jobs, e
+	}
+	return jobs, nil
+}
+
+// Wait poll job until it finishes, then update job and save it into Store
+func (r *JobResumer) Wait(ctx context.Context, job *StoredJob) (*JobState, error) {
+	state, err := r.Caller.WaitJob(ctx, job.Product, job.Keys, r.PollInterval)
+	if err != nil {
+		return state, fmt.Errorf("r.Caller.WaitJob: id: %s, error: %w", job.ID, err)
+	}
+	job.Status, job.Error = SuccessJobStatus, ""
+	if state.Err != nil {
+		job.Status, job.Error = FailedJobStatus, state.Err.Error()
+	}
+	job.ActualCost = state.ActualCost(job.Product, job.ExpectedCost)
+	if r.Now != nil {
+		job.UpdatedAt = r.Now()
+	} else {
+		job.UpdatedAt = time.Now()
+	}
+	if err := r.Store.Put(ctx, job); err != nil {
+		return state, fmt.Errorf("r.Store.Put: id: %s, error: %w", job.ID, err)
+	}
+	if r.OnFinish != nil {
+		r.OnFinish(ctx, job, state)
+	}
+	return state, nil
+}
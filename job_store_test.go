@@ -0,0 +1,167 @@
+package wujiesdk_test
+
+// @Title        job_store_test.go
+// @Description  test file job store and job resumer against fake server
+// @Create       XdpCs 2026-10-20 11:50
+// @Update       XdpCs 2026-10-20 11:50
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/XdpCs/wujiesdk"
+	"github.com/XdpCs/wujiesdk/wujietest"
+)
+
+func openJobStore(t *testing.T, path string) *wujiesdk.FileJobStore {
+	t.Helper()
+	f, err := wujiesdk.OpenFileJobStore(path)
+	if err != nil {
+		t.Fatalf("OpenFileJobStore error: %v", err)
+	}
+	return f
+}
+
+func countLines(t *testing.T, path string) int {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return bytes.Count(data, []byte("\n"))
+}
+
+func TestFileJobStoreReplay(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "jobs.jsonl")
+	f := openJobStore(t, path)
+	for _, job := range []*wujiesdk.StoredJob{
+		{ID: "a", Product: wujiesdk.ImageProduct, Keys: []string{"a"}},
+		{ID: "b", Product: wujiesdk.ProProduct, Keys: []string{"b"}},
+		{ID: "a", Product: wujiesdk.ImageProduct, Keys: []string{"a"}, Status: wujiesdk.SuccessJobStatus},
+	} {
+		if err := f.Put(ctx, job); err != nil {
+			t.Fatalf("Put error: %v", err)
+		}
+	}
+	if err := f.Delete(ctx, "b"); err != nil {
+		t.Fatalf("Delete error: %v", err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	f = openJobStore(t, path)
+	defer f.Close()
+	a, err := f.Get(ctx, "a")
+	if err != nil || a.Status != wujiesdk.SuccessJobStatus {
+		t.Errorf("Get a: %+v, error: %v, want the last put", a, err)
+	}
+	if _, err := f.Get(ctx, "b"); !errors.Is(err, wujiesdk.ErrJobNotFound) {
+		t.Errorf("Get b error: %v, want %v", err, wujiesdk.ErrJobNotFound)
+	}
+	if jobs, _ := f.List(ctx, wujiesdk.JobFilter{Unfinished: true}); len(jobs) != 0 {
+		t.Errorf("unfinished jobs: %+v, want none", jobs)
+	}
+}
+
+func TestFileJobStoreTornLine(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "jobs.jsonl")
+	f := openJobStore(t, path)
+	if err := f.Put(ctx, &wujiesdk.StoredJob{ID: "a", Keys: []string{"a"}}); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	// a crash in the middle of writing leaves a partial record without newline
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := file.WriteString(`{"id":"b","job":{"id":"b","ke`); err != nil {
+		t.Fatal(err)
+	}
+	file.Close()
+
+	f = openJobStore(t, path)
+	if _, err := f.Get(ctx, "b"); !errors.Is(err, wujiesdk.ErrJobNotFound) {
+		t.Errorf("Get torn b error: %v, want %v", err, wujiesdk.ErrJobNotFound)
+	}
+	if err := f.Put(ctx, &wujiesdk.StoredJob{ID: "c", Keys: []string{"c"}}); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	f = openJobStore(t, path)
+	defer f.Close()
+	jobs, _ := f.List(ctx, wujiesdk.JobFilter{})
+	if len(jobs) != 2 || jobs[0].ID != "a" || jobs[1].ID != "c" {
+		t.Errorf("jobs after torn line: %+v, want a and c", jobs)
+	}
+}
+
+func TestFileJobStoreCompact(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "jobs.jsonl")
+	f := openJobStore(t, path)
+	defer f.Close()
+	f.CompactAfter = 1
+	for _, id := range []string{"a", "b"} {
+		if err := f.Put(ctx, &wujiesdk.StoredJob{ID: id}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// a deletion is one stale record, it does not reach CompactAfter alone
+	if err := f.Delete(ctx, "a"); err != nil {
+		t.Fatal(err)
+	}
+	if n := countLines(t, path); n != 3 {
+		t.Fatalf("lines after delete: %d, want 3", n)
+	}
+	if err := f.Put(ctx, &wujiesdk.StoredJob{ID: "b", Status: wujiesdk.SuccessJobStatus}); err != nil {
+		t.Fatal(err)
+	}
+	if n := countLines(t, path); n != 1 {
+		t.Errorf("lines after compaction: %d, want 1", n)
+	}
+}
+
+func TestJobResumer(t *testing.T) {
+	s := wujietest.NewServer(wujietest.WithTiming(0, 0))
+	defer s.Close()
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "jobs.jsonl")
+	store := openJobStore(t, path)
+	c := s.Caller()
+	c.AddCallerHooks(wujiesdk.NewJobStoreHook(c, store))
+	_, data, err := c.CreateImage(ctx, &wujiesdk.CreateImageRequest{Model: 1, Prompt: "a cat", Num: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	store.Close()
+
+	// a restarted process resumes the job from the file
+	store = openJobStore(t, path)
+	defer store.Close()
+	r := wujiesdk.NewJobResumer(s.Caller(), store)
+	r.PollInterval = 10 * time.Millisecond
+	jobs, err := r.Resume(ctx, wujiesdk.JobFilter{Product: wujiesdk.ImageProduct})
+	if err != nil {
+		t.Fatalf("Resume error: %v", err)
+	}
+	if len(jobs) != 1 || jobs[0].ID != data.Keys[0] {
+		t.Fatalf("resumed jobs: %+v, want %v", jobs, data.Keys)
+	}
+	job, err := store.Get(ctx, data.Keys[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if job.Status != wujiesdk.SuccessJobStatus || job.ActualCost.Points == 0 {
+		t.Errorf("resumed job: %+v, want success with actual cost", job)
+	}
+}
@@ -0,0 +1,201 @@
+package wujiesdk
+
+// @Title        ledger.go
+// @Description  ledger of points and duration spent by calls
+// @Create       XdpCs 2026-10-19 14:20
+// @Update       XdpCs 2026-10-19 14:20
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// LedgerEntryKind is the kind of ledger entry
+type LedgerEntryKind string
+
+const (
+	// ExpectedLedgerEntryKind is the cost expected at submission
+	ExpectedLedgerEntryKind LedgerEntryKind = "expected"
+	// ActualLedgerEntryKind is the cost actually charged at completion
+	ActualLedgerEntryKind LedgerEntryKind = "actual"
+)
+
+// Tenant is the customer whom the cost is charged back to
+type Tenant struct {
+	ID             string            `json:"id,omitempty"`
+	Labels         map[string]string `json:"labels,omitempty"`
+	ServiceContext *ServiceContext   `json:"service_context,omitempty"`
+}
+
+type tenantContextKey struct{}
+
+// WithTenant set the tenant of calls made with ctx
+func WithTenant(ctx context.Context, tenant Tenant) context.Context {
+	return context.WithValue(ctx, tenantContextKey{}, tenant)
+}
+
+// TenantFromContext get the tenant set by WithTenant
+func TenantFromContext(ctx context.Context) (Tenant, bool) {
+	if ctx == nil {
+		return Tenant{}, false
+	}
+	tenant, ok := ctx.Value(tenantContextKey{}).(Tenant)
+	return tenant, ok
+}
+
+// LedgerEntry is one record of ledger
+type LedgerEntry struct {
+	Time    time.Time       `json:"time"`
+	Kind    LedgerEntryKind `json:"kind"`
+	Call    string          `json:"call"`
+	Keys    []string        `json:"keys,omitempty"`
+	Cost    Cost            `json:"cost"`
+	Message string          `json:"message,omitempty"`
+	Tenant  Tenant          `json:"tenant"`
+}
+
+// LedgerFilter selects ledger entries, zero field matches all
+type LedgerFilter struct {
+	TenantID string
+	Kind     LedgerEntryKind
+	Since    time.Time
+	Until    time.Time
+}
+
+// Match report whether entry is selected by filter
+func (f *LedgerFilter) Match(entry *LedgerEntry) bool {
+	if f.TenantID != "" && entry.Tenant.ID != f.TenantID {
+		return false
+	}
+	if f.Kind != "" && entry.Kind != f.Kind {
+		return false
+	}
+	if !f.Since.IsZero() && entry.Time.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && !entry.Time.Before(f.Until) {
+		return false
+	}
+	return true
+}
+
+// Ledger records the cost of calls
+type Ledger interface {
+	Record(ctx context.Context, entry *LedgerEntry) error
+	Entries(ctx context.Context, filter LedgerFilter) ([]LedgerEntry, error)
+}
+
+// SumLedgerEntries get total cost of entries by tenant id,
+// entries should be selected by one Kind, otherwise a job is counted twice
+func SumLedgerEntries(entries []LedgerEntry) map[string]Cost {
+	sums := make(map[string]Cost)
+	for _, entry := range entries {
+		sums[entry.Tenant.ID] = sums[entry.Tenant.ID].Add(entry.Cost)
+	}
+	return sums
+}
+
+// MemoryLedger keeps entries in memory, it is safe for concurrent use
+type MemoryLedger struct {
+	mu      sync.RWMutex
+	entries []LedgerEntry
+}
+
+// NewMemoryLedger new memory ledger
+func NewMemoryLedger() *MemoryLedger {
+	return &MemoryLedger{}
+}
+
+// Record append entry
+func (m *MemoryLedger) Record(_ context.Context, entry *LedgerEntry) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.entries = append(m.entries, *entry)
+	return nil
+}
+
+// Entries get entries selected by filter in the order they are recorded
+func (m *MemoryLedger) Entries(_ context.Context, filter LedgerFilter) ([]LedgerEntry, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var entries []LedgerEntry
+	for i := range m.entries {
+		if filter.Match(&m.entries[i]) {
+			entries = append(entries, m.entries[i])
+		}
+	}
+	return entries, nil
+}
+
+// FileLedger appends entries to a file as JSON lines, it is safe for concurrent use
+type FileLedger struct {
+	mu   sync.Mutex
+	path string
+	file *os.File
+}
+
+// OpenFileLedger open or create the ledger file at path
+func OpenFileLedger(path string) (*FileLedger, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("os.OpenFile: path: %s, error: %w", path, err)
+	}
+	return &FileLedger{path: path, file: file}, nil
+}
+
+// Record append entry as one JSON line
+func (f *FileLedger) Record(_ context.Context, entry *LedgerEntry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("json.Marshal: %w", err)
+	}
+	line = append(line, '\n')
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, err := f.file.Write(line); err != nil {
+		return fmt.Errorf("f.file.Write: path: %s, error: %w", f.path, err)
+	}
+	return nil
+}
+
+// Entries read entries selected by filter from the file
+func (f *FileLedger) Entries(_ context.Context, filter LedgerFilter) ([]LedgerEntry, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	file, err := os.Open(f.path)
+	if err != nil {
+		return nil, fmt.Errorf("os.Open: path: %s, error: %w", f.path, err)
+	}
+	defer file.Close()
+	var entries []LedgerEntry
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var entry LedgerEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("json.Unmarshal: path: %s, line: %d, error: %w", f.path, line, err)
+		}
+		if filter.Match(&entry) {
+			entries = append(entries, entry)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("scanner.Err: path: %s, error: %w", f.path, err)
+	}
+	return entries, nil
+}
+
+// Close close the file
+func (f *FileLedger) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.file.Close()
+}
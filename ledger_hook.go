@@ -0,0 +1,279 @@
+package wujiesdk
+
+// @Title        ledger_hook.go
+// @Description  feed ledger with expected and actual cost of calls
+// @Create       XdpCs 2026-10-19 14:20
+// @Update       XdpCs 2026-10-20 13:30
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/patrickmn/go-cache"
+)
+
+// LedgerHook is a CallerHook which records expected cost when create call succeeds,
+// and actual cost when GeneratingInfo, ImageInfo, GetSuperSize, GeneratingInfoPro, CameraGeneratingInfo
+// or VideoInfo returns a finished job, duration cost of pro and camera is recorded as ProDuration,
+// pro cost is read by ImageInfoPro, camera and video report no charged cost,
+// so their expected cost is recorded when they succeed and zero when they fail
+type LedgerHook struct {
+	Caller *Caller
+	Ledger Ledger
+	// Pricer prices create calls whose result has no expected cost, it is skipped if nil
+	Pricer Pricer
+	Now    func() time.Time
+	// jobs remembers tenant of submitted keys and keys whose actual cost is recorded
+	jobs *cache.Cache
+	once sync.Once
+}
+
+// NewLedgerHook new ledger hook, create calls without expected cost in result are priced by CallerPricer with table
+func NewLedgerHook(c *Caller, ledger Ledger, table PriceTable) *LedgerHook {
+	return &LedgerHook{
+		Caller: c,
+		Ledger: ledger,
+		Pricer: NewCallerPricer(c, table),
+		Now:    time.Now,
+	}
+}
+
+// BeforeCall do nothing
+func (l *LedgerHook) BeforeCall(_ context.Context, _ *Call) error {
+	return nil
+}
+
+// AfterCall record cost of the call
+func (l *LedgerHook) AfterCall(ctx context.Context, call *Call, code WujieCode, result interface{}, err error) {
+	if err != nil || code != OKWujieCode {
+		return
+	}
+	if isCreateCall(call) {
+		l.recordExpected(ctx, call, result)
+		return
+	}
+	switch r := result.(type) {
+	case []ImageGeneratingInfo:
+		for _, info := range r {
+			if info.CompleteTime > 0 || info.FailMessage.Failed() || JobStatus(info.Status).Terminal() {
+				l.recordActual(ctx, call.Name, info.Key, Cost{Points: info.IntegralCost}, info.IntegralCostMessage)
+			}
+		}
+	case *ImageInfoData:
+		key, _ := call.Request.(string)
+		if key != "" && (r.CompleteTime > 0 || r.FailMessage.Failed()) {
+			l.recordActual(ctx, call.Name, key, Cost{Points: r.IntegralCost}, r.IntegralCostMessage)
+		}
+	case []SuperSizeInfo:
+		for _, info := range r {
+			if JobStatus(info.Status).Terminal() {
+				l.recordActual(ctx, call.Name, info.Key, Cost{Points: info.Integral, ProDuration: info.Duration}, "")
+			}
+		}
+	case []GeneratingInfoPro:
+		for _, info := range r {
+			if JobStatus(info.Status).Terminal() {
+				l.recordPro(ctx, call.Name, info.Key)
+			}
+		}
+	case []CameraGeneratingInfo:
+		for _, info := range r {
+			if JobStatus(info.Status).Terminal() {
+				l.recordActual(ctx, call.Name, info.Key, l.expected(info.Key, JobStatus(info.Status)), "")
+			}
+		}
+	case *VideoInfo:
+		if JobStatus(r.Status).Terminal() {
+			l.recordActual(ctx, call.Name, r.Key, l.expected(r.Key, JobStatus(r.Status)), "")
+		}
+	}
+}
+
+// RecordCallBackSuccess record actual cost from success callback of notify_url
+func (l *LedgerHook) RecordCallBackSuccess(ctx context.Context, resp *CreateImageCallBackSuccessResp) {
+	l.recordActual(ctx, "CallBack", resp.Key, Cost{Points: resp.IntegralCost}, resp.IntegralCostMessage)
+}
+
+// RecordCallBackFailed record actual cost from failed callback of notify_url
+func (l *LedgerHook) RecordCallBackFailed(ctx context.Context, resp *CreateImageCallBackFailedResp) {
+	l.recordActual(ctx, "CallBack", resp.Key, Cost{Points: resp.IntegralCost}, resp.IntegralCostMessage)
+}
+
+func (l *LedgerHook) recordExpected(ctx context.Context, call *Call, result interface{}) {
+	keys := createdKeys(result)
+	cost, ok := expectedCost(result)
+	if !ok && l.Pricer != nil {
+		var err error
+		// calls which can not be priced are recorded with zero cost without warning
+		if cost, err = l.Pricer.Price(ctx, call); err != nil && !errors.Is(err, ErrUnpricedCall) {
+			l.logf("LedgerHook: call: %v, price error: %v\n", call, err)
+		}
+	}
+	tenant := requestTenant(ctx, call.Request)
+	for i, key := range keys {
+		l.jobCache().Set("tenant:"+key, tenant, cache.DefaultExpiration)
+		l.jobCache().Set("expected:"+key, shareCost(cost, len(keys), i), cache.DefaultExpiration)
+	}
+	l.record(ctx, &LedgerEntry{Kind: ExpectedLedgerEntryKind, Call: call.Name, Keys: keys, Cost: cost, Tenant: tenant})
+}
+
+// recordPro record duration cost of finished pro job read by ImageInfoPro
+func (l *LedgerHook) recordPro(ctx context.Context, name, key string) {
+	if _, found := l.jobCache().Get("actual:" + key); found || l.Caller == nil {
+		return
+	}
+	_, info, err := l.Caller.ImageInfoPro(ctx, key)
+	if err != nil {
+		l.logf("LedgerHook: call: %s, key: %s, get image info pro error: %v\n", name, key, err)
+		return
+	}
+	l.recordActual(ctx, name, key, Cost{ProDuration: info.CostInfo.DurationCost}, "")
+}
+
+// expected get expected cost of key remembered at submission for jobs which report no charged cost
+func (l *LedgerHook) expected(key string, status JobStatus) Cost {
+	if status != SuccessJobStatus {
+		return Cost{}
+	}
+	if cost, found := l.jobCache().Get("expected:" + key); found {
+		return cost.(Cost)
+	}
+	return Cost{}
+}
+
+func (l *LedgerHook) recordActual(ctx context.Context, name, key string, cost Cost, message string) {
+	if key == "" {
+		return
+	}
+	// record actual cost of one key once, jobs are polled many times after completion
+	if err := l.jobCache().Add("actual:"+key, true, cache.DefaultExpiration); err != nil {
+		return
+	}
+	tenant, ok := TenantFromContext(ctx)
+	if t, found := l.jobCache().Get("tenant:" + key); found {
+		tenant, ok = t.(Tenant), true
+	}
+	if !ok {
+		tenant = Tenant{}
+	}
+	l.record(ctx, &LedgerEntry{Kind: ActualLedgerEntryKind, Call: name, Keys: []string{key}, Cost: cost, Message: message, Tenant: tenant})
+}
+
+func (l *LedgerHook) record(ctx context.Context, entry *LedgerEntry) {
+	if l.Now != nil {
+		entry.Time = l.Now()
+	} else {
+		entry.Time = time.Now()
+	}
+	if err := l.Ledger.Record(ctx, entry); err != nil {
+		l.logf("LedgerHook: call: %s, keys: %v, record error: %v\n", entry.Call, entry.Keys, err)
+	}
+}
+
+func (l *LedgerHook) logf(format string, a ...interface{}) {
+	if l.Caller != nil && l.Caller.Client != nil {
+		l.Caller.Client.WriteLog(LogWarn, format, a...)
+	}
+}
+
+// requestTenant get tenant from ctx, ServiceContext of CreateImageRequest fills the missing parts,
+// other create requests have no service context, their tenant is only carried by WithTenant
+func requestTenant(ctx context.Context, req interface{}) Tenant {
+	tenant, _ := TenantFromContext(ctx)
+	var sc *ServiceContext
+	if r, ok := req.(*CreateImageRequest); ok {
+		sc = r.ServiceContext
+	}
+	if sc != nil {
+		if tenant.ServiceContext == nil {
+			tenant.ServiceContext = sc
+		}
+		if tenant.ID == "" {
+			tenant.ID = sc.AppCode
+		}
+	}
+	return tenant
+}
+
+// shareCost get the share of key i of n keys in cost, the first key takes the remainder
+func shareCost(cost Cost, n, i int) Cost {
+	share := Cost{Points: cost.Points / n, ProDuration: cost.ProDuration / n}
+	if i == 0 {
+		share = share.Add(Cost{Points: cost.Points % n, ProDuration: cost.ProDuration % n})
+	}
+	return share
+}
+
+// createdKeys get keys of jobs submitted by create call
+func createdKeys(result interface{}) []string {
+	switch r := result.(type) {
+	case *CreateImageData:
+		return r.Keys
+	case *CreateMidjourneyResponse:
+		return r.Data.Keys
+	case *CreateFluxResponse:
+		return r.Data.Keys
+	case *CreateAvatarArtworkData:
+		return r.Keys
+	case *CreateCameraResult:
+		return r.Keys
+	case *YouthifyData:
+		return resultKeys(r.Results)
+	case []CreateImageProResult:
+		keys := make([]string, 0, len(r))
+		for _, result := range r {
+			keys = append(keys, result.Key)
+		}
+		return keys
+	case *CreateAvatarData:
+		return []string{r.Key}
+	case *CreateSegmentationResult:
+		return []string{r.Key}
+	case *CreateInfiniteZoomResult:
+		return []string{r.Key}
+	case *CreateVectorStudioResult:
+		return []string{r.Key}
+	case string:
+		if r != "" {
+			return []string{r}
+		}
+	}
+	return nil
+}
+
+func resultKeys(results []CreateImageResult) []string {
+	keys := make([]string, 0, len(results))
+	for _, result := range results {
+		keys = append(keys, result.Key)
+	}
+	return keys
+}
+
+// expectedCost get expected cost returned by create call
+func expectedCost(result interface{}) (Cost, bool) {
+	switch r := result.(type) {
+	case *CreateImageData:
+		return Cost{Points: r.ExpectedIntegralCost}, true
+	case *CreateMidjourneyResponse:
+		return Cost{Points: r.Data.ExpectedIntegralCost}, true
+	case *CreateFluxResponse:
+		return Cost{Points: r.Data.ExpectedIntegralCost}, true
+	case *YouthifyData:
+		return Cost{Points: r.ExpectedIntegralCost}, true
+	case *CreateAvatarArtworkData:
+		return Cost{Points: r.ExpectedIntegralCost}, true
+	case *CreateCameraResult:
+		return Cost{ProDuration: r.ExpectedDurationCost}, true
+	}
+	return Cost{}, false
+}
+
+// jobCache get cache of submitted jobs, it is created on first use so that the zero value is usable
+func (l *LedgerHook) jobCache() *cache.Cache {
+	l.once.Do(func() {
+		l.jobs = cache.New(7*24*time.Hour, time.Hour)
+	})
+	return l.jobs
+}
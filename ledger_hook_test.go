@@ -0,0 +1,67 @@
+package wujiesdk_test
+
+// @Title        ledger_hook_test.go
+// @Description  test ledger hook against fake server
+// @Create       XdpCs 2026-10-20 12:50
+// @Update       XdpCs 2026-10-20 13:30
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/XdpCs/wujiesdk"
+	"github.com/XdpCs/wujiesdk/wujietest"
+)
+
+func TestLedgerHookTenant(t *testing.T) {
+	s := wujietest.NewServer(wujietest.WithTiming(0, 0))
+	defer s.Close()
+	c := s.Caller()
+	ledger := wujiesdk.NewMemoryLedger()
+	c.AddCallerHooks(wujiesdk.NewLedgerHook(c, ledger, wujiesdk.PriceTable{}))
+	ctx := context.Background()
+
+	// pro requests have no service context, the tenant comes from ctx
+	_, results, err := c.CreateImagePro(wujiesdk.WithTenant(ctx, wujiesdk.Tenant{ID: "tenant-a"}),
+		wujiesdk.NewCreateImageProRequest(1, "a cat", 512, 512))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.WaitJob(ctx, wujiesdk.ProProduct, []string{results[0].Key}, 10*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	entries, _ := ledger.Entries(ctx, wujiesdk.LedgerFilter{TenantID: "tenant-a", Kind: wujiesdk.ActualLedgerEntryKind})
+	if len(entries) != 1 || entries[0].Cost.ProDuration == 0 {
+		t.Errorf("actual entries of tenant-a: %+v, want the pro duration of the job", entries)
+	}
+
+	// service context of CreateImageRequest fills the tenant without WithTenant
+	req := &wujiesdk.CreateImageRequest{Model: 1, Prompt: "a cat", Num: 1, ServiceContext: &wujiesdk.ServiceContext{AppCode: "tenant-b"}}
+	if _, _, err := c.CreateImage(ctx, req); err != nil {
+		t.Fatal(err)
+	}
+	entries, _ = ledger.Entries(ctx, wujiesdk.LedgerFilter{TenantID: "tenant-b", Kind: wujiesdk.ExpectedLedgerEntryKind})
+	if len(entries) != 1 {
+		t.Errorf("expected entries of tenant-b: %+v, want one", entries)
+	}
+}
+
+func TestLedgerHookZeroValue(t *testing.T) {
+	s := wujietest.NewServer(wujietest.WithTiming(0, 0))
+	defer s.Close()
+	c := s.Caller()
+	ledger := wujiesdk.NewMemoryLedger()
+	c.AddCallerHooks(&wujiesdk.LedgerHook{Caller: c, Ledger: ledger})
+	ctx := context.Background()
+	_, data, err := c.CreateImage(ctx, &wujiesdk.CreateImageRequest{Model: 1, Prompt: "a cat", Num: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.WaitJob(ctx, wujiesdk.ImageProduct, data.Keys, 10*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	if entries, _ := ledger.Entries(ctx, wujiesdk.LedgerFilter{Kind: wujiesdk.ActualLedgerEntryKind}); len(entries) != 1 {
+		t.Errorf("actual entries: %+v, want one", entries)
+	}
+}
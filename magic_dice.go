@@ -0,0 +1,155 @@
+package wujiesdk
+
+// @Title        magic_dice.go
+// @Description  convert magic dice result into create request and roll a surprise image
+// @Create       XdpCs 2026-10-19 21:20
+// @Update       XdpCs 2026-10-19 21:20
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+// CreateImageRequest convert magic dice result into CreateImageRequest, prompt of language is used,
+// and the prompt of the other language is used when it is empty
+func (c *CreateMagicDiceResult) CreateImageRequest(language CreateMagicDiceLanguage) *CreateImageRequest {
+	prompt, other := c.PromptChinese, c.PromptEnglish
+	if language == EnglishCreateMagicDiceLanguage {
+		prompt, other = other, prompt
+	}
+	if prompt == "" {
+		prompt = other
+	}
+	return &CreateImageRequest{
+		Model:        c.ModelCode,
+		Prompt:       prompt,
+		Num:          1,
+		Cfg:          c.Cfg,
+		ImageType:    append([]string(nil), c.ImageType...),
+		Style:        append([]string(nil), c.Style...),
+		Artists:      append([]string(nil), c.Artists...),
+		ElementMagic: append([]string(nil), c.ElementMagic...),
+		Character:    append([]string(nil), c.Character...),
+		ModelFusion:  append([]ModelFusion(nil), c.ModelFusion...),
+	}
+}
+
+// SurpriseOption is option of SurpriseMe
+type SurpriseOption func(*surpriseConfig)
+
+type surpriseConfig struct {
+	dice     CreateMagicDiceRequest
+	themeSet bool
+	width    int
+	height   int
+	num      int
+	override func(*CreateImageRequest)
+	rand     *rand.Rand
+}
+
+// WithSurpriseTheme use theme instead of a random one
+func WithSurpriseTheme(themeID int) SurpriseOption {
+	return func(s *surpriseConfig) {
+		s.dice.ThemeId, s.themeSet = themeID, true
+	}
+}
+
+// WithSurpriseKeyword set keyword of magic dice
+func WithSurpriseKeyword(keyword string) SurpriseOption {
+	return func(s *surpriseConfig) {
+		s.dice.Keyword = keyword
+	}
+}
+
+// WithSurpriseLanguage set language of magic dice and prompt, it is ChineseCreateMagicDiceLanguage by default
+func WithSurpriseLanguage(language CreateMagicDiceLanguage) SurpriseOption {
+	return func(s *surpriseConfig) {
+		s.dice.Language = language
+	}
+}
+
+// WithSurpriseDice set type and model of magic dice,
+// they are MagicDicCreateMagicDiceType and StableDiffusionCreateMagicDiceModel by default
+func WithSurpriseDice(diceType CreateMagicDiceType, model CreateMagicDiceModel) SurpriseOption {
+	return func(s *surpriseConfig) {
+		s.dice.Type, s.dice.Model = diceType, model
+	}
+}
+
+// WithSurpriseSize set width and height of images
+func WithSurpriseSize(width, height int) SurpriseOption {
+	return func(s *surpriseConfig) {
+		s.width, s.height = width, height
+	}
+}
+
+// WithSurpriseNum set number of images
+func WithSurpriseNum(num int) SurpriseOption {
+	return func(s *surpriseConfig) {
+		s.num = num
+	}
+}
+
+// WithSurpriseOverride change the converted request before it is submitted
+func WithSurpriseOverride(override func(*CreateImageRequest)) SurpriseOption {
+	return func(s *surpriseConfig) {
+		s.override = override
+	}
+}
+
+// WithSurpriseRand set source of randomness for picking theme
+func WithSurpriseRand(r *rand.Rand) SurpriseOption {
+	return func(s *surpriseConfig) {
+		s.rand = r
+	}
+}
+
+// SurpriseMe pick a random MagicDiceTheme, roll magic dice, convert the result and submit it,
+// it returns the handle of images and the result of magic dice
+func (c *Caller) SurpriseMe(ctx context.Context, options ...SurpriseOption) (*ImageJob, *CreateMagicDiceResult, error) {
+	s := &surpriseConfig{
+		dice: CreateMagicDiceRequest{
+			Type:     MagicDicCreateMagicDiceType,
+			Model:    StableDiffusionCreateMagicDiceModel,
+			Language: ChineseCreateMagicDiceLanguage,
+		},
+	}
+	for _, option := range options {
+		option(s)
+	}
+	if !s.themeSet {
+		_, themes, err := c.MagicDiceTheme(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("c.MagicDiceTheme: %w", err)
+		}
+		if len(themes) == 0 {
+			return nil, nil, fmt.Errorf("c.MagicDiceTheme: error: no theme")
+		}
+		r := s.rand
+		if r == nil {
+			r = rand.New(rand.NewSource(time.Now().UnixNano()))
+		}
+		s.dice.ThemeId = themes[r.Intn(len(themes))].ThemeID
+	}
+	_, dice, err := c.CreateMagicDice(ctx, &s.dice)
+	if err != nil {
+		return nil, nil, fmt.Errorf("c.CreateMagicDice: %w", err)
+	}
+	cReq := dice.CreateImageRequest(s.dice.Language)
+	if s.width > 0 && s.height > 0 {
+		cReq.Width, cReq.Height = s.width, s.height
+	}
+	if s.num > 0 {
+		cReq.Num = s.num
+	}
+	if s.override != nil {
+		s.override(cReq)
+	}
+	job, err := c.SubmitImage(ctx, cReq)
+	if err != nil {
+		return nil, dice, fmt.Errorf("c.SubmitImage: %w", err)
+	}
+	return job, dice, nil
+}
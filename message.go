@@ -0,0 +1,210 @@
+package wujiesdk
+
+// @Title        message.go
+// @Description  WujieCode's message catalog
+// @Create       XdpCs 2026-10-19 10:12
+// @Update       XdpCs 2026-10-20 13:15
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// Language is the language of WujieCode's message
+type Language string
+
+const (
+	ChineseLanguage Language = "zh-CN"
+	EnglishLanguage Language = "en-US"
+)
+
+// DefaultLanguage is used when neither Client nor context selects a language
+const DefaultLanguage = ChineseLanguage
+
+type languageContextKey struct{}
+
+// WithLanguage select the language of WujieCode's message for one call
+func WithLanguage(ctx context.Context, lang Language) context.Context {
+	return context.WithValue(ctx, languageContextKey{}, lang)
+}
+
+// LanguageFromContext get the language selected by WithLanguage
+func LanguageFromContext(ctx context.Context) (Language, bool) {
+	if ctx == nil {
+		return "", false
+	}
+	lang, ok := ctx.Value(languageContextKey{}).(Language)
+	return lang, ok && lang != ""
+}
+
+// MessageCatalog holds WujieCode's messages in every language, it is safe for concurrent use
+type MessageCatalog struct {
+	mu       sync.RWMutex
+	messages map[Language]map[WujieCode]string
+	unknown  map[Language]string
+}
+
+// NewMessageCatalog new message catalog with built-in chinese and english messages
+func NewMessageCatalog() *MessageCatalog {
+	m := &MessageCatalog{
+		messages: make(map[Language]map[WujieCode]string),
+		unknown: map[Language]string{
+			ChineseLanguage: "code: %v 未知错误",
+			EnglishLanguage: "code: %v unknown error",
+		},
+	}
+	for lang, messages := range builtinWujieCodeMessages {
+		for code, message := range messages {
+			m.Register(lang, code, message)
+		}
+	}
+	return m
+}
+
+// Register add or replace the message of code in lang
+func (m *MessageCatalog) Register(lang Language, code WujieCode, message string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.messages[lang] == nil {
+		m.messages[lang] = make(map[WujieCode]string)
+	}
+	m.messages[lang][code] = message
+}
+
+// Load add or replace messages from r in json as {"zh-CN": {"20010001": "非法参数"}, "en-US": {...}},
+// codes without constants in const.go, such as codes of newer apis, are added this way,
+// before that Caller uses the message of the response for them
+func (m *MessageCatalog) Load(r io.Reader) error {
+	var messages map[Language]map[WujieCode]string
+	if err := json.NewDecoder(r).Decode(&messages); err != nil {
+		return fmt.Errorf("json.NewDecoder: %w", err)
+	}
+	for lang, codes := range messages {
+		for code, message := range codes {
+			m.Register(lang, code, message)
+		}
+	}
+	return nil
+}
+
+// RegisterUnknown set the format of unknown code's message in lang, format gets code as the only argument
+func (m *MessageCatalog) RegisterUnknown(lang Language, format string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.unknown[lang] = format
+}
+
+// Lookup get the message of code in lang without any fallback
+func (m *MessageCatalog) Lookup(lang Language, code WujieCode) (string, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	message, ok := m.messages[lang][code]
+	return message, ok
+}
+
+// Has report whether code has a message in any language
+func (m *MessageCatalog) Has(code WujieCode) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, messages := range m.messages {
+		if _, ok := messages[code]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Message get the message of code in lang,
+// it falls back to DefaultLanguage when lang has no such message, then to the unknown format of lang
+func (m *MessageCatalog) Message(lang Language, code WujieCode) string {
+	if lang == "" {
+		lang = DefaultLanguage
+	}
+	if message, ok := m.Lookup(lang, code); ok {
+		return message
+	}
+	if message, ok := m.Lookup(DefaultLanguage, code); ok {
+		return message
+	}
+	m.mu.RLock()
+	format, ok := m.unknown[lang]
+	if !ok {
+		format = m.unknown[DefaultLanguage]
+	}
+	m.mu.RUnlock()
+	return fmt.Sprintf(format, string(code))
+}
+
+// Languages get all languages which have at least one message
+func (m *MessageCatalog) Languages() []Language {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	languages := make([]Language, 0, len(m.messages))
+	for lang := range m.messages {
+		languages = append(languages, lang)
+	}
+	return languages
+}
+
+// DefaultMessageCatalog is used by WujieCode.String and by Client without its own catalog
+var DefaultMessageCatalog = NewMessageCatalog()
+
+// RegisterWujieCodeMessage add or replace the message of code in lang of DefaultMessageCatalog
+func RegisterWujieCodeMessage(lang Language, code WujieCode, message string) {
+	DefaultMessageCatalog.Register(lang, code, message)
+}
+
+// WujieCodeError is the error of a WujieCode which is not OKWujieCode
+type WujieCodeError struct {
+	Code     WujieCode
+	Language Language
+	Message  string
+}
+
+func (e *WujieCodeError) Error() string {
+	return e.Message
+}
+
+var builtinWujieCodeMessages = map[Language]map[WujieCode]string{
+	ChineseLanguage: {
+		ErrorWujieCode:                               "非无界报错返回",
+		OKWujieCode:                                  "请求成功",
+		InvalidParameterWujieCode:                    "非法参数",
+		UnsupportedResolutionWujieCode:               "暂时无法支持的尺寸/分辨率",
+		LockRaceConditionWujieCode:                   "由锁竞争导致的作画失败（需要重新发起）",
+		PromptTranslationFailedWujieCode:             "文本语言翻译失败",
+		PromptContainsSensitiveWordsWujieCode:        "画面描述含有敏感词",
+		InitImageLinkIncorrectOrUnsupportedWujieCode: "底图链接信息有误或不支持",
+		InitImageContainsSensitiveInfoWujieCode:      "参考图含有敏感信息",
+		ImageStatusChange:                            "作品状态改变，请刷新后查看",
+		InsufficientPointsBalanceWujieCode:           "积分余额不足",
+		JobNotInQueueAndCannotCancelWujieCode:        "该作品不在排队中，无法撤销",
+		CheckResourcesWujieCode:                      "检测资源",
+		ImageRecognitionAbnormalityWujieCode:         "图片识别异常",
+		NoFaceOrFaceIsSmallWujieCode:                 "未检测到人脸或人脸太小",
+		MultipleFacesDetectedWujieCode:               "检测到多张人脸",
+		SideFaceDetectedWujieCode:                    "检测到侧脸",
+	},
+	EnglishLanguage: {
+		ErrorWujieCode:                               "error not returned by wujie",
+		OKWujieCode:                                  "success",
+		InvalidParameterWujieCode:                    "invalid parameter",
+		UnsupportedResolutionWujieCode:               "unsupported size or resolution",
+		LockRaceConditionWujieCode:                   "creation failed because of lock contention, please submit again",
+		PromptTranslationFailedWujieCode:             "failed to translate the prompt",
+		PromptContainsSensitiveWordsWujieCode:        "prompt contains sensitive words",
+		InitImageLinkIncorrectOrUnsupportedWujieCode: "init image link is incorrect or unsupported",
+		InitImageContainsSensitiveInfoWujieCode:      "reference image contains sensitive information",
+		ImageStatusChange:                            "artwork status changed, please refresh and check again",
+		InsufficientPointsBalanceWujieCode:           "insufficient points balance",
+		JobNotInQueueAndCannotCancelWujieCode:        "artwork is not in the queue and cannot be canceled",
+		CheckResourcesWujieCode:                      "checking resources",
+		ImageRecognitionAbnormalityWujieCode:         "image recognition error",
+		NoFaceOrFaceIsSmallWujieCode:                 "no face detected or the face is too small",
+		MultipleFacesDetectedWujieCode:               "multiple faces detected",
+		SideFaceDetectedWujieCode:                    "side face detected",
+	},
+}
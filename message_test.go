@@ -0,0 +1,45 @@
+package wujiesdk_test
+
+// @Title        message_test.go
+// @Description  test WujieCode's message catalog against fake server
+// @Create       XdpCs 2026-10-20 13:15
+// @Update       XdpCs 2026-10-20 13:15
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/XdpCs/wujiesdk"
+	"github.com/XdpCs/wujiesdk/wujietest"
+)
+
+func TestWujieCodeMessages(t *testing.T) {
+	s := wujietest.NewServer(wujietest.WithTiming(0, 0))
+	defer s.Close()
+	c := s.Caller()
+	c.Client.Messages = wujiesdk.NewMessageCatalog()
+	ctx := wujiesdk.WithLanguage(context.Background(), wujiesdk.EnglishLanguage)
+
+	s.Inject(wujiesdk.AvailableIntegralBalanceWujieRouter, wujietest.Fault{Code: wujiesdk.InsufficientPointsBalanceWujieCode, Message: "余额不足", Times: 1})
+	var codeErr *wujiesdk.WujieCodeError
+	if _, _, err := c.AvailableIntegralBalance(ctx); !errors.As(err, &codeErr) || codeErr.Message != "insufficient points balance" {
+		t.Errorf("AvailableIntegralBalance error: %v, want the english message of catalog", err)
+	}
+
+	// codes without message in the catalog use the message of the response
+	s.Inject(wujiesdk.AvailableIntegralBalanceWujieRouter, wujietest.Fault{Code: "20199999", Message: "account is frozen", Times: 1})
+	if _, _, err := c.AvailableIntegralBalance(ctx); !errors.As(err, &codeErr) || codeErr.Message != "account is frozen" {
+		t.Errorf("AvailableIntegralBalance error: %v, want the message of the response", err)
+	}
+
+	err := c.Client.Messages.Load(strings.NewReader(`{"en-US": {"20199999": "account frozen"}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Inject(wujiesdk.AvailableIntegralBalanceWujieRouter, wujietest.Fault{Code: "20199999", Message: "account is frozen", Times: 1})
+	if _, _, err := c.AvailableIntegralBalance(ctx); !errors.As(err, &codeErr) || codeErr.Message != "account frozen" {
+		t.Errorf("AvailableIntegralBalance error: %v, want the loaded message", err)
+	}
+}
@@ -0,0 +1,457 @@
+package wujiesdk
+
+// @Title        moderation.go
+// @Description  unified moderation of image, video and lab results
+// @Create       XdpCs 2026-10-19 19:30
+// @Update       XdpCs 2026-10-19 19:30
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"strings"
+)
+
+// ModerationSuggestion is suggestion of content scanning
+type ModerationSuggestion string
+
+const (
+	PassModerationSuggestion   ModerationSuggestion = "pass"
+	ReviewModerationSuggestion ModerationSuggestion = "review"
+	BlockModerationSuggestion  ModerationSuggestion = "block"
+)
+
+// ModerationSource is where moderation is decoded from
+type ModerationSource string
+
+const (
+	AuditInfoModerationSource     ModerationSource = "audit_info"
+	CallBackModerationSource      ModerationSource = "callback"
+	ViolationInfoModerationSource ModerationSource = "violation_info"
+	LabModerationSource           ModerationSource = "lab"
+)
+
+// ModerationScene is one scanned scene, Url is the screenshot of video scenes
+type ModerationScene struct {
+	Scene      string               `json:"scene"`
+	Label      string               `json:"label"`
+	LabelDesc  string               `json:"label_desc"`
+	Suggestion ModerationSuggestion `json:"suggestion"`
+	Rate       float64              `json:"rate"`
+	SubLabels  []SubLabel           `json:"sub_labels,omitempty"`
+	Url        string               `json:"url,omitempty"`
+}
+
+// Moderation is moderation of a result of any source
+type Moderation struct {
+	Source        ModerationSource     `json:"source"`
+	Key           string               `json:"key"`
+	Url           string               `json:"url"`
+	Checked       bool                 `json:"checked"`    // content is scanned
+	CheckFail     bool                 `json:"check_fail"` // scanning is failed
+	Hit           bool                 `json:"hit"`
+	InvolveYellow bool                 `json:"involve_yellow"`
+	Violation     bool                 `json:"violation"`
+	Suggestion    ModerationSuggestion `json:"suggestion"`
+	Scenes        []ModerationScene    `json:"scenes"`
+}
+
+// IsBlocked report whether result is flagged as violation by any source
+func (m *Moderation) IsBlocked() bool {
+	if m.Violation || m.InvolveYellow || m.Hit || m.Suggestion == BlockModerationSuggestion {
+		return true
+	}
+	for _, scene := range m.Scenes {
+		if scene.Suggestion == BlockModerationSuggestion {
+			return true
+		}
+	}
+	return false
+}
+
+// NeedsReview report whether result is not blocked but suggested to review
+func (m *Moderation) NeedsReview() bool {
+	if m.IsBlocked() {
+		return false
+	}
+	if m.Suggestion == ReviewModerationSuggestion {
+		return true
+	}
+	for _, scene := range m.Scenes {
+		if scene.Suggestion == ReviewModerationSuggestion {
+			return true
+		}
+	}
+	return false
+}
+
+// Labels get distinct labels and sub labels of scenes which are not passed
+func (m *Moderation) Labels() []string {
+	var labels []string
+	seen := make(map[string]struct{})
+	add := func(label string) {
+		if label == "" || label == "normal" {
+			return
+		}
+		if _, ok := seen[label]; !ok {
+			seen[label] = struct{}{}
+			labels = append(labels, label)
+		}
+	}
+	for _, scene := range m.Scenes {
+		if scene.Suggestion == PassModerationSuggestion {
+			continue
+		}
+		add(scene.Label)
+		for _, sub := range scene.SubLabels {
+			add(sub.SubLabel)
+		}
+	}
+	return labels
+}
+
+// MaxRate get max rate of scenes and sub labels which are not passed
+func (m *Moderation) MaxRate() float64 {
+	var rate float64
+	for _, scene := range m.Scenes {
+		if scene.Suggestion == PassModerationSuggestion {
+			continue
+		}
+		if scene.Rate > rate {
+			rate = scene.Rate
+		}
+		for _, sub := range scene.SubLabels {
+			if sub.Rate > rate {
+				rate = sub.Rate
+			}
+		}
+	}
+	return rate
+}
+
+func (m *Moderation) String() string {
+	return fmt.Sprintf("%+v", *m)
+}
+
+// rawScanScene accepts both snake case and camel case keys of audit info string
+type rawScanScene struct {
+	ScanSceneDTO
+	LabelDescCamel string     `json:"labelDesc"`
+	SubLabelsCamel []SubLabel `json:"subLabels"`
+}
+
+type rawAuditInfo struct {
+	AuditInfo
+	CheckFailCamel       bool           `json:"checkFail"`
+	TotalSuggestionCamel string         `json:"totalSuggestion"`
+	ScanSceneDTOS        []rawScanScene `json:"scan_scene_d_t_o_s"`
+	ScanSceneDTOSCamel   []rawScanScene `json:"scanSceneDTOS"`
+}
+
+// ParseAuditInfo decode audit info string of ImageGeneratingInfo, ImageInfoData and GeneratingInfoPro,
+// it is JSON of AuditInfo, or a bare suggestion, empty string means not scanned
+func ParseAuditInfo(auditInfo string) (*Moderation, error) {
+	auditInfo = strings.TrimSpace(auditInfo)
+	m := &Moderation{Source: AuditInfoModerationSource}
+	if auditInfo == "" || auditInfo == "null" || auditInfo == "{}" {
+		return m, nil
+	}
+	if !strings.HasPrefix(auditInfo, "{") {
+		switch s := ModerationSuggestion(strings.ToLower(auditInfo)); s {
+		case PassModerationSuggestion, ReviewModerationSuggestion, BlockModerationSuggestion:
+			m.Checked, m.Suggestion = true, s
+			return m, nil
+		}
+		return nil, fmt.Errorf("ParseAuditInfo: audit_info: %q, error: unknown format", auditInfo)
+	}
+	var raw rawAuditInfo
+	if err := json.Unmarshal([]byte(auditInfo), &raw); err != nil {
+		return nil, fmt.Errorf("json.Unmarshal: audit_info: %q, error: %w", auditInfo, err)
+	}
+	a := raw.AuditInfo
+	a.CheckFail = a.CheckFail || raw.CheckFailCamel
+	if a.TotalSuggestion == "" {
+		a.TotalSuggestion = raw.TotalSuggestionCamel
+	}
+	for _, scene := range append(raw.ScanSceneDTOS, raw.ScanSceneDTOSCamel...) {
+		s := scene.ScanSceneDTO
+		if s.LabelDesc == "" {
+			s.LabelDesc = scene.LabelDescCamel
+		}
+		if len(s.SubLabels) == 0 {
+			s.SubLabels = scene.SubLabelsCamel
+		}
+		a.ScanSceneDTOS = append(a.ScanSceneDTOS, s)
+	}
+	m = ModerationFromAuditInfo(a)
+	m.Source = AuditInfoModerationSource
+	return m, nil
+}
+
+// ModerationFromAuditInfo get moderation of AuditInfo of callback
+func ModerationFromAuditInfo(a AuditInfo) *Moderation {
+	return &Moderation{
+		Source:     CallBackModerationSource,
+		Url:        a.Url,
+		Checked:    true,
+		CheckFail:  a.CheckFail,
+		Hit:        a.Hit,
+		Suggestion: ModerationSuggestion(strings.ToLower(a.TotalSuggestion)),
+		Scenes:     moderationScenes(a.ScanSceneDTOS, ""),
+	}
+}
+
+// ModerationFromViolationInfo get moderation of Violation and ViolationInfo of video
+func ModerationFromViolationInfo(violation bool, v ViolationInfo) *Moderation {
+	m := &Moderation{
+		Source:     ViolationInfoModerationSource,
+		Url:        v.Url,
+		Checked:    violation || v.TotalSuggestion != "" || v.DataId != "" || len(v.ScreenshotInfos) > 0,
+		CheckFail:  v.CheckFail,
+		Violation:  violation,
+		Suggestion: ModerationSuggestion(strings.ToLower(v.TotalSuggestion)),
+	}
+	for _, info := range v.ScreenshotInfos {
+		m.Scenes = append(m.Scenes, moderationScenes(info.ScanSceneDTOS, info.Url)...)
+	}
+	return m
+}
+
+func moderationScenes(scenes []ScanSceneDTO, url string) []ModerationScene {
+	result := make([]ModerationScene, 0, len(scenes))
+	for _, s := range scenes {
+		result = append(result, ModerationScene{
+			Scene:      s.Scene,
+			Label:      s.Label,
+			LabelDesc:  s.LabelDesc,
+			Suggestion: ModerationSuggestion(strings.ToLower(s.Suggestion)),
+			Rate:       s.Rate,
+			SubLabels:  s.SubLabels,
+			Url:        url,
+		})
+	}
+	return result
+}
+
+// ModerationOf get moderation of result, result is one of ImageGeneratingInfo, GeneratingInfoPro, ImageInfoData,
+// CreateImageCallBackSuccessResp, VideoInfo or LabInfo, or pointer of them,
+// InvolveYellow and CheckIsViolation are 1 when result is flagged
+func ModerationOf(result interface{}) (*Moderation, error) {
+	withAuditInfo := func(key, url, auditInfo string, involveYellow int) (*Moderation, error) {
+		m, err := ParseAuditInfo(auditInfo)
+		if err != nil {
+			return nil, err
+		}
+		m.Key, m.InvolveYellow = key, involveYellow == 1
+		if m.Url == "" {
+			m.Url = url
+		}
+		return m, nil
+	}
+	switch r := result.(type) {
+	case ImageGeneratingInfo:
+		return withAuditInfo(r.Key, r.PictureURL, r.AuditInfo, r.InvolveYellow)
+	case GeneratingInfoPro:
+		return withAuditInfo(r.Key, r.PictureURL, r.AuditInfo, r.InvolveYellow)
+	case ImageInfoData:
+		return withAuditInfo("", r.PictureUrl, r.AuditInfo, r.InvolveYellow)
+	case CreateImageCallBackSuccessResp:
+		m := ModerationFromAuditInfo(r.AuditInfo)
+		m.Key, m.InvolveYellow = r.Key, r.InvolveYellow == 1
+		if m.Url == "" {
+			m.Url = r.ArtworkUrl
+		}
+		return m, nil
+	case VideoInfo:
+		m := ModerationFromViolationInfo(r.Violation, r.ViolationInfo)
+		m.Key = r.Key
+		if m.Url == "" {
+			m.Url = r.AiVideoUrl
+		}
+		return m, nil
+	case LabInfo:
+		return &Moderation{
+			Source:    LabModerationSource,
+			Key:       r.ServiceKey,
+			Checked:   true,
+			Violation: r.CheckIsViolation == 1,
+		}, nil
+	case *ImageGeneratingInfo:
+		return ModerationOf(*r)
+	case *GeneratingInfoPro:
+		return ModerationOf(*r)
+	case *ImageInfoData:
+		return ModerationOf(*r)
+	case *CreateImageCallBackSuccessResp:
+		return ModerationOf(*r)
+	case *VideoInfo:
+		return ModerationOf(*r)
+	case *LabInfo:
+		return ModerationOf(*r)
+	}
+	return nil, fmt.Errorf("ModerationOf: type: %T, error: no moderation", result)
+}
+
+// ModerationAction is what to do with a result by moderation
+type ModerationAction int
+
+const (
+	AllowModerationAction ModerationAction = iota
+	QuarantineModerationAction
+	SkipModerationAction
+)
+
+func (m ModerationAction) String() string {
+	switch m {
+	case AllowModerationAction:
+		return "allow"
+	case QuarantineModerationAction:
+		return "quarantine"
+	case SkipModerationAction:
+		return "skip"
+	}
+	return fmt.Sprintf("ModerationAction(%d)", int(m))
+}
+
+// ModerationPolicy decide action of moderation
+type ModerationPolicy func(m *Moderation) ModerationAction
+
+// DefaultModerationPolicy skip blocked results and quarantine results to review
+func DefaultModerationPolicy(m *Moderation) ModerationAction {
+	switch {
+	case m.IsBlocked():
+		return SkipModerationAction
+	case m.NeedsReview():
+		return QuarantineModerationAction
+	}
+	return AllowModerationAction
+}
+
+// RateModerationPolicy take action when result is blocked or any flagged rate reaches maxRate
+func RateModerationPolicy(maxRate float64, action ModerationAction) ModerationPolicy {
+	return func(m *Moderation) ModerationAction {
+		if m.IsBlocked() || m.MaxRate() >= maxRate {
+			return action
+		}
+		return AllowModerationAction
+	}
+}
+
+// ModeratedDownload is downloads of one result under moderation policy
+type ModeratedDownload struct {
+	Moderation *Moderation
+	Action     ModerationAction
+	Results    []DownloadResult
+}
+
+// ModeratedDownloader downloads results by Downloader under Policy, quarantined results go to QuarantineDir,
+// which is the quarantine directory of dir when it is empty, skipped results are not downloaded
+type ModeratedDownloader struct {
+	Downloader    *Downloader
+	Policy        ModerationPolicy
+	QuarantineDir string
+	OnFlagged     func(m *Moderation, action ModerationAction)
+}
+
+// NewModeratedDownloader new moderated downloader with DefaultModerationPolicy
+func NewModeratedDownloader(d *Downloader) *ModeratedDownloader {
+	return &ModeratedDownloader{Downloader: d, Policy: DefaultModerationPolicy}
+}
+
+// DownloadAll download results into dir, results is a result of ModerationOf or a slice of them
+func (d *ModeratedDownloader) DownloadAll(ctx context.Context, results interface{}, dir string) ([]ModeratedDownload, error) {
+	var downloads []ModeratedDownload
+	for _, result := range flattenResults(results) {
+		m, err := ModerationOf(result)
+		if err != nil {
+			return downloads, fmt.Errorf("ModerationOf: %w", err)
+		}
+		download := ModeratedDownload{Moderation: m, Action: d.action(m)}
+		if download.Action != AllowModerationAction && d.OnFlagged != nil {
+			d.OnFlagged(m, download.Action)
+		}
+		switch download.Action {
+		case AllowModerationAction:
+			download.Results = d.Downloader.DownloadAll(ctx, AssetsOf(result), dir)
+		case QuarantineModerationAction:
+			quarantineDir := d.QuarantineDir
+			if quarantineDir == "" {
+				quarantineDir = filepath.Join(dir, "quarantine")
+			}
+			download.Results = d.Downloader.DownloadAll(ctx, AssetsOf(result), quarantineDir)
+		}
+		downloads = append(downloads, download)
+	}
+	return downloads, nil
+}
+
+func (d *ModeratedDownloader) action(m *Moderation) ModerationAction {
+	if d.Policy == nil {
+		return DefaultModerationPolicy(m)
+	}
+	return d.Policy(m)
+}
+
+// ModerationHook is a CallerHook which checks moderation of results of query calls by Policy,
+// OnFlagged is called for results which are not allowed
+type ModerationHook struct {
+	Policy    ModerationPolicy
+	OnFlagged func(ctx context.Context, call *Call, m *Moderation, action ModerationAction)
+}
+
+// NewModerationHook new moderation hook with DefaultModerationPolicy
+func NewModerationHook(onFlagged func(ctx context.Context, call *Call, m *Moderation, action ModerationAction)) *ModerationHook {
+	return &ModerationHook{Policy: DefaultModerationPolicy, OnFlagged: onFlagged}
+}
+
+// BeforeCall do nothing
+func (h *ModerationHook) BeforeCall(_ context.Context, _ *Call) error {
+	return nil
+}
+
+// AfterCall check moderation of every result
+func (h *ModerationHook) AfterCall(ctx context.Context, call *Call, code WujieCode, result interface{}, err error) {
+	if err != nil || code != OKWujieCode || h.OnFlagged == nil {
+		return
+	}
+	if info, ok := result.(*VideoGeneratingInfo); ok && info != nil {
+		result = info.List
+	}
+	policy := h.Policy
+	if policy == nil {
+		policy = DefaultModerationPolicy
+	}
+	for _, r := range flattenResults(result) {
+		m, err := ModerationOf(r)
+		if err != nil {
+			continue
+		}
+		if key, ok := call.Request.(string); ok && m.Key == "" {
+			m.Key = key
+		}
+		if action := policy(m); action != AllowModerationAction {
+			h.OnFlagged(ctx, call, m, action)
+		}
+	}
+}
+
+// flattenResults get elements of slice results, or results itself
+func flattenResults(results interface{}) []interface{} {
+	v := reflect.ValueOf(results)
+	if !v.IsValid() {
+		return nil
+	}
+	if v.Kind() == reflect.Ptr && v.IsNil() {
+		return nil
+	}
+	if v.Kind() != reflect.Slice {
+		return []interface{}{results}
+	}
+	items := make([]interface{}, 0, v.Len())
+	for i := 0; i < v.Len(); i++ {
+		items = append(items, v.Index(i).Interface())
+	}
+	return items
+}
@@ -0,0 +1,160 @@
+package wujiesdk
+
+// @Title        price.go
+// @Description  price create call before submission
+// @Create       XdpCs 2026-10-19 13:45
+// @Update       XdpCs 2026-10-20 09:40
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrUnpricedCall is the error of create call whose price is neither exposed by open api nor set in PriceTable
+var ErrUnpricedCall = errors.New("call can not be priced before submission")
+
+// Cost is the points and pro duration spent by calls
+type Cost struct {
+	Points      int `json:"points"`
+	ProDuration int `json:"pro_duration"`
+}
+
+// Add get the sum of c and o
+func (c Cost) Add(o Cost) Cost {
+	return Cost{Points: c.Points + o.Points, ProDuration: c.ProDuration + o.ProDuration}
+}
+
+// Sub get c minus o
+func (c Cost) Sub(o Cost) Cost {
+	return Cost{Points: c.Points - o.Points, ProDuration: c.ProDuration - o.ProDuration}
+}
+
+// IsZero report whether c spends nothing
+func (c Cost) IsZero() bool {
+	return c.Points == 0 && c.ProDuration == 0
+}
+
+func (c Cost) String() string {
+	return fmt.Sprintf("points: %d, pro_duration: %d", c.Points, c.ProDuration)
+}
+
+// Pricer price the call before submission, calls which spend nothing return zero Cost
+type Pricer interface {
+	Price(ctx context.Context, call *Call) (Cost, error)
+}
+
+// PricerFunc is a function implementing Pricer
+type PricerFunc func(ctx context.Context, call *Call) (Cost, error)
+
+func (f PricerFunc) Price(ctx context.Context, call *Call) (Cost, error) {
+	return f(ctx, call)
+}
+
+// PriceTable is the price of calls which open api can not price before submission,
+// zero field means the price is unknown, and calls priced by it fail with ErrUnpricedCall
+type PriceTable struct {
+	ProDurationPerImage       int           // pro duration of one image of CreateImagePro
+	SuperSizePoints           int           // points of PostSuperSize with IntegralSuperSizeCostType, derived from resolutions of the first model if zero
+	SuperSizeProDuration      int           // pro duration of PostSuperSize with DurationSuperSizeCostType
+	SVDPointsPerSecond        int           // points of one second of CreateSVD
+	VideoPriceTableExpiration time.Duration // ttl of VideoCostEstimator, zero uses DefaultVideoPriceTableTTL
+	// Calls is the cost of other create calls by Call.Name, such as CreateMidjourney, set zero Cost for free calls
+	Calls map[string]Cost
+}
+
+// CallerPricer price create call by Caller,
+// CreateImage is priced by ImagePriceInfo, CreateVideo by Video, PostSuperSize by SuperSizeDetails of resolutions of the first model,
+// CreateImagePro, CreateSVD and other create calls by PriceTable, create calls which can not be priced fail with ErrUnpricedCall
+type CallerPricer struct {
+	Caller     *Caller
+	PriceTable PriceTable
+	Video      *VideoCostEstimator
+}
+
+// NewCallerPricer new caller pricer
+func NewCallerPricer(c *Caller, table PriceTable) *CallerPricer {
+	return &CallerPricer{
+		Caller:     c,
+		PriceTable: table,
+		Video:      NewVideoCostEstimator(c, table.VideoPriceTableExpiration),
+	}
+}
+
+// Price price the call, calls which are not create calls spend nothing
+func (p *CallerPricer) Price(ctx context.Context, call *Call) (Cost, error) {
+	switch r := call.Request.(type) {
+	case *CreateImageRequest:
+		_, data, err := p.Caller.ImagePriceInfo(ctx, &ImagePriceInfoRequest{CreateImageRequest: *r})
+		if err != nil {
+			return Cost{}, fmt.Errorf("p.Caller.ImagePriceInfo: %w", err)
+		}
+		return Cost{Points: data.IntegralUse.Total()}, nil
+	case *CreateImageProRequest:
+		if p.PriceTable.ProDurationPerImage <= 0 {
+			return Cost{}, unpriced(call)
+		}
+		batch := r.BatchCount
+		if batch <= 0 {
+			batch = 1
+		}
+		return Cost{ProDuration: p.PriceTable.ProDurationPerImage * batch}, nil
+	case *PostSuperSizeRequest:
+		if r.CostType == DurationSuperSizeCostType {
+			if p.PriceTable.SuperSizeProDuration <= 0 {
+				return Cost{}, unpriced(call)
+			}
+			return Cost{ProDuration: p.PriceTable.SuperSizeProDuration}, nil
+		}
+		if p.PriceTable.SuperSizePoints > 0 {
+			return Cost{Points: p.PriceTable.SuperSizePoints}, nil
+		}
+		return p.superSizePoints(ctx, call, r.Multiple)
+	case *CreateVideoRequest:
+		return p.Video.Price(ctx, call)
+	case *CreateSVDRequest:
+		if p.PriceTable.SVDPointsPerSecond <= 0 {
+			return Cost{}, unpriced(call)
+		}
+		return Cost{Points: p.PriceTable.SVDPointsPerSecond * r.Duration}, nil
+	}
+	if !isCreateCall(call) {
+		return Cost{}, nil
+	}
+	if cost, ok := p.PriceTable.Calls[call.Name]; ok {
+		return cost, nil
+	}
+	return Cost{}, unpriced(call)
+}
+
+// superSizePoints get the highest integral price of multiple in SuperSizeDetails of resolutions of the first model
+func (p *CallerPricer) superSizePoints(ctx context.Context, call *Call, multiple float64) (Cost, error) {
+	_, models, err := p.Caller.ModelBaseInfos(ctx)
+	if err != nil {
+		return Cost{}, fmt.Errorf("p.Caller.ModelBaseInfos: %w", err)
+	}
+	if len(models) == 0 {
+		return Cost{}, unpriced(call)
+	}
+	_, data, err := p.Caller.DefaultResourceModel(ctx, models[0].ModelCode)
+	if err != nil {
+		return Cost{}, fmt.Errorf("p.Caller.DefaultResourceModel: %w", err)
+	}
+	points, found := 0, false
+	for _, resolution := range data.CreateOptionMenu.Resolution {
+		for _, detail := range resolution.SuperSizeDetails {
+			if detail.Multiple == multiple && detail.IntegralPrice >= points {
+				points, found = detail.IntegralPrice, true
+			}
+		}
+	}
+	if !found {
+		return Cost{}, fmt.Errorf("multiple: %v, error: %w", multiple, unpriced(call))
+	}
+	return Cost{Points: points}, nil
+}
+
+func unpriced(call *Call) error {
+	return fmt.Errorf("call: %s, error: %w", call.Name, ErrUnpricedCall)
+}
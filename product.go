@@ -0,0 +1,346 @@
+package wujiesdk
+
+// @Title        product.go
+// @Description  submit and poll jobs of every product by one api
+// @Create       XdpCs 2026-10-19 23:40
+// @Update       XdpCs 2026-10-20 10:55
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Product is a kind of job created by Caller
+type Product string
+
+const (
+	ImageProduct          Product = "image"           // CreateImage, polled by GeneratingInfo
+	ProProduct            Product = "pro"             // CreateImagePro, polled by GeneratingInfoPro
+	MidjourneyProduct     Product = "midjourney"      // CreateMidjourney, polled by GeneratingInfo
+	FluxProduct           Product = "flux"            // CreateFlux, polled by GeneratingInfo
+	YouthifyProduct       Product = "youthify"        // Youthify, polled by GeneratingInfo
+	SuperSizeProduct      Product = "supersize"       // PostSuperSize, polled by GetSuperSize
+	PromptOptimizeProduct Product = "prompt_optimize" // PromptOptimizeSubmit, polled by PromptOptimizeResult with task id as key
+	AvatarProduct         Product = "avatar"          // CreateAvatar, polled by AvatarInfo
+	AvatarArtworkProduct  Product = "avatar_artwork"  // CreateAvatarArtwork, polled by GeneratingInfo
+	SpellAnalysisProduct  Product = "spell_analysis"  // CreateSpellAnalysis, polled by SpellAnalysisInfo
+	VideoProduct          Product = "video"           // CreateVideo, polled by VideoInfo
+	CameraProduct         Product = "camera"          // CreateCamera, polled by CameraGeneratingInfo
+	SegmentationProduct   Product = "segmentation"    // CreateSegmentation, polled by LabInfo
+	InfiniteZoomProduct   Product = "infinite_zoom"   // CreateInfiniteZoom, polled by LabInfo
+	VectorStudioProduct   Product = "vector_studio"   // CreateVectorStudio, polled by LabInfo
+	SVDProduct            Product = "svd"             // CreateSVD, polled by SVDInfo
+)
+
+// productCall is the create call of product
+type productCall struct {
+	name       string
+	router     WujieRouter
+	newRequest func() interface{}
+}
+
+var productCalls = map[Product]productCall{
+	ImageProduct:          {"CreateImage", CreateImageWujieRouter, func() interface{} { return &CreateImageRequest{} }},
+	ProProduct:            {"CreateImagePro", CreateImageProWujieRouter, func() interface{} { return &CreateImageProRequest{} }},
+	MidjourneyProduct:     {"CreateMidjourney", CreateMidjourneyWujieRouter, func() interface{} { return &CreateMidjourneyRequest{} }},
+	FluxProduct:           {"CreateFlux", CreateFluxWujieRouter, func() interface{} { return &CreateFluxRequest{} }},
+	YouthifyProduct:       {"Youthify", YouthifyWujieRouter, func() interface{} { return &YouthifyRequest{} }},
+	SuperSizeProduct:      {"PostSuperSize", SuperSizeWujieRouter, func() interface{} { return &PostSuperSizeRequest{} }},
+	PromptOptimizeProduct: {"PromptOptimizeSubmit", PromptOptimizeSubmitWujieRouter, func() interface{} { return &PromptOptimizeSubmitRequest{} }},
+	AvatarProduct:         {"CreateAvatar", CreateAvatarWujieRouter, func() interface{} { return &CreateAvatarRequest{} }},
+	AvatarArtworkProduct:  {"CreateAvatarArtwork", CreateAvatarArtworkWujieRouter, func() interface{} { return &CreateAvatarArtworkRequest{} }},
+	SpellAnalysisProduct:  {"CreateSpellAnalysis", CreateSpellAnalysisWujieRouter, func() interface{} { return &CreateSpellAnalysisRequest{} }},
+	VideoProduct:          {"CreateVideo", CreateVideoWujieRouter, func() interface{} { return &CreateVideoRequest{} }},
+	CameraProduct:         {"CreateCamera", CreateCameraWujieRouter, func() interface{} { return &CreateCameraRequest{} }},
+	SegmentationProduct:   {"CreateSegmentation", CreateSegmentationWujieRouter, func() interface{} { return &CreateSegmentationRequest{} }},
+	InfiniteZoomProduct:   {"CreateInfiniteZoom", CreateInfiniteZoomWujieRouter, func() interface{} { return &CreateInfiniteZoomRequest{} }},
+	VectorStudioProduct:   {"CreateVectorStudio", CreateVectorStudioWujieRouter, func() interface{} { return &CreateVectorStudioRequest{} }},
+	SVDProduct:            {"CreateSVD", CreateSVDWujieRouter, func() interface{} { return &CreateSVDRequest{} }},
+}
+
+// NewRequest get a new create request of product, such as *CreateImageRequest for ImageProduct
+func (p Product) NewRequest() (interface{}, error) {
+	call, ok := productCalls[p]
+	if !ok {
+		return nil, fmt.Errorf("Product.NewRequest: product: %q, error: unknown product", string(p))
+	}
+	return call.newRequest(), nil
+}
+
+// Call get the create call of product with request, it is what CallerHook and Pricer see
+func (p Product) Call(request interface{}) *Call {
+	call := productCalls[p]
+	return &Call{Name: call.name, Router: call.router, Request: request}
+}
+
+// ProductOf get product of create request
+func ProductOf(request interface{}) (Product, bool) {
+	switch request.(type) {
+	case *CreateImageRequest:
+		return ImageProduct, true
+	case *CreateImageProRequest:
+		return ProProduct, true
+	case *CreateMidjourneyRequest:
+		return MidjourneyProduct, true
+	case *CreateFluxRequest:
+		return FluxProduct, true
+	case *YouthifyRequest:
+		return YouthifyProduct, true
+	case *PostSuperSizeRequest:
+		return SuperSizeProduct, true
+	case *PromptOptimizeSubmitRequest:
+		return PromptOptimizeProduct, true
+	case *CreateAvatarRequest:
+		return AvatarProduct, true
+	case *CreateAvatarArtworkRequest:
+		return AvatarArtworkProduct, true
+	case *CreateSpellAnalysisRequest:
+		return SpellAnalysisProduct, true
+	case *CreateVideoRequest:
+		return VideoProduct, true
+	case *CreateCameraRequest:
+		return CameraProduct, true
+	case *CreateSegmentationRequest:
+		return SegmentationProduct, true
+	case *CreateInfiniteZoomRequest:
+		return InfiniteZoomProduct, true
+	case *CreateVectorStudioRequest:
+		return VectorStudioProduct, true
+	case *CreateSVDRequest:
+		return SVDProduct, true
+	}
+	return "", false
+}
+
+// SubmittedJob is a job submitted by SubmitJob
+type SubmittedJob struct {
+	Product      Product
+	Keys         []string
+	Result       interface{} // result of the create call, such as *CreateImageData
+	ExpectedCost Cost        // zero if the create call does not return it
+}
+
+// SubmitJob submit create request of any product, request is one of the requests of ProductOf
+func (c *Caller) SubmitJob(ctx context.Context, request interface{}) (*SubmittedJob, error) {
+	product, ok := ProductOf(request)
+	if !ok {
+		return nil, fmt.Errorf("Caller.SubmitJob: request: %T, error: unknown product", request)
+	}
+	var (
+		result interface{}
+		err    error
+	)
+	switch r := request.(type) {
+	case *CreateImageRequest:
+		_, result, err = c.CreateImage(ctx, r)
+	case *CreateImageProRequest:
+		_, result, err = c.CreateImagePro(ctx, r)
+	case *CreateMidjourneyRequest:
+		_, result, err = c.CreateMidjourney(ctx, r)
+	case *CreateFluxRequest:
+		_, result, err = c.CreateFlux(ctx, r)
+	case *YouthifyRequest:
+		_, result, err = c.Youthify(ctx, r)
+	case *PostSuperSizeRequest:
+		_, result, err = c.PostSuperSize(ctx, r)
+	case *PromptOptimizeSubmitRequest:
+		_, _, err = c.PromptOptimizeSubmit(ctx, r)
+		result = r.TaskID
+	case *CreateAvatarRequest:
+		_, result, err = c.CreateAvatar(ctx, r)
+	case *CreateAvatarArtworkRequest:
+		_, result, err = c.CreateAvatarArtwork(ctx, r)
+	case *CreateSpellAnalysisRequest:
+		_, result, err = c.CreateSpellAnalysis(ctx, r)
+	case *CreateVideoRequest:
+		_, result, err = c.CreateVideo(ctx, r)
+	case *CreateCameraRequest:
+		_, result, err = c.CreateCamera(ctx, r)
+	case *CreateSegmentationRequest:
+		_, result, err = c.CreateSegmentation(ctx, r)
+	case *CreateInfiniteZoomRequest:
+		_, result, err = c.CreateInfiniteZoom(ctx, r)
+	case *CreateVectorStudioRequest:
+		_, result, err = c.CreateVectorStudio(ctx, r)
+	case *CreateSVDRequest:
+		_, result, err = c.CreateSVD(ctx, r)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Caller.SubmitJob: product: %s, error: %w", product, err)
+	}
+	job := &SubmittedJob{Product: product, Keys: createdKeys(result), Result: result}
+	job.ExpectedCost, _ = expectedCost(result)
+	return job, nil
+}
+
+// JobState is the state of keys of a job polled by PollJob
+type JobState struct {
+	Result interface{} // result of the query of product, such as []ImageGeneratingInfo
+	Done   bool        // every key is finished
+	Err    error       // error of the first failed key, nil if every finished key succeeded
+	Cost   Cost        // actual cost reported by the query, only images, pro and super size report it, see ActualCost
+}
+
+// PollJob get state of keys created for product
+func (c *Caller) PollJob(ctx context.Context, product Product, keys []string) (*JobState, error) {
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("Caller.PollJob: product: %s, error: no keys", product)
+	}
+	state := &JobState{}
+	switch product {
+	case ImageProduct, MidjourneyProduct, FluxProduct, YouthifyProduct, AvatarArtworkProduct:
+		_, infos, err := c.GeneratingInfo(ctx, keys)
+		if err != nil {
+			return nil, fmt.Errorf("c.GeneratingInfo: %w", err)
+		}
+		state.Result, state.Done = infos, len(infos) >= len(keys)
+		for _, info := range infos {
+			state.finish(info.Key, info.Status, info.FailMessage)
+			state.Cost.Points += info.IntegralCost
+		}
+	case ProProduct:
+		_, infos, err := c.GeneratingInfoPro(ctx, keys)
+		if err != nil {
+			return nil, fmt.Errorf("c.GeneratingInfoPro: %w", err)
+		}
+		state.Result, state.Done = infos, len(infos) >= len(keys)
+		for _, info := range infos {
+			state.finish(info.Key, info.Status, info.FailMessage)
+		}
+		if !state.Done {
+			break
+		}
+		// generating info of pro has no cost, ImageInfoPro reports duration cost of finished images
+		for _, key := range keys {
+			_, info, err := c.ImageInfoPro(ctx, key)
+			if err != nil {
+				return nil, fmt.Errorf("c.ImageInfoPro: key: %s, error: %w", key, err)
+			}
+			state.Cost.ProDuration += info.CostInfo.DurationCost
+		}
+	case SuperSizeProduct:
+		_, infos, err := c.GetSuperSize(ctx, keys)
+		if err != nil {
+			return nil, fmt.Errorf("c.GetSuperSize: %w", err)
+		}
+		state.Result, state.Done = infos, len(infos) >= len(keys)
+		for _, info := range infos {
+			state.finish(info.Key, info.Status, FailMessage{})
+			state.Cost = state.Cost.Add(Cost{Points: info.Integral, ProDuration: info.Duration})
+		}
+	case CameraProduct:
+		_, infos, err := c.CameraGeneratingInfo(ctx, keys)
+		if err != nil {
+			return nil, fmt.Errorf("c.CameraGeneratingInfo: %w", err)
+		}
+		state.Result, state.Done = infos, len(infos) >= len(keys)
+		for _, info := range infos {
+			state.finish(info.Key, info.Status, info.FailMessage)
+		}
+	case PromptOptimizeProduct:
+		_, data, err := c.PromptOptimizeResult(ctx, keys[0])
+		if err != nil {
+			return nil, fmt.Errorf("c.PromptOptimizeResult: %w", err)
+		}
+		state.Result, state.Done = data, true
+		state.finish(keys[0], data.Code, FailMessage{})
+	case AvatarProduct:
+		_, info, err := c.AvatarInfo(ctx, keys[0])
+		if err != nil {
+			return nil, fmt.Errorf("c.AvatarInfo: %w", err)
+		}
+		state.Result, state.Done = info, true
+		state.finish(keys[0], info.Status, FailMessage{})
+	case SpellAnalysisProduct:
+		_, info, err := c.SpellAnalysisInfo(ctx, keys[0])
+		if err != nil {
+			return nil, fmt.Errorf("c.SpellAnalysisInfo: %w", err)
+		}
+		state.Result, state.Done = info, true
+		state.finish(keys[0], info.Status, FailMessage{})
+	case VideoProduct:
+		_, info, err := c.VideoInfo(ctx, keys[0])
+		if err != nil {
+			return nil, fmt.Errorf("c.VideoInfo: %w", err)
+		}
+		state.Result, state.Done = info, true
+		state.finish(keys[0], info.Status, info.FailMessage)
+	case SVDProduct:
+		_, info, err := c.SVDInfo(ctx, keys[0])
+		if err != nil {
+			return nil, fmt.Errorf("c.SVDInfo: %w", err)
+		}
+		state.Result, state.Done = info, true
+		state.finish(keys[0], info.Status, info.FailMessage)
+	case SegmentationProduct, InfiniteZoomProduct, VectorStudioProduct:
+		aiType := map[Product]LabInfoType{
+			SegmentationProduct: SegmentationLabInfoType,
+			InfiniteZoomProduct: InfiniteZoomLabInfoType,
+			VectorStudioProduct: VectorLabInfoType,
+		}[product]
+		_, info, err := c.LabInfo(ctx, &LabInfoRequest{ServiceKey: keys[0], AiType: aiType})
+		if err != nil {
+			return nil, fmt.Errorf("c.LabInfo: %w", err)
+		}
+		state.Result = info
+		switch info.Status {
+		case "SUCCESS":
+			state.Done = true
+		case "FAILED":
+			state.Done = true
+			state.Err = fmt.Errorf("key: %s, error: %v", keys[0], info.FailMessage)
+		}
+	default:
+		return nil, fmt.Errorf("Caller.PollJob: product: %q, error: unknown product", string(product))
+	}
+	return state, nil
+}
+
+// finish record status of key, Done stays true only if every key is terminal
+func (s *JobState) finish(key string, status int, fail FailMessage) {
+	s.Done = s.Done && JobStatus(status).Terminal()
+	if JobStatus(status) != FailedJobStatus || s.Err != nil {
+		return
+	}
+	if fail.Failed() {
+		s.Err = fmt.Errorf("key: %s, error: %w", key, fail)
+		return
+	}
+	s.Err = fmt.Errorf("key: %s, error: job failed", key)
+}
+
+// ActualCost get actual cost of finished job of product, camera and video report no charged cost,
+// so expected is their cost when they succeed and zero when they fail
+func (s *JobState) ActualCost(product Product, expected Cost) Cost {
+	switch product {
+	case CameraProduct, VideoProduct:
+		if s.Err != nil {
+			return Cost{}
+		}
+		return expected
+	}
+	return s.Cost
+}
+
+// WaitJob poll keys created for product every interval until they finish, interval <= 0 uses DefaultJobPollInterval,
+// the state of finished job is returned with its Err
+func (c *Caller) WaitJob(ctx context.Context, product Product, keys []string, interval time.Duration) (*JobState, error) {
+	if interval <= 0 {
+		interval = DefaultJobPollInterval
+	}
+	for {
+		state, err := c.PollJob(ctx, product, keys)
+		if err != nil {
+			return nil, err
+		}
+		if state.Done {
+			return state, nil
+		}
+		select {
+		case <-ctx.Done():
+			return state, ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
@@ -0,0 +1,370 @@
+package wujiesdk
+
+// @Title        prompt_builder.go
+// @Description  compose prompt with weights, negatives, spells and catalog selections
+// @Create       XdpCs 2026-10-19 20:40
+// @Update       XdpCs 2026-10-20 12:40
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type promptToken struct {
+	text   string
+	weight float64
+}
+
+func (p promptToken) String() string {
+	if p.weight == 0 || p.weight == 1 {
+		return p.text
+	}
+	return "(" + p.text + ":" + strconv.FormatFloat(p.weight, 'f', -1, 64) + ")"
+}
+
+type fusionSelection struct {
+	nameOrKey string
+	weight    float64
+}
+
+// PromptBuilder composes prompt and selections of catalog into CreateImageRequest or CreateImageProRequest,
+// selections are names or keys, they are validated and mapped to keys by Catalog, or used as they are
+// when Catalog is nil
+type PromptBuilder struct {
+	Catalog   *Catalog
+	ModelCode ModelCode
+
+	tokens          []promptToken
+	negatives       []promptToken
+	spells          []string
+	styles          []string
+	artists         []string
+	elementMagic    []string
+	styleDecoration []string
+	characters      []string
+	fusions         []fusionSelection
+	styleModel      string
+}
+
+// NewPromptBuilder new prompt builder of model, catalog may be nil
+func NewPromptBuilder(catalog *Catalog, model ModelCode) *PromptBuilder {
+	return &PromptBuilder{Catalog: catalog, ModelCode: model}
+}
+
+// Add add tokens with weight 1
+func (p *PromptBuilder) Add(tokens ...string) *PromptBuilder {
+	for _, token := range tokens {
+		p.AddWeighted(token, 1)
+	}
+	return p
+}
+
+// AddWeighted add token as (token:weight)
+func (p *PromptBuilder) AddWeighted(token string, weight float64) *PromptBuilder {
+	if token = strings.TrimSpace(token); token != "" {
+		p.tokens = append(p.tokens, promptToken{text: token, weight: weight})
+	}
+	return p
+}
+
+// Negative add negative tokens with weight 1
+func (p *PromptBuilder) Negative(tokens ...string) *PromptBuilder {
+	for _, token := range tokens {
+		p.NegativeWeighted(token, 1)
+	}
+	return p
+}
+
+// NegativeWeighted add negative token as (token:weight)
+func (p *PromptBuilder) NegativeWeighted(token string, weight float64) *PromptBuilder {
+	if token = strings.TrimSpace(token); token != "" {
+		p.negatives = append(p.negatives, promptToken{text: token, weight: weight})
+	}
+	return p
+}
+
+// Spell add spells of QuerySpell by SpellName or SpellEnName, english name is inserted into prompt
+func (p *PromptBuilder) Spell(names ...string) *PromptBuilder {
+	p.spells = append(p.spells, names...)
+	return p
+}
+
+// Style select styles by name
+func (p *PromptBuilder) Style(names ...string) *PromptBuilder {
+	p.styles = append(p.styles, names...)
+	return p
+}
+
+// Artist select artists by name
+func (p *PromptBuilder) Artist(names ...string) *PromptBuilder {
+	p.artists = append(p.artists, names...)
+	return p
+}
+
+// ElementMagic select element magic by key or name
+func (p *PromptBuilder) ElementMagic(keysOrNames ...string) *PromptBuilder {
+	p.elementMagic = append(p.elementMagic, keysOrNames...)
+	return p
+}
+
+// StyleDecoration select style decoration by key or name
+func (p *PromptBuilder) StyleDecoration(keysOrNames ...string) *PromptBuilder {
+	p.styleDecoration = append(p.styleDecoration, keysOrNames...)
+	return p
+}
+
+// Character select characters by key or name
+func (p *PromptBuilder) Character(keysOrNames ...string) *PromptBuilder {
+	p.characters = append(p.characters, keysOrNames...)
+	return p
+}
+
+// ModelFusion select model fusion by key or name with weight
+func (p *PromptBuilder) ModelFusion(keyOrName string, weight float64) *PromptBuilder {
+	p.fusions = append(p.fusions, fusionSelection{nameOrKey: keyOrName, weight: weight})
+	return p
+}
+
+// StyleModel select style model by key or name
+func (p *PromptBuilder) StyleModel(keyOrName string) *PromptBuilder {
+	p.styleModel = keyOrName
+	return p
+}
+
+// Prompt get prompt of tokens, without spells and selections
+func (p *PromptBuilder) Prompt() string {
+	return joinTokens(p.tokens)
+}
+
+// UcPrompt get negative prompt
+func (p *PromptBuilder) UcPrompt() string {
+	return joinTokens(p.negatives)
+}
+
+func joinTokens(tokens []promptToken) string {
+	texts := make([]string, 0, len(tokens))
+	for _, t := range tokens {
+		texts = append(texts, t.String())
+	}
+	return strings.Join(texts, ", ")
+}
+
+// CreateImageRequest build CreateImageRequest, selections go to top-level fields
+func (p *PromptBuilder) CreateImageRequest() (*CreateImageRequest, error) {
+	var v ValidationErrors
+	menu := p.menu(&v, false)
+	tokens := append(append([]promptToken(nil), p.tokens...), p.spellTokens(&v)...)
+	c := &CreateImageRequest{
+		Model:           p.ModelCode,
+		Prompt:          joinTokens(tokens),
+		UcPrompt:        p.UcPrompt(),
+		Num:             1,
+		Style:           selectOptions(&v, "style", menu, p.styles, func(m *CreateOptionMenu) []ResourceOption { return m.Style }),
+		Artists:         selectOptions(&v, "artists", menu, p.artists, func(m *CreateOptionMenu) []ResourceOption { return m.Artist }),
+		ElementMagic:    selectChoices(&v, "element_magic", menu, p.elementMagic, func(m *CreateOptionMenu) []ResourceChoice { return m.ElementMagic }),
+		StyleDecoration: selectChoices(&v, "style_decoration", menu, p.styleDecoration, func(m *CreateOptionMenu) []ResourceChoice { return m.StyleDecoration }),
+		Character:       p.characterKeys(&v, menu, false),
+		ModelFusion:     p.fusionKeys(&v, menu, false),
+		StyleModel:      p.styleModelKey(&v),
+	}
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// CreateImageProRequest build CreateImageProRequest, characters and model fusions go to OptionParam,
+// styles and artists are inserted into prompt, element magic, style decoration and style model are not supported
+func (p *PromptBuilder) CreateImageProRequest(width, height int) (*CreateImageProRequest, error) {
+	var v ValidationErrors
+	menu := p.menu(&v, true)
+	tokens := append([]promptToken(nil), p.tokens...)
+	for _, name := range selectOptions(&v, "style", menu, p.styles, func(m *CreateOptionMenu) []ResourceOption { return m.Style }) {
+		tokens = append(tokens, promptToken{text: name})
+	}
+	for _, name := range selectOptions(&v, "artists", menu, p.artists, func(m *CreateOptionMenu) []ResourceOption { return m.Artist }) {
+		tokens = append(tokens, promptToken{text: name})
+	}
+	tokens = append(tokens, p.spellTokens(&v)...)
+	if len(p.elementMagic) > 0 {
+		v.add("element_magic", "is not supported by pro")
+	}
+	if len(p.styleDecoration) > 0 {
+		v.add("style_decoration", "is not supported by pro")
+	}
+	if p.styleModel != "" {
+		v.add("style_model", "is not supported by pro")
+	}
+	c := NewCreateImageProRequest(p.ModelCode, joinTokens(tokens), width, height,
+		WithProUcPrompt(p.UcPrompt()))
+	c.OptionParam = OptionParam{ModelFusion: p.fusionKeys(&v, menu, true), Character: p.characterKeys(&v, menu, true)}
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// menu get option menu of model, it is nil without catalog or when the menu of model is not known,
+// selections are not validated then
+func (p *PromptBuilder) menu(v *ValidationErrors, pro bool) *CreateOptionMenu {
+	if p.Catalog == nil {
+		return nil
+	}
+	var m *CatalogModel
+	var ok bool
+	if pro {
+		m, ok = p.Catalog.ProModel(p.ModelCode)
+	} else {
+		m, ok = p.Catalog.Model(p.ModelCode)
+	}
+	if !ok {
+		v.add("model", "%d is not in catalog", p.ModelCode)
+		return nil
+	}
+	return m.Menu
+}
+
+func (p *PromptBuilder) spellTokens(v *ValidationErrors) []promptToken {
+	var tokens []promptToken
+	for _, name := range p.spells {
+		text := name
+		if p.Catalog != nil {
+			spell, ok := p.findSpell(name)
+			if !ok {
+				v.add("spell", "%s is not in catalog", name)
+				continue
+			}
+			text = spell.EnName
+			if text == "" {
+				text = spell.Name
+			}
+		}
+		tokens = append(tokens, promptToken{text: text})
+	}
+	return tokens
+}
+
+func (p *PromptBuilder) findSpell(name string) (CatalogItem, bool) {
+	for _, item := range p.Catalog.Search(name, SpellCatalogItemKind) {
+		if strings.EqualFold(item.Name, name) || strings.EqualFold(item.EnName, name) {
+			return item, true
+		}
+	}
+	return CatalogItem{}, false
+}
+
+func (p *PromptBuilder) characterKeys(v *ValidationErrors, menu *CreateOptionMenu, pro bool) []string {
+	if len(p.characters) == 0 {
+		return nil
+	}
+	var options []FusionOption
+	if menu != nil {
+		options = p.Catalog.CompatibleCharacters(p.ModelCode, pro)
+	}
+	var keys []string
+	for _, c := range p.characters {
+		key, ok := selectFusion(options, c, menu == nil)
+		if !ok {
+			v.add("character", "%s is not compatible with model %d", c, p.ModelCode)
+			continue
+		}
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+func (p *PromptBuilder) fusionKeys(v *ValidationErrors, menu *CreateOptionMenu, pro bool) []ModelFusion {
+	if len(p.fusions) == 0 {
+		return nil
+	}
+	var options []FusionOption
+	if menu != nil {
+		options = p.Catalog.CompatibleFusions(p.ModelCode, pro)
+	}
+	var fusions []ModelFusion
+	for _, f := range p.fusions {
+		key, ok := selectFusion(options, f.nameOrKey, menu == nil)
+		if !ok {
+			v.add("model_fusion", "%s is not compatible with model %d", f.nameOrKey, p.ModelCode)
+			continue
+		}
+		fusions = append(fusions, ModelFusion{Key: key, Weight: f.weight})
+	}
+	return fusions
+}
+
+func (p *PromptBuilder) styleModelKey(v *ValidationErrors) string {
+	if p.styleModel == "" || p.Catalog == nil {
+		return p.styleModel
+	}
+	s, ok := p.Catalog.StyleModel(p.styleModel)
+	if !ok {
+		v.add("style_model", "%s is not in catalog", p.styleModel)
+		return ""
+	}
+	if s.ModelCode != 0 && s.ModelCode != p.ModelCode {
+		v.add("style_model", "%s is for model %d", p.styleModel, s.ModelCode)
+	}
+	return s.Key
+}
+
+func selectFusion(options []FusionOption, keyOrName string, unchecked bool) (string, bool) {
+	if unchecked {
+		return keyOrName, true
+	}
+	for _, o := range options {
+		if o.Key == keyOrName || strings.EqualFold(o.Name, keyOrName) {
+			return o.Key, true
+		}
+	}
+	return "", false
+}
+
+// selectOptions validate names of options, they are not validated when menu is nil
+func selectOptions(v *ValidationErrors, field string, menu *CreateOptionMenu, names []string,
+	options func(*CreateOptionMenu) []ResourceOption) []string {
+	if menu == nil || len(names) == 0 {
+		return names
+	}
+	var selected []string
+	for _, name := range names {
+		found := false
+		for _, o := range options(menu) {
+			if strings.EqualFold(o.Name, name) {
+				selected, found = append(selected, o.Name), true
+				break
+			}
+		}
+		if !found {
+			v.add(field, "%s is not in option menu", name)
+		}
+	}
+	return selected
+}
+
+// selectChoices validate keys or names of choices and map them to keys, they are not validated when menu is nil
+func selectChoices(v *ValidationErrors, field string, menu *CreateOptionMenu, keysOrNames []string,
+	choices func(*CreateOptionMenu) []ResourceChoice) []string {
+	if menu == nil || len(keysOrNames) == 0 {
+		return keysOrNames
+	}
+	var keys []string
+	for _, keyOrName := range keysOrNames {
+		found := false
+		for _, c := range choices(menu) {
+			if c.Key == keyOrName || strings.EqualFold(c.Name, keyOrName) {
+				keys, found = append(keys, c.Key), true
+				break
+			}
+		}
+		if !found {
+			v.add(field, "%s is not in option menu", keyOrName)
+		}
+	}
+	return keys
+}
+
+func (p *PromptBuilder) String() string {
+	return fmt.Sprintf("prompt: %s, uc_prompt: %s", p.Prompt(), p.UcPrompt())
+}
@@ -0,0 +1,67 @@
+package wujiesdk_test
+
+// @Title        prompt_builder_test.go
+// @Description  test prompt builder with catalog of fake server
+// @Create       XdpCs 2026-10-20 12:40
+// @Update       XdpCs 2026-10-20 12:40
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/XdpCs/wujiesdk"
+	"github.com/XdpCs/wujiesdk/wujietest"
+)
+
+func newTestCatalog(t *testing.T, s *wujietest.Server) *wujiesdk.Catalog {
+	t.Helper()
+	catalog := wujiesdk.NewCatalog(s.Caller())
+	if err := catalog.Refresh(context.Background()); err != nil {
+		t.Fatalf("Refresh error: %v", err)
+	}
+	return catalog
+}
+
+func TestPromptBuilderPro(t *testing.T) {
+	s := wujietest.NewServer(wujietest.WithTiming(0, 0))
+	defer s.Close()
+	catalog := newTestCatalog(t, s)
+
+	c, err := wujiesdk.NewPromptBuilder(catalog, 1).Add("a cat").Style("水彩").Artist("莫奈").
+		Character("少女").ModelFusion("动漫", 0.5).CreateImageProRequest(512, 512)
+	if err != nil {
+		t.Fatalf("CreateImageProRequest error: %v", err)
+	}
+	if c.Prompt != "a cat, 水彩, 莫奈" {
+		t.Errorf("prompt: %s", c.Prompt)
+	}
+	want := wujiesdk.OptionParam{ModelFusion: []wujiesdk.ModelFusion{{Key: "mf_anime", Weight: 0.5}}, Character: []string{"ch_girl"}}
+	if !reflect.DeepEqual(c.OptionParam, want) {
+		t.Errorf("option param: %+v, want %+v", c.OptionParam, want)
+	}
+
+	var verrs wujiesdk.ValidationErrors
+	if _, err := wujiesdk.NewPromptBuilder(catalog, 1).Add("a cat").Character("unknown").CreateImageProRequest(512, 512); !errors.As(err, &verrs) {
+		t.Errorf("CreateImageProRequest error: %v, want ValidationErrors", err)
+	}
+}
+
+func TestPromptBuilderProWithoutMenu(t *testing.T) {
+	f := wujietest.DefaultFixtures()
+	f.ModelBaseInfosPro[0].ModelVersion = "SD3"
+	s := wujietest.NewServer(wujietest.WithTiming(0, 0), wujietest.WithFixtures(f))
+	defer s.Close()
+	catalog := newTestCatalog(t, s)
+
+	// no non-pro model has the version of pro model 1, selections can not be validated and are kept as they are
+	c, err := wujiesdk.NewPromptBuilder(catalog, 1).Add("a cat").Style("水彩").Character("ch_girl").
+		ModelFusion("mf_anime", 0.5).CreateImageProRequest(512, 512)
+	if err != nil {
+		t.Fatalf("CreateImageProRequest error: %v", err)
+	}
+	if len(c.OptionParam.Character) != 1 || len(c.OptionParam.ModelFusion) != 1 {
+		t.Errorf("option param: %+v, want the selections", c.OptionParam)
+	}
+}
@@ -0,0 +1,333 @@
+package wujiesdk
+
+// @Title        prompt_screener.go
+// @Description  screen prompts by sensitive words locally before create calls
+// @Create       XdpCs 2026-10-19 20:05
+// @Update       XdpCs 2026-10-20 10:00
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// SensitiveWord is a term of dictionary, Variants such as pinyin or abbreviations are reported as Term,
+// variants are not generated, pinyin forms only come from Variants or word lists given by the caller
+type SensitiveWord struct {
+	Term     string
+	Variants []string
+	Category string
+}
+
+// SensitiveMatch is a sensitive word found in text
+type SensitiveMatch struct {
+	Term     string // term of dictionary
+	Matched  string // matched text in original text
+	Category string
+	Offset   int // byte offset of Matched in original text
+}
+
+// SensitiveWordError is the error of prompt containing sensitive word, it matches WujieCodeError of
+// PromptContainsSensitiveWordsWujieCode by errors.Is
+type SensitiveWordError struct {
+	Call  string
+	Field string
+	SensitiveMatch
+}
+
+func (s *SensitiveWordError) Error() string {
+	return fmt.Sprintf("%s: field: %s, sensitive word: %s, matched: %q, offset: %d", s.Call, s.Field, s.Term, s.Matched, s.Offset)
+}
+
+// Code get WujieCode which server would return
+func (s *SensitiveWordError) Code() WujieCode {
+	return PromptContainsSensitiveWordsWujieCode
+}
+
+// Is report whether target is WujieCodeError of PromptContainsSensitiveWordsWujieCode
+func (s *SensitiveWordError) Is(target error) bool {
+	t, ok := target.(*WujieCodeError)
+	return ok && t.Code == s.Code()
+}
+
+// normalizeRune fold full-width form to half-width form and case, it reports false for separators,
+// such as spaces and punctuation inserted between characters
+func normalizeRune(r rune) (rune, bool) {
+	switch {
+	case r == 0x3000:
+		r = ' '
+	case r >= 0xff01 && r <= 0xff5e:
+		r -= 0xfee0
+	}
+	if unicode.IsSpace(r) || unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.Is(unicode.Cf, r) {
+		return 0, false
+	}
+	return unicode.ToLower(r), true
+}
+
+// isWordRune report whether r is an ascii letter or digit, terms starting or ending with them match whole words only
+func isWordRune(r rune) bool {
+	return r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r))
+}
+
+// normalizeText get normalized runes of text and byte offsets of them in text,
+// separators between CJK characters are dropped, and separators next to ascii words become one space
+// so that latin terms do not match across word boundaries
+func normalizeText(text string) ([]rune, []int) {
+	runes := make([]rune, 0, len(text))
+	offsets := make([]int, 0, len(text))
+	separator := -1
+	for i, r := range text {
+		n, ok := normalizeRune(r)
+		if !ok {
+			if separator < 0 {
+				separator = i
+			}
+			continue
+		}
+		if separator >= 0 && len(runes) > 0 && (isWordRune(runes[len(runes)-1]) || isWordRune(n)) {
+			runes = append(runes, ' ')
+			offsets = append(offsets, separator)
+		}
+		separator = -1
+		runes = append(runes, n)
+		offsets = append(offsets, i)
+	}
+	return runes, offsets
+}
+
+type acNode struct {
+	next    map[rune]int
+	fail    int
+	outputs []int // indexes of patterns ending here
+}
+
+type acPattern struct {
+	length int
+	word   *SensitiveWord
+}
+
+// acMatcher is Aho-Corasick automaton over normalized runes
+type acMatcher struct {
+	nodes    []acNode
+	patterns []acPattern
+}
+
+func newACMatcher(words []SensitiveWord) *acMatcher {
+	m := &acMatcher{nodes: []acNode{{next: map[rune]int{}}}}
+	for i := range words {
+		word := &words[i]
+		for _, form := range append([]string{word.Term}, word.Variants...) {
+			runes, _ := normalizeText(form)
+			if len(runes) == 0 {
+				continue
+			}
+			m.add(runes, word)
+		}
+	}
+	m.build()
+	return m
+}
+
+func (m *acMatcher) add(runes []rune, word *SensitiveWord) {
+	node := 0
+	for _, r := range runes {
+		next, ok := m.nodes[node].next[r]
+		if !ok {
+			next = len(m.nodes)
+			m.nodes = append(m.nodes, acNode{next: map[rune]int{}})
+			m.nodes[node].next[r] = next
+		}
+		node = next
+	}
+	m.nodes[node].outputs = append(m.nodes[node].outputs, len(m.patterns))
+	m.patterns = append(m.patterns, acPattern{length: len(runes), word: word})
+}
+
+func (m *acMatcher) build() {
+	var queue []int
+	for _, child := range m.nodes[0].next {
+		queue = append(queue, child)
+	}
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		for r, child := range m.nodes[node].next {
+			queue = append(queue, child)
+			fail := m.nodes[node].fail
+			for fail != 0 {
+				if _, ok := m.nodes[fail].next[r]; ok {
+					break
+				}
+				fail = m.nodes[fail].fail
+			}
+			if next, ok := m.nodes[fail].next[r]; ok && next != child {
+				m.nodes[child].fail = next
+			}
+			m.nodes[child].outputs = append(m.nodes[child].outputs, m.nodes[m.nodes[child].fail].outputs...)
+		}
+	}
+}
+
+func (m *acMatcher) find(text string, all bool) []SensitiveMatch {
+	runes, offsets := normalizeText(text)
+	var matches []SensitiveMatch
+	node := 0
+	for i, r := range runes {
+		for node != 0 {
+			if _, ok := m.nodes[node].next[r]; ok {
+				break
+			}
+			node = m.nodes[node].fail
+		}
+		node = m.nodes[node].next[r]
+		for _, p := range m.nodes[node].outputs {
+			pattern := m.patterns[p]
+			first := i - pattern.length + 1
+			if (isWordRune(runes[first]) && first > 0 && isWordRune(runes[first-1])) ||
+				(isWordRune(r) && i+1 < len(runes) && isWordRune(runes[i+1])) {
+				continue
+			}
+			start := offsets[first]
+			end := len(text)
+			if i+1 < len(offsets) {
+				end = offsets[i+1]
+			}
+			matches = append(matches, SensitiveMatch{
+				Term:     pattern.word.Term,
+				Matched:  strings.TrimRightFunc(text[start:end], func(r rune) bool { _, ok := normalizeRune(r); return !ok }),
+				Category: pattern.word.Category,
+				Offset:   start,
+			})
+			if !all {
+				return matches
+			}
+		}
+	}
+	return matches
+}
+
+// PromptScreener screens prompts by sensitive word dictionaries before create calls,
+// full-width forms, case, spaces and punctuation between CJK characters are ignored in matching,
+// latin terms match whole words only,
+// it is a CallerHook of CreateImage, CreateImagePro, CreateMidjourney, CreateFlux, CreateAvatarArtwork
+// and PromptOptimizeSubmit, it is safe for concurrent use
+type PromptScreener struct {
+	mu      sync.RWMutex
+	words   []SensitiveWord
+	matcher *acMatcher
+}
+
+// NewPromptScreener new prompt screener with words
+func NewPromptScreener(words ...SensitiveWord) *PromptScreener {
+	p := &PromptScreener{}
+	p.AddWords(words...)
+	return p
+}
+
+// AddWords add words to dictionary
+func (p *PromptScreener) AddWords(words ...SensitiveWord) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.words = append(p.words, words...)
+	p.matcher = newACMatcher(append([]SensitiveWord(nil), p.words...))
+}
+
+// LoadWordList add words of category from r, one word in a line as term|variant|variant,
+// empty lines and lines starting with # are ignored
+func (p *PromptScreener) LoadWordList(r io.Reader, category string) error {
+	var words []SensitiveWord
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		forms := strings.Split(line, "|")
+		word := SensitiveWord{Term: strings.TrimSpace(forms[0]), Category: category}
+		for _, variant := range forms[1:] {
+			if variant = strings.TrimSpace(variant); variant != "" {
+				word.Variants = append(word.Variants, variant)
+			}
+		}
+		words = append(words, word)
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("scanner.Err: %w", err)
+	}
+	p.AddWords(words...)
+	return nil
+}
+
+// Screen get every sensitive word in text
+func (p *PromptScreener) Screen(text string) []SensitiveMatch {
+	return p.find(text, true)
+}
+
+// Check get SensitiveWordError of the first sensitive word in text
+func (p *PromptScreener) Check(text string) error {
+	if matches := p.find(text, false); len(matches) > 0 {
+		return &SensitiveWordError{Call: "PromptScreener.Check", SensitiveMatch: matches[0]}
+	}
+	return nil
+}
+
+func (p *PromptScreener) find(text string, all bool) []SensitiveMatch {
+	p.mu.RLock()
+	matcher := p.matcher
+	p.mu.RUnlock()
+	if matcher == nil || text == "" {
+		return nil
+	}
+	return matcher.find(text, all)
+}
+
+type screenedPrompt struct {
+	field  string
+	prompt string
+}
+
+// screenedPrompts get prompt fields of create request
+func screenedPrompts(request interface{}) []screenedPrompt {
+	var prompts []screenedPrompt
+	switch r := request.(type) {
+	case *CreateImageRequest:
+		prompts = append(prompts, screenedPrompt{"prompt", r.Prompt})
+	case *CreateImageProRequest:
+		prompts = append(prompts, screenedPrompt{"prompt", r.Prompt})
+		for i, b := range r.TiledDiffusionDTO.BboxControlStates {
+			prompts = append(prompts, screenedPrompt{fmt.Sprintf("tiled_diffusion_dto.bbox_control_states[%d].prompt", i), b.Prompt})
+		}
+		for i, a := range r.AdetailerDTOS {
+			prompts = append(prompts, screenedPrompt{fmt.Sprintf("adetailer_dtos[%d].ad_prompt", i), a.AdPrompt})
+		}
+		prompts = append(prompts, screenedPrompt{"face_editor_dto.prompt_for_face", r.FaceEditorDTO.PromptForFace})
+	case *CreateMidjourneyRequest:
+		prompts = append(prompts, screenedPrompt{"prompt", r.Prompt})
+	case *CreateFluxRequest:
+		prompts = append(prompts, screenedPrompt{"prompt", r.Prompt})
+	case *CreateAvatarArtworkRequest:
+		prompts = append(prompts, screenedPrompt{"prompt", r.Prompt})
+	case *PromptOptimizeSubmitRequest:
+		prompts = append(prompts, screenedPrompt{"original", r.Original})
+	}
+	return prompts
+}
+
+// BeforeCall screen prompts of call.Request, it returns SensitiveWordError without calling server
+func (p *PromptScreener) BeforeCall(_ context.Context, call *Call) error {
+	for _, s := range screenedPrompts(call.Request) {
+		if matches := p.find(s.prompt, false); len(matches) > 0 {
+			return &SensitiveWordError{Call: call.Name, Field: s.field, SensitiveMatch: matches[0]}
+		}
+	}
+	return nil
+}
+
+// AfterCall do nothing
+func (p *PromptScreener) AfterCall(_ context.Context, _ *Call, _ WujieCode, _ interface{}, _ error) {
+}
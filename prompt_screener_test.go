@@ -0,0 +1,35 @@
+package wujiesdk_test
+
+// @Title        prompt_screener_test.go
+// @Description  test prompt screener against fake server
+// @Create       XdpCs 2026-10-20 11:50
+// @Update       XdpCs 2026-10-20 11:50
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/XdpCs/wujiesdk"
+	"github.com/XdpCs/wujiesdk/wujietest"
+)
+
+func TestPromptScreener(t *testing.T) {
+	s := wujietest.NewServer(wujietest.WithTiming(0, 0))
+	defer s.Close()
+	c := s.Caller()
+	c.AddCallerHooks(wujiesdk.NewPromptScreener(wujiesdk.SensitiveWord{Term: "sex"}, wujiesdk.SensitiveWord{Term: "色情"}))
+	ctx := context.Background()
+
+	for _, prompt := range []string{"this is exotic", "Essex county", "sextant"} {
+		if _, _, err := c.CreateImage(ctx, &wujiesdk.CreateImageRequest{Model: 1, Prompt: prompt, Num: 1}); err != nil {
+			t.Errorf("prompt: %q, error: %v", prompt, err)
+		}
+	}
+	want := &wujiesdk.WujieCodeError{Code: wujiesdk.PromptContainsSensitiveWordsWujieCode}
+	for _, prompt := range []string{"SEX!", "ｓｅｘ", "a sex b", "色 情", "色，情"} {
+		if _, _, err := c.CreateImage(ctx, &wujiesdk.CreateImageRequest{Model: 1, Prompt: prompt, Num: 1}); !errors.Is(err, want) {
+			t.Errorf("prompt: %q, error: %v, want sensitive word error", prompt, err)
+		}
+	}
+}
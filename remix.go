@@ -0,0 +1,392 @@
+package wujiesdk
+
+// @Title        remix.go
+// @Description  rebuild create request from an existing artwork
+// @Create       XdpCs 2026-10-19 17:10
+// @Update       XdpCs 2026-10-20 10:45
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// RemixOption is option of remix
+type RemixOption func(*remixOverride)
+
+type remixOverride struct {
+	seed       *string
+	prompt     *string
+	ucPrompt   *string
+	width      int
+	height     int
+	strength   *int
+	num        int
+	artworkUrl string
+	catalog    *Catalog
+}
+
+// WithRemixSeed set seed, empty seed lets server choose a random one for variations
+func WithRemixSeed(seed string) RemixOption {
+	return func(r *remixOverride) {
+		r.seed = &seed
+	}
+}
+
+// WithRemixPrompt replace prompt
+func WithRemixPrompt(prompt string) RemixOption {
+	return func(r *remixOverride) {
+		r.prompt = &prompt
+	}
+}
+
+// WithRemixUcPrompt replace negative prompt
+func WithRemixUcPrompt(ucPrompt string) RemixOption {
+	return func(r *remixOverride) {
+		r.ucPrompt = &ucPrompt
+	}
+}
+
+// WithRemixSize replace width and height
+func WithRemixSize(width, height int) RemixOption {
+	return func(r *remixOverride) {
+		r.width, r.height = width, height
+	}
+}
+
+// WithRemixStrength use the artwork as init image with creativity degree strength in [0, 100],
+// a lower strength keeps closer to the artwork
+func WithRemixStrength(strength int) RemixOption {
+	return func(r *remixOverride) {
+		r.strength = &strength
+	}
+}
+
+// WithRemixNum set number of images, it is 1 by default
+func WithRemixNum(num int) RemixOption {
+	return func(r *remixOverride) {
+		r.num = num
+	}
+}
+
+// WithRemixCatalog map model fusion names of ImageInfoPro to keys by catalog
+func WithRemixCatalog(catalog *Catalog) RemixOption {
+	return func(r *remixOverride) {
+		r.catalog = catalog
+	}
+}
+
+func newRemixOverride(options []RemixOption) *remixOverride {
+	r := &remixOverride{}
+	for _, option := range options {
+		option(r)
+	}
+	return r
+}
+
+// RemixImage get CreateImageRequest which recreates the artwork of key, by CreateParams and ImageInfo
+func (c *Caller) RemixImage(ctx context.Context, key string, options ...RemixOption) (*CreateImageRequest, error) {
+	_, params, err := c.CreateParams(ctx, []string{key})
+	if err != nil {
+		return nil, fmt.Errorf("c.CreateParams: %w", err)
+	}
+	if len(params) == 0 {
+		return nil, fmt.Errorf("c.CreateParams: key: %s, error: no create params", key)
+	}
+	_, info, err := c.ImageInfo(ctx, key)
+	if err != nil {
+		return nil, fmt.Errorf("c.ImageInfo: %w", err)
+	}
+	return RemixCreateParams(&params[0], info, options...)
+}
+
+// RemixImagePro get CreateImageProRequest which recreates the artwork of key, by ImageInfoPro,
+// GeneratingInfoPro is also used for the artwork url when WithRemixStrength is set
+func (c *Caller) RemixImagePro(ctx context.Context, key string, options ...RemixOption) (*CreateImageProRequest, error) {
+	_, info, err := c.ImageInfoPro(ctx, key)
+	if err != nil {
+		return nil, fmt.Errorf("c.ImageInfoPro: %w", err)
+	}
+	override := newRemixOverride(options)
+	if override.strength != nil {
+		_, infos, err := c.GeneratingInfoPro(ctx, []string{key})
+		if err != nil {
+			return nil, fmt.Errorf("c.GeneratingInfoPro: %w", err)
+		}
+		for _, i := range infos {
+			if i.Key == key {
+				override.artworkUrl = i.PictureURL
+			}
+		}
+		if override.artworkUrl == "" {
+			return nil, fmt.Errorf("c.GeneratingInfoPro: key: %s, error: no picture url", key)
+		}
+	}
+	return remixImageInfoPro(info, override), nil
+}
+
+// RemixCreateParams build CreateImageRequest from CreateParams, info fills size and artwork url and may be nil
+func RemixCreateParams(params *CreateParams, info *ImageInfoData, options ...RemixOption) (*CreateImageRequest, error) {
+	override := newRemixOverride(options)
+	c := &CreateImageRequest{
+		Model:              params.Model,
+		Prompt:             params.Prompt,
+		UcPrompt:           params.UcPrompt,
+		Num:                1,
+		InitImageURL:       params.InitImageURL,
+		InitWidth:          params.InitWidth,
+		InitHeight:         params.InitHeight,
+		CreativityDegree:   params.CreativityDegree,
+		StyleDecoration:    parseStringList(params.StyleDecoration),
+		Character:          parseStringList(params.Character),
+		StyleModel:         params.StyleModel,
+		Pattern:            params.Pattern,
+		PretreatmentMethod: params.PretreatmentMethod,
+		Steps:              params.Steps,
+		Cfg:                int(math.Round(params.Cfg)),
+		Seed:               params.Seed,
+		ClipSkip:           params.ClipSkip,
+	}
+	if c.Model == 0 {
+		c.Model = params.ModelCode
+	}
+	if params.SamplerIndex != "" {
+		sampler, err := strconv.Atoi(strings.TrimSpace(params.SamplerIndex))
+		if err != nil {
+			return nil, fmt.Errorf("strconv.Atoi: sampler_index: %q, error: %w", params.SamplerIndex, err)
+		}
+		c.SamplerIndex = sampler
+	}
+	fusions, err := parseModelFusions(params.ModelFusion)
+	if err != nil {
+		return nil, fmt.Errorf("parseModelFusions: model_fusion: %q, error: %w", params.ModelFusion, err)
+	}
+	c.ModelFusion = fusions
+	c.Width, c.Height = parseSize(params.Size)
+	artworkUrl := params.ArtworkURL
+	if info != nil {
+		if c.Width == 0 || c.Height == 0 {
+			c.Width, c.Height = info.Width, info.Height
+		}
+		if c.Seed == "" {
+			c.Seed = info.Seed
+		}
+		if artworkUrl == "" {
+			artworkUrl = info.PictureUrl
+		}
+	}
+	override.artworkUrl = artworkUrl
+	if override.strength != nil && override.artworkUrl == "" {
+		return nil, fmt.Errorf("RemixCreateParams: key: %s, error: no artwork url for strength", params.Key)
+	}
+	override.applyImage(c)
+	return c, nil
+}
+
+func (r *remixOverride) applyImage(c *CreateImageRequest) {
+	if r.prompt != nil {
+		c.Prompt = *r.prompt
+	}
+	if r.ucPrompt != nil {
+		c.UcPrompt = *r.ucPrompt
+	}
+	if r.seed != nil {
+		c.Seed = *r.seed
+	}
+	if r.width > 0 && r.height > 0 {
+		c.Width, c.Height = r.width, r.height
+	}
+	if r.num > 0 {
+		c.Num = r.num
+	}
+	if r.strength != nil {
+		c.InitImageURL, c.CreativityDegree = r.artworkUrl, *r.strength
+		// size of init image is the size of the artwork, it is filled by the server or dimension probing
+		c.InitWidth, c.InitHeight = 0, 0
+	}
+}
+
+// RemixImageInfoPro build CreateImageProRequest from ImageInfoPro
+func RemixImageInfoPro(info *ImageInfoPro, options ...RemixOption) *CreateImageProRequest {
+	return remixImageInfoPro(info, newRemixOverride(options))
+}
+
+func remixImageInfoPro(info *ImageInfoPro, r *remixOverride) *CreateImageProRequest {
+	c := NewCreateImageProRequest(info.ModelCode, info.Prompt, info.Width, info.Height)
+	c.SupersizeMultiple, c.PrefineMultiple = info.SupersizeMultiple, info.PrefineMultiple
+	c.OptionParam = OptionParam{
+		ModelFusion: r.fusionKeys(info.ModelCode, info.OptionInfo.ModelFusion),
+		Character:   info.OptionInfo.Character,
+	}
+	advanced := info.AdvancedInfo
+	c.AdvancedParam = AdvancedParam{
+		UcPrompt:     advanced.UcPrompt,
+		RestoreFaces: advanced.RestoreFaces,
+		Tilling:      advanced.Tilling,
+		Seed:         advanced.Seed,
+		VaeFile:      advanced.VaeFile,
+		Cfg:          int(math.Round(advanced.Cfg)),
+		SamplerSteps: advanced.SamplerSteps,
+		SamplerIndex: advanced.SamplerIndex,
+		ClipSkip:     advanced.ClipSkip,
+		Ensd:         advanced.Ensd,
+	}
+	resizeMode, _ := strconv.Atoi(info.ImgToImgInfo.ResizeMode)
+	c.ImgToImgParam = ImgToImgParam{
+		InitImageUrl:     info.ImgToImgInfo.InitImageUrl,
+		CreativityDegree: info.ImgToImgInfo.CreativityDegree,
+		ResizeMode:       resizeMode,
+	}
+	for _, n := range info.ControlNetInfo {
+		c.ControlNetParams = append(c.ControlNetParams, ControlNetParam{
+			Type: n.Type, Preprocessor: n.Preprocessor, Model: n.Model, ControlWeight: n.ControlWeight,
+			StartingControlStep: n.StartingControlStep, EndingControlStep: n.EndingControlStep,
+			ControlMode: n.ControlMode, ImageUrl: n.ImageUrl, ImageWidth: n.ImageWidth, ImageHeight: n.ImageHeight,
+			MaskUrl: n.MaskUrl, ProcessorRes: n.ProcessorRes, ThresholdA: n.ThresholdA, ThresholdB: n.ThresholdB,
+			ResizeMode: n.ResizeMode, PixelPerfect: n.PixelPerfect,
+		})
+	}
+	inpainting := info.InpaintingPlugin
+	c.InpaintingPluginDTO = InpaintingPluginDTO{
+		MaskZoneImageUrl:      inpainting.MaskZoneImageUrl,
+		MaskBlur:              inpainting.MaskBlur,
+		InpaintingFill:        inpainting.InpaintingFill,
+		InpaintingMaskInvert:  inpainting.InpaintingMaskInvert,
+		InpaintFullResPadding: inpainting.InpaintFullResPadding,
+		InpaintFullRes:        inpainting.InpaintFullRes,
+	}
+	c.TiledDiffusionDTO = TiledDiffusionDTO{Enabled: info.TiledDiffusion.Enabled, DrawBackground: info.TiledDiffusion.DrawBackground}
+	for _, b := range info.TiledDiffusion.BboxControlStates {
+		characters := make([]string, 0, len(b.OptionInfo.CharacterOptions))
+		for _, character := range b.OptionInfo.CharacterOptions {
+			characters = append(characters, character.Key)
+		}
+		c.TiledDiffusionDTO.BboxControlStates = append(c.TiledDiffusionDTO.BboxControlStates, BboxControlState{
+			Enabled: b.Enabled, X: b.X, Y: b.Y, W: b.W, H: b.H, Prompt: b.Prompt, NegPrompt: b.NegPrompt,
+			BlendMode: b.BlendMode, Seed: b.Seed,
+			OptionParam: OptionParam{ModelFusion: r.fusionKeys(info.ModelCode, b.OptionInfo.ModelFusion), Character: characters},
+		})
+	}
+	face := info.FaceEditor
+	c.FaceEditorDTO = FaceEditorDTO{
+		Enabled: face.Enabled, UseMinimalArea: face.UseMinimalArea, AffectedAreas: face.AffectedAreas,
+		MaskSize: face.MaskSize, MaskBlur: face.MaskBlur, MaxFaceCount: face.MaxFaceCount,
+		Confidence: face.Confidence, FaceMargin: face.FaceMargin, FaceSize: face.FaceSize,
+		IgnoreLargerFaces: face.IgnoreLargerFaces, Strength1: face.Strength1,
+		ApplyInsideMaskOnly: face.ApplyInsideMaskOnly, Strength2: face.Strength2, PromptForFace: face.PromptForFace,
+	}
+	upscale := info.UltimateUpscale
+	c.UltimateUpscaleDTO = UltimateUpscaleDTO{
+		Enabled: upscale.Enabled, TargetSizeType: upscale.TargetSizeType, UpscalerIndex: upscale.UpscalerIndex,
+		RedrawMode: upscale.RedrawMode, TileWidth: upscale.TileWidth, TileHeight: upscale.TileHeight,
+		MaskBlur: upscale.MaskBlur, SeamsFixType: upscale.SeamsFixType, SeamsFixWidth: upscale.SeamsFixWidth,
+		SeamsFixDenoise: upscale.SeamsFixDenoise, SeamsFixPadding: upscale.SeamsFixPadding,
+	}
+	for _, a := range info.Adetailer {
+		c.AdetailerDTOS = append(c.AdetailerDTOS, ADetailer{AdModel: a.AdModel, AdNegativePrompt: a.AdNegativePrompt, AdPrompt: a.AdPrompt})
+	}
+	if r.prompt != nil {
+		c.Prompt = *r.prompt
+	}
+	if r.ucPrompt != nil {
+		c.AdvancedParam.UcPrompt = *r.ucPrompt
+	}
+	if r.seed != nil {
+		c.AdvancedParam.Seed = *r.seed
+	}
+	if r.width > 0 && r.height > 0 {
+		c.Width, c.Height = r.width, r.height
+	}
+	if r.num > 0 {
+		c.BatchCount = r.num
+	}
+	if r.strength != nil {
+		c.ImgToImgParam.InitImageUrl, c.ImgToImgParam.CreativityDegree = r.artworkUrl, *r.strength
+	}
+	return c
+}
+
+// fusionKeys convert model fusion names to keys, names without match in catalog are used as keys
+func (r *remixOverride) fusionKeys(code ModelCode, infos []ModelFusionInfo) []ModelFusion {
+	var fusions []ModelFusion
+	var options []FusionOption
+	if r.catalog != nil && len(infos) > 0 {
+		options = r.catalog.CompatibleFusions(code, true)
+	}
+	for _, info := range infos {
+		key := info.Name
+		for _, option := range options {
+			if option.Name == info.Name {
+				key = option.Key
+				break
+			}
+		}
+		fusions = append(fusions, ModelFusion{Key: key, Weight: info.Weight})
+	}
+	return fusions
+}
+
+var sizePattern = regexp.MustCompile(`(\d+)\D+(\d+)`)
+
+// parseSize parse size such as 512x768 or 512*768
+func parseSize(size string) (int, int) {
+	m := sizePattern.FindStringSubmatch(size)
+	if m == nil {
+		return 0, 0
+	}
+	width, _ := strconv.Atoi(m[1])
+	height, _ := strconv.Atoi(m[2])
+	return width, height
+}
+
+// parseStringList parse JSON array of strings or comma separated strings
+func parseStringList(s string) []string {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil
+	}
+	var list []string
+	if strings.HasPrefix(s, "[") && json.Unmarshal([]byte(s), &list) == nil {
+		return list
+	}
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+// parseModelFusions parse model fusion of CreateParams,
+// which is JSON array of {"key","weight"}, or comma separated key:weight
+func parseModelFusions(s string) ([]ModelFusion, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, nil
+	}
+	if strings.HasPrefix(s, "[") {
+		var fusions []ModelFusion
+		if err := json.Unmarshal([]byte(s), &fusions); err != nil {
+			return nil, fmt.Errorf("json.Unmarshal: %w", err)
+		}
+		return fusions, nil
+	}
+	var fusions []ModelFusion
+	for _, item := range parseStringList(s) {
+		key, weight, found := strings.Cut(item, ":")
+		fusion := ModelFusion{Key: strings.TrimSpace(key), Weight: 1}
+		if found {
+			w, err := strconv.ParseFloat(strings.TrimSpace(weight), 64)
+			if err != nil {
+				return nil, fmt.Errorf("strconv.ParseFloat: weight: %q, error: %w", weight, err)
+			}
+			fusion.Weight = w
+		}
+		fusions = append(fusions, fusion)
+	}
+	return fusions, nil
+}
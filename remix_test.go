@@ -0,0 +1,52 @@
+package wujiesdk_test
+
+// @Title        remix_test.go
+// @Description  test remix helpers against fake server
+// @Create       XdpCs 2026-10-20 12:30
+// @Update       XdpCs 2026-10-20 12:30
+
+import (
+	"context"
+	"testing"
+
+	"github.com/XdpCs/wujiesdk"
+	"github.com/XdpCs/wujiesdk/wujietest"
+)
+
+func TestRemixImageProCatalog(t *testing.T) {
+	s := wujietest.NewServer(wujietest.WithTiming(0, 0))
+	defer s.Close()
+	c := s.Caller()
+	ctx := context.Background()
+	catalog := wujiesdk.NewCatalog(c)
+	if err := catalog.Refresh(ctx); err != nil {
+		t.Fatalf("Refresh error: %v", err)
+	}
+	if fusions := catalog.CompatibleFusions(1, true); len(fusions) != 1 || fusions[0].Key != "mf_anime" {
+		t.Fatalf("fusions of pro model 1: %+v, want mf_anime of model 1 of the same version", fusions)
+	}
+
+	req := wujiesdk.NewCreateImageProRequest(1, "a cat", 512, 512)
+	req.OptionParam.ModelFusion = []wujiesdk.ModelFusion{{Key: "mf_anime", Weight: 0.6}}
+	_, results, err := c.CreateImagePro(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// ImageInfoPro carries names of model fusions, the catalog maps them back to keys
+	remix, err := c.RemixImagePro(ctx, results[0].Key, wujiesdk.WithRemixCatalog(catalog))
+	if err != nil {
+		t.Fatalf("RemixImagePro error: %v", err)
+	}
+	if f := remix.OptionParam.ModelFusion; len(f) != 1 || f[0].Key != "mf_anime" || f[0].Weight != 0.6 {
+		t.Errorf("model fusions: %+v, want mf_anime with weight 0.6", f)
+	}
+
+	remix, err = c.RemixImagePro(ctx, results[0].Key)
+	if err != nil {
+		t.Fatalf("RemixImagePro error: %v", err)
+	}
+	if f := remix.OptionParam.ModelFusion; len(f) != 1 || f[0].Key != "动漫" {
+		t.Errorf("model fusions without catalog: %+v, want the name as key", f)
+	}
+}
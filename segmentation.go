@@ -0,0 +1,326 @@
+package wujiesdk
+
+// @Title        segmentation.go
+// @Description  point prompts of segment anything
+// @Create       XdpCs 2026-10-19 13:10
+// @Update       XdpCs 2026-10-20 13:00
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// UnmarshalJSON decode point from {"x":1,"y":2}, [1,2], "1,2" or a string holding one of them,
+// lab info returns positive points as objects and negative points as strings
+func (p *Point) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 || bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+	switch data[0] {
+	case '"':
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return fmt.Errorf("json.Unmarshal: point: %s, error: %w", data, err)
+		}
+		return p.parseString(s)
+	case '[':
+		var axes []pointAxis
+		if err := json.Unmarshal(data, &axes); err != nil {
+			return fmt.Errorf("json.Unmarshal: point: %s, error: %w", data, err)
+		}
+		if len(axes) != 2 {
+			return fmt.Errorf("point: %s, error: want 2 coordinates, got %d", data, len(axes))
+		}
+		p.X, p.Y = int(axes[0]), int(axes[1])
+		return nil
+	}
+	var obj struct {
+		X pointAxis `json:"x"`
+		Y pointAxis `json:"y"`
+	}
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return fmt.Errorf("json.Unmarshal: point: %s, error: %w", data, err)
+	}
+	p.X, p.Y = int(obj.X), int(obj.Y)
+	return nil
+}
+
+func (p *Point) parseString(s string) error {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil
+	}
+	if s[0] == '{' || s[0] == '[' {
+		return p.UnmarshalJSON([]byte(s))
+	}
+	s = strings.Trim(s, "()")
+	fields := strings.FieldsFunc(s, func(r rune) bool {
+		return r == ',' || r == ' ' || r == ';'
+	})
+	if len(fields) != 2 {
+		return fmt.Errorf("point: %q, error: want 2 coordinates, got %d", s, len(fields))
+	}
+	x, err := parseAxis(fields[0])
+	if err != nil {
+		return fmt.Errorf("parseAxis: point: %q, error: %w", s, err)
+	}
+	y, err := parseAxis(fields[1])
+	if err != nil {
+		return fmt.Errorf("parseAxis: point: %q, error: %w", s, err)
+	}
+	p.X, p.Y = x, y
+	return nil
+}
+
+func (p Point) String() string {
+	return fmt.Sprintf("(%d,%d)", p.X, p.Y)
+}
+
+// pointAxis is one coordinate which may be number or string
+type pointAxis int
+
+func (a *pointAxis) UnmarshalJSON(data []byte) error {
+	s := strings.Trim(string(bytes.TrimSpace(data)), `"`)
+	if s == "" || s == "null" {
+		*a = 0
+		return nil
+	}
+	v, err := parseAxis(s)
+	if err != nil {
+		return err
+	}
+	*a = pointAxis(v)
+	return nil
+}
+
+func parseAxis(s string) (int, error) {
+	f, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil {
+		return 0, fmt.Errorf("strconv.ParseFloat: %w", err)
+	}
+	return int(math.Round(f)), nil
+}
+
+// RelativePoint is a point whose coordinates are fractions of image's width and height in [0, 1]
+type RelativePoint struct {
+	X float64
+	Y float64
+}
+
+// Point convert relative point to pixel point of image with width and height
+func (r RelativePoint) Point(width, height int) (Point, error) {
+	if width <= 0 || height <= 0 {
+		return Point{}, fmt.Errorf("image size: %dx%d, error: width and height must be positive", width, height)
+	}
+	if r.X < 0 || r.X > 1 || r.Y < 0 || r.Y > 1 {
+		return Point{}, fmt.Errorf("relative point: %+v, error: coordinates must be in [0, 1]", r)
+	}
+	return Point{X: scaleAxis(r.X, width), Y: scaleAxis(r.Y, height)}, nil
+}
+
+// scaleAxis scale fraction to pixel in [0, size-1]
+func scaleAxis(fraction float64, size int) int {
+	v := int(math.Round(fraction * float64(size-1)))
+	if v < 0 {
+		return 0
+	}
+	if v >= size {
+		return size - 1
+	}
+	return v
+}
+
+// NormalizePoints convert relative points to pixel points of image with width and height
+func NormalizePoints(width, height int, points ...RelativePoint) ([]Point, error) {
+	result := make([]Point, 0, len(points))
+	for i, r := range points {
+		p, err := r.Point(width, height)
+		if err != nil {
+			return nil, fmt.Errorf("r.Point: index: %d, error: %w", i, err)
+		}
+		result = append(result, p)
+	}
+	return result, nil
+}
+
+// Box is a rectangle from top left (X1, Y1) to bottom right (X2, Y2) in pixels,
+// open api has no box prompt, so box only helps to choose points of a region
+type Box struct {
+	X1 int
+	Y1 int
+	X2 int
+	Y2 int
+}
+
+// Center get the center of box
+func (b Box) Center() Point {
+	return Point{X: (b.X1 + b.X2) / 2, Y: (b.Y1 + b.Y2) / 2}
+}
+
+// Contains report whether p is inside box
+func (b Box) Contains(p Point) bool {
+	return p.X >= b.X1 && p.X <= b.X2 && p.Y >= b.Y1 && p.Y <= b.Y2
+}
+
+// Validate check box is inside image with width and height
+func (b Box) Validate(width, height int) error {
+	var v ValidationErrors
+	b.validate(&v, "box", width, height)
+	return v.err()
+}
+
+func (b Box) validate(v *ValidationErrors, field string, width, height int) {
+	if b.X1 >= b.X2 || b.Y1 >= b.Y2 {
+		v.add(field, "%+v must have x1 < x2 and y1 < y2", b)
+	}
+	validatePoint(v, field+".top_left", Point{X: b.X1, Y: b.Y1}, width, height)
+	validatePoint(v, field+".bottom_right", Point{X: b.X2, Y: b.Y2}, width, height)
+}
+
+// RelativeBox is a box whose coordinates are fractions of image's width and height in [0, 1]
+type RelativeBox struct {
+	X1 float64
+	Y1 float64
+	X2 float64
+	Y2 float64
+}
+
+// Box convert relative box to pixel box of image with width and height
+func (r RelativeBox) Box(width, height int) (Box, error) {
+	topLeft, err := RelativePoint{X: r.X1, Y: r.Y1}.Point(width, height)
+	if err != nil {
+		return Box{}, fmt.Errorf("RelativePoint.Point: top left, error: %w", err)
+	}
+	bottomRight, err := RelativePoint{X: r.X2, Y: r.Y2}.Point(width, height)
+	if err != nil {
+		return Box{}, fmt.Errorf("RelativePoint.Point: bottom right, error: %w", err)
+	}
+	b := Box{X1: topLeft.X, Y1: topLeft.Y, X2: bottomRight.X, Y2: bottomRight.Y}
+	if err := b.Validate(width, height); err != nil {
+		return Box{}, err
+	}
+	return b, nil
+}
+
+func validatePoint(v *ValidationErrors, field string, p Point, width, height int) {
+	if p.X < 0 || p.Y < 0 {
+		v.add(field, "%v must not be negative", p)
+		return
+	}
+	if width > 0 && height > 0 && (p.X >= width || p.Y >= height) {
+		v.add(field, "%v is outside of image %dx%d", p, width, height)
+	}
+}
+
+// SegmentationOption is option of NewCreateSegmentationRequest
+type SegmentationOption func(*CreateSegmentationInput)
+
+// NewCreateSegmentationRequest new create segmentation request
+func NewCreateSegmentationRequest(imageUrl string, modelCode ModelCode, options ...SegmentationOption) *CreateSegmentationRequest {
+	input := &CreateSegmentationInput{
+		ImageUrl:  imageUrl,
+		ModelCode: modelCode,
+	}
+	for _, option := range options {
+		option(input)
+	}
+	return &CreateSegmentationRequest{Input: input}
+}
+
+// WithSegmentationPositivePoints add points which belong to the segment
+func WithSegmentationPositivePoints(points ...Point) SegmentationOption {
+	return func(c *CreateSegmentationInput) {
+		c.PositivePoints = append(c.PositivePoints, points...)
+	}
+}
+
+// WithSegmentationNegativePoints add points which do not belong to the segment
+func WithSegmentationNegativePoints(points ...Point) SegmentationOption {
+	return func(c *CreateSegmentationInput) {
+		c.NegativePoints = append(c.NegativePoints, points...)
+	}
+}
+
+// WithSegmentationPrompt set text prompt of the segment
+func WithSegmentationPrompt(prompt string) SegmentationOption {
+	return func(c *CreateSegmentationInput) {
+		c.Prompt = prompt
+	}
+}
+
+// WithSegmentationThreshold set threshold
+func WithSegmentationThreshold(threshold int) SegmentationOption {
+	return func(c *CreateSegmentationInput) {
+		c.Threshold = threshold
+	}
+}
+
+// WithSegmentationNotifyUrl set notify url
+func WithSegmentationNotifyUrl(notifyUrl string) SegmentationOption {
+	return func(c *CreateSegmentationInput) {
+		c.NotifyUrl = notifyUrl
+	}
+}
+
+// Validate check structural rules of CreateSegmentationRequest
+func (c *CreateSegmentationRequest) Validate() error {
+	var v ValidationErrors
+	if c.Input == nil {
+		v.add("input", "is required")
+		return v.err()
+	}
+	c.Input.validate(&v, 0, 0)
+	return v.err()
+}
+
+// ValidateSize check points are inside image with width and height
+func (c *CreateSegmentationInput) ValidateSize(width, height int) error {
+	var v ValidationErrors
+	if width <= 0 || height <= 0 {
+		v.add("width", "image size %dx%d must be positive", width, height)
+		return v.err()
+	}
+	c.validate(&v, width, height)
+	return v.err()
+}
+
+func (c *CreateSegmentationInput) validate(v *ValidationErrors, width, height int) {
+	if c.ImageUrl == "" && c.ImageUrlParam.Url == "" {
+		v.add("imageUrl", "is required")
+	}
+	if len(c.PositivePoints) == 0 && strings.TrimSpace(c.Prompt) == "" {
+		v.add("positivePoints", "positivePoints or prompt is required")
+	}
+	v.nonNegative("threshold", float64(c.Threshold))
+	for i, p := range c.PositivePoints {
+		validatePoint(v, fmt.Sprintf("positivePoints[%d]", i), p, width, height)
+	}
+	for i, p := range c.NegativePoints {
+		validatePoint(v, fmt.Sprintf("negativePoints[%d]", i), p, width, height)
+	}
+}
+
+// CreateSegmentationRequest new create segmentation request from segment info for refinement,
+// options add points or change prompt based on the previous segmentation
+func (s *SegmentInfo) CreateSegmentationRequest(options ...SegmentationOption) *CreateSegmentationRequest {
+	input := &CreateSegmentationInput{
+		ImageUrl:       s.ImageUrl,
+		ImageUrls:      append([]string(nil), s.ImageUrls...),
+		ModelCode:      s.ModelCode,
+		PositivePoints: append([]Point(nil), s.PositivePoints...),
+		NegativePoints: append([]Point(nil), s.NegativePoints...),
+		Threshold:      s.Threshold,
+	}
+	if prompt, ok := s.Prompt.(string); ok {
+		input.Prompt = prompt
+	}
+	for _, option := range options {
+		option(input)
+	}
+	return &CreateSegmentationRequest{Input: input}
+}
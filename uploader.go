@@ -0,0 +1,467 @@
+package wujiesdk
+
+// @Title        uploader.go
+// @Description  upload local inputs to public urls by S3 compatible storage or local file server
+// @Create       XdpCs 2026-10-19 18:25
+// @Update       XdpCs 2026-10-20 12:10
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Uploader turns content into a public url which wujie's server can fetch
+type Uploader interface {
+	// Upload upload r as name, name is a hint of file name and extension
+	Upload(ctx context.Context, name string, r io.Reader) (string, error)
+}
+
+// UploaderFunc is a func as Uploader
+type UploaderFunc func(ctx context.Context, name string, r io.Reader) (string, error)
+
+func (f UploaderFunc) Upload(ctx context.Context, name string, r io.Reader) (string, error) {
+	return f(ctx, name, r)
+}
+
+// UploadFile upload local file by u
+func UploadFile(ctx context.Context, u Uploader, filePath string) (string, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return "", fmt.Errorf("os.Open: path: %s, error: %w", filePath, err)
+	}
+	defer func() { _ = f.Close() }()
+	return u.Upload(ctx, filepath.Base(filePath), f)
+}
+
+// UploadBytes upload data by u
+func UploadBytes(ctx context.Context, u Uploader, name string, data []byte) (string, error) {
+	return u.Upload(ctx, name, bytes.NewReader(data))
+}
+
+// objectName name content by its sha256, so same content is uploaded to the same object
+func objectName(prefix, name string, data []byte) string {
+	sum := sha256.Sum256(data)
+	ext := strings.ToLower(path.Ext(name))
+	if ext == "" {
+		if exts, err := mime.ExtensionsByType(http.DetectContentType(data)); err == nil && len(exts) > 0 {
+			ext = exts[0]
+		}
+	}
+	return prefix + hex.EncodeToString(sum[:16]) + ext
+}
+
+// S3Uploader uploads to S3 compatible storage by PutObject signed with AWS Signature Version 4
+type S3Uploader struct {
+	Endpoint        string // such as https://s3.us-east-1.amazonaws.com or https://oss-cn-hangzhou.aliyuncs.com
+	Region          string
+	Bucket          string
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+	Prefix          string // prefix of object key, such as wujie/
+	ACL             string // x-amz-acl, such as public-read, empty means bucket default
+	PublicBaseURL   string // base of returned url, object url of endpoint is used when it is empty
+	PathStyle       bool   // use endpoint/bucket/key instead of bucket.endpoint/key
+	HTTPClient      *http.Client
+	Now             func() time.Time
+}
+
+// NewS3Uploader new S3 uploader with path style addressing
+func NewS3Uploader(endpoint, region, bucket, accessKeyID, secretAccessKey string) *S3Uploader {
+	return &S3Uploader{
+		Endpoint:        endpoint,
+		Region:          region,
+		Bucket:          bucket,
+		AccessKeyID:     accessKeyID,
+		SecretAccessKey: secretAccessKey,
+		PathStyle:       true,
+		HTTPClient:      http.DefaultClient,
+		Now:             time.Now,
+	}
+}
+
+// Upload put r to bucket, object key is Prefix and sha256 of content
+func (s *S3Uploader) Upload(ctx context.Context, name string, r io.Reader) (string, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return "", fmt.Errorf("io.ReadAll: name: %s, error: %w", name, err)
+	}
+	key := objectName(s.Prefix, name, data)
+	objectURL, err := s.objectURL(key)
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, objectURL.String(), bytes.NewReader(data))
+	if err != nil {
+		return "", fmt.Errorf("http.NewRequestWithContext: url: %v, new request error: %w", objectURL, err)
+	}
+	contentType := mime.TypeByExtension(path.Ext(key))
+	if contentType == "" {
+		contentType = http.DetectContentType(data)
+	}
+	req.Header.Set(ContentType, contentType)
+	if s.ACL != "" {
+		req.Header.Set("x-amz-acl", s.ACL)
+	}
+	s.sign(req, data)
+
+	client := s.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("client.Do: url: %v, error: %w", objectURL, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode < http.StatusOK || resp.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return "", fmt.Errorf("S3Uploader.Upload: http status code: %d, %s, body: %s", resp.StatusCode,
+			http.StatusText(resp.StatusCode), body)
+	}
+	if s.PublicBaseURL != "" {
+		return strings.TrimSuffix(s.PublicBaseURL, "/") + "/" + key, nil
+	}
+	return objectURL.String(), nil
+}
+
+func (s *S3Uploader) objectURL(key string) (*url.URL, error) {
+	u, err := url.Parse(s.Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("url.Parse: endpoint: %s, error: %w", s.Endpoint, err)
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("S3Uploader.objectURL: endpoint: %s, error: scheme and host are required", s.Endpoint)
+	}
+	if s.PathStyle {
+		u.Path = "/" + s.Bucket + "/" + key
+	} else {
+		u.Host = s.Bucket + "." + u.Host
+		u.Path = "/" + key
+	}
+	return u, nil
+}
+
+// sign sign req with AWS Signature Version 4
+func (s *S3Uploader) sign(req *http.Request, payload []byte) {
+	now := time.Now
+	if s.Now != nil {
+		now = s.Now
+	}
+	t := now().UTC()
+	amzDate := t.Format("20060102T150405Z")
+	date := t.Format("20060102")
+	payloadHash := sha256Hex(payload)
+
+	req.Header.Set("x-amz-date", amzDate)
+	req.Header.Set("x-amz-content-sha256", payloadHash)
+	if s.SessionToken != "" {
+		req.Header.Set("x-amz-security-token", s.SessionToken)
+	}
+
+	headers := map[string]string{"host": req.URL.Host}
+	for k, v := range req.Header {
+		headers[strings.ToLower(k)] = strings.TrimSpace(strings.Join(v, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for k := range headers {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, k := range names {
+		canonicalHeaders.WriteString(k + ":" + headers[k] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		s3EscapePath(req.URL.Path),
+		req.URL.Query().Encode(),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	scope := date + "/" + s.Region + "/s3/aws4_request"
+	stringToSign := strings.Join([]string{"AWS4-HMAC-SHA256", amzDate, scope, sha256Hex([]byte(canonicalRequest))}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+s.SecretAccessKey), date)
+	key = hmacSHA256(key, s.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.AccessKeyID, scope, signedHeaders, signature))
+}
+
+func s3EscapePath(p string) string {
+	segments := strings.Split(p, "/")
+	for i, segment := range segments {
+		segments[i] = strings.ReplaceAll(url.PathEscape(segment), "+", "%2B")
+	}
+	return strings.Join(segments, "/")
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+// LocalUploader writes content into Dir and serves it by http for development,
+// BaseURL must be reachable by wujie's server, such as a tunnel to the listening address
+type LocalUploader struct {
+	Dir     string
+	BaseURL string
+}
+
+// NewLocalUploader new local uploader
+func NewLocalUploader(dir, baseURL string) *LocalUploader {
+	return &LocalUploader{Dir: dir, BaseURL: baseURL}
+}
+
+// Upload write r into Dir, file name is sha256 of content
+func (l *LocalUploader) Upload(_ context.Context, name string, r io.Reader) (string, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return "", fmt.Errorf("io.ReadAll: name: %s, error: %w", name, err)
+	}
+	if err := os.MkdirAll(l.Dir, 0o755); err != nil {
+		return "", fmt.Errorf("os.MkdirAll: dir: %s, error: %w", l.Dir, err)
+	}
+	fileName := objectName("", name, data)
+	if err := os.WriteFile(filepath.Join(l.Dir, fileName), data, 0o644); err != nil {
+		return "", fmt.Errorf("os.WriteFile: name: %s, error: %w", fileName, err)
+	}
+	return strings.TrimSuffix(l.BaseURL, "/") + "/" + fileName, nil
+}
+
+// Handler serve files of Dir
+func (l *LocalUploader) Handler() http.Handler {
+	return http.FileServer(http.Dir(l.Dir))
+}
+
+// ListenAndServe serve files of Dir on addr until ctx is done
+func (l *LocalUploader) ListenAndServe(ctx context.Context, addr string) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("net.Listen: addr: %s, error: %w", addr, err)
+	}
+	server := &http.Server{Handler: l.Handler(), ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		<-ctx.Done()
+		_ = server.Close()
+	}()
+	if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("server.Serve: addr: %s, error: %w", addr, err)
+	}
+	return ctx.Err()
+}
+
+// IsLocalInput report whether input is a file:// url of a local file, bare paths are not local inputs,
+// so a url field set to a path such as /etc/passwd is never uploaded by mistake
+func IsLocalInput(input string) bool {
+	return strings.HasPrefix(strings.ToLower(input), "file://")
+}
+
+// UploadHook is a CallerHook which uploads local files in input fields of create requests before the call,
+// and replaces them with uploaded urls, uploaded urls are cached by path, modification time and size in the hook
+type UploadHook struct {
+	Uploader Uploader
+	// Root is the directory local inputs must be in, when it is set, file:// urls out of Root are rejected
+	// and bare paths of regular files in Root are local inputs too, set it when requests come from untrusted input
+	Root  string
+	mu    sync.Mutex
+	cache map[string]uploadedFile
+}
+
+// uploadedFile is an uploaded file, it is uploaded again once its modification time or size changes
+type uploadedFile struct {
+	modTime int64
+	size    int64
+	url     string
+}
+
+// NewUploadHook new upload hook
+func NewUploadHook(u Uploader) *UploadHook {
+	return &UploadHook{Uploader: u, cache: make(map[string]uploadedFile)}
+}
+
+// BeforeCall upload local inputs of call.Request, key lists of queries are left as they are
+func (u *UploadHook) BeforeCall(ctx context.Context, call *Call) error {
+	if _, ok := call.Request.([]string); ok && call.Router != ImageBatchCheckWujieRouter {
+		return nil
+	}
+	if err := u.ResolveInputs(ctx, call.Request); err != nil {
+		return fmt.Errorf("u.ResolveInputs: %s: %w", call.Name, err)
+	}
+	return nil
+}
+
+// AfterCall do nothing
+func (u *UploadHook) AfterCall(_ context.Context, _ *Call, _ WujieCode, _ interface{}, _ error) {
+}
+
+// ResolveInputs upload local inputs of request in place, request is a pointer of create request,
+// or []string of ImageBatchCheck
+func (u *UploadHook) ResolveInputs(ctx context.Context, request interface{}) error {
+	var fields []*string
+	switch r := request.(type) {
+	case *CreateImageRequest:
+		fields = append(fields, &r.InitImageURL)
+	case *PostSuperSizeRequest:
+		fields = append(fields, &r.URL)
+	case *YouthifyRequest:
+		fields = append(fields, &r.ImageURL)
+	case *CreateImageProRequest:
+		fields = append(fields, &r.ImgToImgParam.InitImageUrl, &r.InpaintingPluginDTO.MaskZoneImageUrl)
+		for i := range r.ControlNetParams {
+			fields = append(fields, &r.ControlNetParams[i].ImageUrl, &r.ControlNetParams[i].MaskUrl)
+		}
+	case *CreateAvatarRequest:
+		for i := range r.TrainImageUrlList {
+			fields = append(fields, &r.TrainImageUrlList[i])
+		}
+	case []string:
+		for i := range r {
+			fields = append(fields, &r[i])
+		}
+	case *CreateSpellAnalysisRequest:
+		fields = append(fields, &r.ImageURL)
+	case *CreateVideoRequest:
+		fields = append(fields, &r.OriginVideoUrl)
+	case *CreateCameraRequest:
+		if r.TemplateCreateParam != nil {
+			fields = append(fields, &r.TemplateCreateParam.TemplateUrl)
+		}
+	case *CreateSegmentationRequest:
+		if r.Input != nil {
+			fields = append(fields, &r.Input.ImageUrl)
+		}
+	case *CreateInfiniteZoomRequest:
+		if r.Input != nil {
+			fields = append(fields, &r.Input.InitImageUrl, &r.Input.ExitImageUrl)
+		}
+	case *CreateVectorStudioRequest:
+		if r.Input != nil {
+			fields = append(fields, &r.Input.InitImageParam.Url)
+		}
+	case *CreateSVDRequest:
+		fields = append(fields, &r.InitImageUrl)
+	case *CreateMidjourneyRequest:
+		fields = append(fields, &r.InitImageUrl)
+	case *CreateFluxRequest:
+		fields = append(fields, &r.InitImageUrl)
+	}
+	for _, field := range fields {
+		filePath, ok, err := u.localPath(*field)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+		uploaded, err := u.upload(ctx, filePath)
+		if err != nil {
+			return err
+		}
+		*field = uploaded
+	}
+	return nil
+}
+
+// localPath get file path of input, ok is false when input is not a local input
+func (u *UploadHook) localPath(input string) (filePath string, ok bool, err error) {
+	if IsLocalInput(input) {
+		filePath = input[len("file://"):]
+		if u.Root != "" && !u.inRoot(filePath) {
+			return "", false, fmt.Errorf("UploadHook.localPath: path: %s, root: %s, error: path is out of root", filePath, u.Root)
+		}
+		return filePath, true, nil
+	}
+	if u.Root == "" || input == "" || strings.Contains(input, "://") || strings.HasPrefix(strings.ToLower(input), "data:") {
+		return "", false, nil
+	}
+	info, err := os.Stat(input)
+	if err != nil || !info.Mode().IsRegular() || !u.inRoot(input) {
+		return "", false, nil
+	}
+	return input, true, nil
+}
+
+// inRoot report whether filePath is in Root after symbolic links are resolved
+func (u *UploadHook) inRoot(filePath string) bool {
+	root, err := resolvePath(u.Root)
+	if err != nil {
+		return false
+	}
+	resolved, err := resolvePath(filePath)
+	if err != nil {
+		return false
+	}
+	rel, err := filepath.Rel(root, resolved)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// resolvePath get absolute path of p without symbolic links
+func resolvePath(p string) (string, error) {
+	resolved, err := filepath.EvalSymlinks(p)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Abs(resolved)
+}
+
+func (u *UploadHook) upload(ctx context.Context, filePath string) (string, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return "", fmt.Errorf("os.Open: path: %s, error: %w", filePath, err)
+	}
+	defer func() { _ = f.Close() }()
+	info, err := f.Stat()
+	if err != nil {
+		return "", fmt.Errorf("f.Stat: path: %s, error: %w", filePath, err)
+	}
+	file := uploadedFile{modTime: info.ModTime().UnixNano(), size: info.Size()}
+	u.mu.Lock()
+	cached, ok := u.cache[filePath]
+	u.mu.Unlock()
+	if ok && cached.modTime == file.modTime && cached.size == file.size {
+		return cached.url, nil
+	}
+	// upload without the lock so that uploads of different files run at the same time
+	file.url, err = u.Uploader.Upload(ctx, filepath.Base(filePath), f)
+	if err != nil {
+		return "", fmt.Errorf("u.Uploader.Upload: path: %s, error: %w", filePath, err)
+	}
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if u.cache == nil {
+		u.cache = make(map[string]uploadedFile)
+	}
+	u.cache[filePath] = file
+	return file.url, nil
+}
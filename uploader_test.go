@@ -0,0 +1,113 @@
+package wujiesdk_test
+
+// @Title        uploader_test.go
+// @Description  test upload hook against fake server
+// @Create       XdpCs 2026-10-20 11:50
+// @Update       XdpCs 2026-10-20 12:10
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/XdpCs/wujiesdk"
+	"github.com/XdpCs/wujiesdk/wujietest"
+)
+
+// recordUploader record names of uploads, every upload gets a new url
+type recordUploader struct {
+	mu      sync.Mutex
+	uploads []string
+}
+
+func (r *recordUploader) Upload(_ context.Context, name string, _ io.Reader) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.uploads = append(r.uploads, name)
+	return fmt.Sprintf("https://cdn.example.com/%d/%s", len(r.uploads), name), nil
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestUploadHook(t *testing.T) {
+	s := wujietest.NewServer(wujietest.WithTiming(0, 0))
+	defer s.Close()
+	c := s.Caller()
+	u := &recordUploader{}
+	root := t.TempDir()
+	hook := wujiesdk.NewUploadHook(u)
+	hook.Root = root
+	c.AddCallerHooks(hook)
+	ctx := context.Background()
+	path := filepath.Join(root, "cat.png")
+	writeFile(t, path, "cat")
+	outside := filepath.Join(t.TempDir(), "secret.txt")
+	writeFile(t, outside, "secret")
+
+	// keys of queries are never uploaded, even if a file has the same name
+	_, _, _ = c.GeneratingInfo(ctx, []string{path})
+	if len(u.uploads) != 0 {
+		t.Fatalf("uploads: %q after GeneratingInfo, want none", u.uploads)
+	}
+
+	// bare paths are local inputs only in root
+	urls := []string{path, outside, "https://example.com/dog.png"}
+	_, _, _ = c.ImageBatchCheck(ctx, urls)
+	if len(u.uploads) != 1 || urls[0] == path || urls[1] != outside || urls[2] != "https://example.com/dog.png" {
+		t.Fatalf("uploads: %q, urls: %q after ImageBatchCheck", u.uploads, urls)
+	}
+
+	r := &wujiesdk.CreateImageRequest{Model: 1, Prompt: "a cat", Num: 1, InitImageURL: "file://" + path}
+	if _, _, err := c.CreateImage(ctx, r); err != nil {
+		t.Fatalf("CreateImage error: %v", err)
+	}
+	if len(u.uploads) != 1 || r.InitImageURL != urls[0] {
+		t.Errorf("uploads: %q, init image url: %s, want the cached url %s", u.uploads, r.InitImageURL, urls[0])
+	}
+
+	// a changed file is uploaded again
+	writeFile(t, path, "another cat")
+	r.InitImageURL = "file://" + path
+	if _, _, err := c.CreateImage(ctx, r); err != nil {
+		t.Fatalf("CreateImage error: %v", err)
+	}
+	if len(u.uploads) != 2 || r.InitImageURL == urls[0] {
+		t.Errorf("uploads: %q, init image url: %s after the file changed, want a new upload", u.uploads, r.InitImageURL)
+	}
+
+	created := s.Calls(wujiesdk.CreateImageWujieRouter)
+	r.InitImageURL = "file://" + outside
+	if _, _, err := c.CreateImage(ctx, r); err == nil {
+		t.Error("CreateImage of a file out of root succeeded")
+	}
+	if len(u.uploads) != 2 || s.Calls(wujiesdk.CreateImageWujieRouter) != created {
+		t.Errorf("uploads: %q, file out of root was sent", u.uploads)
+	}
+}
+
+func TestUploadHookWithoutRoot(t *testing.T) {
+	s := wujietest.NewServer(wujietest.WithTiming(0, 0))
+	defer s.Close()
+	c := s.Caller()
+	u := &recordUploader{}
+	c.AddCallerHooks(wujiesdk.NewUploadHook(u))
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "cat.png")
+	writeFile(t, path, "cat")
+
+	// only file:// urls are local inputs without root
+	urls := []string{path, "file://" + path}
+	_, _, _ = c.ImageBatchCheck(ctx, urls)
+	if len(u.uploads) != 1 || urls[0] != path || urls[1] == "file://"+path {
+		t.Errorf("uploads: %q, urls: %q after ImageBatchCheck", u.uploads, urls)
+	}
+}
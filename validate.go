@@ -0,0 +1,531 @@
+package wujiesdk
+
+// @Title        validate.go
+// @Description  validate create request before submission
+// @Create       XdpCs 2026-10-19 11:20
+// @Update       XdpCs 2026-10-20 13:30
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/patrickmn/go-cache"
+)
+
+// limits of create request
+const (
+	MinCreateImageNum    = 1
+	MaxCreateImageNum    = 4
+	MinModelFusionWeight = 0
+	MaxModelFusionWeight = 1
+	MaxPercentage        = 100
+	MaxMjChaos           = 100
+	MaxMjStylize         = 1000
+	MaxMjStyleWeight     = 1000
+	MaxMjCharacterWeight = 100
+)
+
+// ValidationError is the error of one field of request
+type ValidationError struct {
+	Field  string
+	Reason string
+}
+
+func (v *ValidationError) Error() string {
+	return fmt.Sprintf("%s: %s", v.Field, v.Reason)
+}
+
+// ValidationErrors is all validation errors of request
+type ValidationErrors []*ValidationError
+
+func (v ValidationErrors) Error() string {
+	messages := make([]string, 0, len(v))
+	for _, e := range v {
+		messages = append(messages, e.Error())
+	}
+	return "invalid request: " + strings.Join(messages, "; ")
+}
+
+func (v *ValidationErrors) add(field, format string, a ...interface{}) {
+	*v = append(*v, &ValidationError{Field: field, Reason: fmt.Sprintf(format, a...)})
+}
+
+func (v ValidationErrors) err() error {
+	if len(v) == 0 {
+		return nil
+	}
+	return v
+}
+
+func (v *ValidationErrors) required(field, value string) {
+	if strings.TrimSpace(value) == "" {
+		v.add(field, "is required")
+	}
+}
+
+func (v *ValidationErrors) intRange(field string, value, min, max int) {
+	if value < min || value > max {
+		v.add(field, "%d is out of range [%d, %d]", value, min, max)
+	}
+}
+
+func (v *ValidationErrors) floatRange(field string, value, min, max float64) {
+	if value < min || value > max {
+		v.add(field, "%v is out of range [%v, %v]", value, min, max)
+	}
+}
+
+func (v *ValidationErrors) nonNegative(field string, value float64) {
+	if value < 0 {
+		v.add(field, "%v must not be negative", value)
+	}
+}
+
+func (v *ValidationErrors) size(widthField, heightField string, width, height int) {
+	v.nonNegative(widthField, float64(width))
+	v.nonNegative(heightField, float64(height))
+	if (width == 0) != (height == 0) {
+		v.add(widthField, "%s and %s must be set together", widthField, heightField)
+	}
+}
+
+func (v *ValidationErrors) modelFusion(field string, index int, key string, weight float64) {
+	name := fmt.Sprintf("%s[%d]", field, index)
+	v.required(name+".key", key)
+	v.floatRange(name+".weight", weight, MinModelFusionWeight, MaxModelFusionWeight)
+}
+
+// Validate check structural rules of CreateImageRequest
+func (c *CreateImageRequest) Validate() error {
+	var v ValidationErrors
+	v.required("prompt", c.Prompt)
+	v.intRange("num", c.Num, MinCreateImageNum, MaxCreateImageNum)
+	v.size("width", "height", c.Width, c.Height)
+	v.size("init_width", "init_height", c.InitWidth, c.InitHeight)
+	v.intRange("creativity_degree", c.CreativityDegree, 0, MaxPercentage)
+	v.intRange("init_image_similarity", c.InitImageSimilarity, 0, MaxPercentage)
+	v.nonNegative("super_size_multiple", c.SuperSizeMultiple)
+	v.nonNegative("prefine_multiple", c.PrefineMultiple)
+	v.nonNegative("steps", float64(c.Steps))
+	v.nonNegative("cfg", float64(c.Cfg))
+	v.nonNegative("accelerate_times", float64(c.AccelerateTimes))
+	for i, m := range c.ModelFusion {
+		v.modelFusion("model_fusion", i, m.Key, m.Weight)
+	}
+	if c.MjParam != nil {
+		c.MjParam.validate(&v)
+	}
+	return v.err()
+}
+
+func (m *MjParam) validate(v *ValidationErrors) {
+	v.intRange("mj_param.chaos", m.Chaos, 0, MaxMjChaos)
+	v.intRange("mj_param.stylize", m.Stylize, 0, MaxMjStylize)
+	v.intRange("mj_param.sw", m.Sw, 0, MaxMjStyleWeight)
+	v.intRange("mj_param.cw", m.Cw, 0, MaxMjCharacterWeight)
+}
+
+// Validate check structural rules of CreateImageProRequest
+func (c *CreateImageProRequest) Validate() error {
+	var v ValidationErrors
+	v.required("prompt", c.Prompt)
+	if c.Width <= 0 || c.Height <= 0 {
+		v.add("width", "width and height are required")
+	}
+	v.intRange("batch_count", c.BatchCount, MinCreateImageNum, MaxCreateImageNum)
+	v.nonNegative("supersize_multiple", c.SupersizeMultiple)
+	v.nonNegative("prefine_multiple", c.PrefineMultiple)
+	for i, m := range c.OptionParam.ModelFusion {
+		v.modelFusion("option_param.model_fusion", i, m.Key, m.Weight)
+	}
+	v.nonNegative("advanced_param.cfg", float64(c.AdvancedParam.Cfg))
+	v.nonNegative("advanced_param.sampler_steps", float64(c.AdvancedParam.SamplerSteps))
+	v.intRange("img_to_img_param.creativity_degree", c.ImgToImgParam.CreativityDegree, 0, MaxPercentage)
+	for i, p := range c.ControlNetParams {
+		name := fmt.Sprintf("control_net_params[%d]", i)
+		v.required(name+".image_url", p.ImageUrl)
+		v.nonNegative(name+".control_weight", float64(p.ControlWeight))
+		v.intRange(name+".starting_control_step", p.StartingControlStep, 0, MaxPercentage)
+		v.intRange(name+".ending_control_step", p.EndingControlStep, 0, MaxPercentage)
+		if p.StartingControlStep > p.EndingControlStep {
+			v.add(name+".starting_control_step", "%d is after ending_control_step %d", p.StartingControlStep, p.EndingControlStep)
+		}
+		v.size(name+".image_width", name+".image_height", p.ImageWidth, p.ImageHeight)
+		v.nonNegative(name+".threshold_a", float64(p.ThresholdA))
+		v.nonNegative(name+".threshold_b", float64(p.ThresholdB))
+	}
+	return v.err()
+}
+
+// Validate check structural rules of CreateMidjourneyRequest
+func (c *CreateMidjourneyRequest) Validate() error {
+	var v ValidationErrors
+	v.required("prompt", c.Prompt)
+	v.intRange("num", c.Num, MinCreateImageNum, MaxCreateImageNum)
+	v.size("width", "height", c.Width, c.Height)
+	v.size("init_width", "init_height", c.InitWidth, c.InitHeight)
+	v.intRange("creativity_degree", c.CreativityDegree, 0, MaxPercentage)
+	v.nonNegative("cfg", c.Cfg)
+	if c.MjParam != nil {
+		c.MjParam.validate(&v)
+	}
+	return v.err()
+}
+
+// Validate check structural rules of CreateFluxRequest
+func (c *CreateFluxRequest) Validate() error {
+	var v ValidationErrors
+	v.required("prompt", c.Prompt)
+	v.intRange("num", c.Num, MinCreateImageNum, MaxCreateImageNum)
+	v.size("width", "height", c.Width, c.Height)
+	v.size("init_width", "init_height", c.InitWidth, c.InitHeight)
+	v.intRange("creativity_degree", c.CreativityDegree, 0, MaxPercentage)
+	v.nonNegative("cfg", c.Cfg)
+	v.nonNegative("accelerate_times", float64(c.AccelerateTimes))
+	for i, m := range c.ModelFusion {
+		if m == nil {
+			v.add(fmt.Sprintf("model_fusion[%d]", i), "must not be nil")
+			continue
+		}
+		v.modelFusion("model_fusion", i, m.Key, m.Weight)
+	}
+	return v.err()
+}
+
+// Validate check structural rules of CreateSVDRequest
+func (c *CreateSVDRequest) Validate() error {
+	var v ValidationErrors
+	v.required("init_image_url", c.InitImageUrl)
+	if c.Duration <= 0 {
+		v.add("duration", "%d must be positive", c.Duration)
+	}
+	v.nonNegative("motion_amplitude", float64(c.MotionAmplitude))
+	v.floatRange("noise_intensity", c.NoiseIntensity, 0, 1)
+	switch c.CameraMoveType {
+	case "", CameraDefault, CameraMoveUp, CameraMoveDown, CameraMoveLeft, CameraMoveRight, CameraZoomIn, CameraZoomOut:
+	default:
+		v.add("camera_move_type", "unknown camera move type %q", c.CameraMoveType)
+	}
+	return v.err()
+}
+
+// Validate check structural rules of CreateVideoRequest
+func (c *CreateVideoRequest) Validate() error {
+	var v ValidationErrors
+	v.required("origin_video_url", c.OriginVideoUrl)
+	if c.VideoDuration <= 0 {
+		v.add("video_duration", "%d must be positive", c.VideoDuration)
+	}
+	switch VideoQueueType(c.QueueType) {
+	case 0, NormalVideoQueueType, NightVideoQueueType:
+	default:
+		v.add("queue_type", "unknown queue type %d", c.QueueType)
+	}
+	return v.err()
+}
+
+// ModelCapability is what a model supports, built from model base info and DefaultResourceModelData
+type ModelCapability struct {
+	ModelCode    ModelCode
+	ModelVersion string
+	Resource     *DefaultResourceModelData
+}
+
+// SupportsResolution report whether width x height is one of resolutions of the model
+func (m *ModelCapability) SupportsResolution(width, height int) bool {
+	_, ok := m.resolution(width, height)
+	return ok
+}
+
+type resolutionCapability struct {
+	superSizeMultiples []float64
+	prefineMultiples   []float64
+}
+
+func (m *ModelCapability) hasResolutions() bool {
+	if m.Resource == nil {
+		return false
+	}
+	menu := m.Resource.CreateOptionMenu
+	return len(menu.Resolution) > 0 || len(menu.ResolutionNew.ResolutionList) > 0
+}
+
+func (m *ModelCapability) resolution(width, height int) (*resolutionCapability, bool) {
+	if m.Resource == nil {
+		return nil, false
+	}
+	menu := m.Resource.CreateOptionMenu
+	for _, r := range menu.Resolution {
+		if r.Width == width && r.Height == height {
+			superSizeMultiples := r.SuperSizeMultiples
+			if len(superSizeMultiples) == 0 && r.SuperSizeMultiple > 0 {
+				superSizeMultiples = []float64{r.SuperSizeMultiple}
+			}
+			return &resolutionCapability{superSizeMultiples: superSizeMultiples, prefineMultiples: r.PrefineMultiples}, true
+		}
+	}
+	for _, r := range menu.ResolutionNew.ResolutionList {
+		if r.Width == width && r.Height == height {
+			res := &resolutionCapability{}
+			if r.SuperSizeMultiple > 0 {
+				res.superSizeMultiples = []float64{r.SuperSizeMultiple}
+			}
+			if r.PrefineMultiples > 0 {
+				res.prefineMultiples = []float64{r.PrefineMultiples}
+			}
+			return res, true
+		}
+	}
+	return nil, false
+}
+
+func (m *ModelCapability) supportsVersion(versions []string) bool {
+	if m.ModelVersion == "" || len(versions) == 0 {
+		return true
+	}
+	for _, version := range versions {
+		if version == m.ModelVersion {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *ModelCapability) validateSize(v *ValidationErrors, width, height int, superSize, prefine float64) {
+	if width == 0 || height == 0 || !m.hasResolutions() {
+		return
+	}
+	r, ok := m.resolution(width, height)
+	if !ok {
+		v.add("width", "%dx%d is not supported by model %d", width, height, m.ModelCode)
+		return
+	}
+	if superSize > 0 && len(r.superSizeMultiples) > 0 && !containsFloat(r.superSizeMultiples, superSize) {
+		v.add("super_size_multiple", "%v is not one of %v", superSize, r.superSizeMultiples)
+	}
+	if prefine > 0 && len(r.prefineMultiples) > 0 && !containsFloat(r.prefineMultiples, prefine) {
+		v.add("prefine_multiple", "%v is not one of %v", prefine, r.prefineMultiples)
+	}
+}
+
+func (m *ModelCapability) validateCharacters(v *ValidationErrors, field string, keys []string) {
+	if m.Resource == nil || len(keys) == 0 {
+		return
+	}
+	for i, key := range keys {
+		found := false
+		for _, character := range m.Resource.CreateOptionMenu.Character {
+			if character.Key != key {
+				continue
+			}
+			found = true
+			if !m.supportsVersion(character.SupportModelVersions) {
+				v.add(fmt.Sprintf("%s[%d]", field, i), "%s does not support model version %s", key, m.ModelVersion)
+			}
+		}
+		if !found {
+			v.add(fmt.Sprintf("%s[%d]", field, i), "unknown character %s", key)
+		}
+	}
+}
+
+func (m *ModelCapability) validateModelFusions(v *ValidationErrors, field string, keys []string) {
+	if m.Resource == nil || len(keys) == 0 {
+		return
+	}
+	for i, key := range keys {
+		found := false
+		for _, fusion := range m.Resource.CreateOptionMenu.ModelFusion {
+			if fusion.Key != key {
+				continue
+			}
+			found = true
+			if !m.supportsVersion(fusion.SupportModelVersions) {
+				v.add(fmt.Sprintf("%s[%d]", field, i), "%s does not support model version %s", key, m.ModelVersion)
+			}
+		}
+		if !found {
+			v.add(fmt.Sprintf("%s[%d]", field, i), "unknown model fusion %s", key)
+		}
+	}
+}
+
+func containsFloat(values []float64, value float64) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateCapability check CreateImageRequest against what the model supports
+func (c *CreateImageRequest) ValidateCapability(m *ModelCapability) error {
+	var v ValidationErrors
+	m.validateSize(&v, c.Width, c.Height, c.SuperSizeMultiple, c.PrefineMultiple)
+	m.validateCharacters(&v, "character", c.Character)
+	keys := make([]string, 0, len(c.ModelFusion))
+	for _, f := range c.ModelFusion {
+		keys = append(keys, f.Key)
+	}
+	m.validateModelFusions(&v, "model_fusion", keys)
+	return v.err()
+}
+
+// ValidateCapability check CreateImageProRequest against what the model supports
+func (c *CreateImageProRequest) ValidateCapability(m *ModelCapability) error {
+	var v ValidationErrors
+	m.validateSize(&v, c.Width, c.Height, c.SupersizeMultiple, c.PrefineMultiple)
+	m.validateCharacters(&v, "option_param.character", c.OptionParam.Character)
+	keys := make([]string, 0, len(c.OptionParam.ModelFusion))
+	for _, f := range c.OptionParam.ModelFusion {
+		keys = append(keys, f.Key)
+	}
+	m.validateModelFusions(&v, "option_param.model_fusion", keys)
+	return v.err()
+}
+
+// ValidateCapability check CreateMidjourneyRequest against what the model supports
+func (c *CreateMidjourneyRequest) ValidateCapability(m *ModelCapability) error {
+	var v ValidationErrors
+	m.validateSize(&v, c.Width, c.Height, 0, 0)
+	return v.err()
+}
+
+// ValidateCapability check CreateFluxRequest against what the model supports
+func (c *CreateFluxRequest) ValidateCapability(m *ModelCapability) error {
+	var v ValidationErrors
+	m.validateSize(&v, c.Width, c.Height, 0, 0)
+	keys := make([]string, 0, len(c.ModelFusion))
+	for _, f := range c.ModelFusion {
+		if f != nil {
+			keys = append(keys, f.Key)
+		}
+	}
+	m.validateModelFusions(&v, "model_fusion", keys)
+	return v.err()
+}
+
+// Validator is the request which can check its structural rules
+type Validator interface {
+	Validate() error
+}
+
+// CapabilityValidator is the request which can be checked against what the model supports
+type CapabilityValidator interface {
+	ValidateCapability(m *ModelCapability) error
+}
+
+// RequestValidator is a CallerHook which validates create request before submission
+type RequestValidator struct {
+	// Caller fetches ModelCapability, only structural rules are checked if it is nil
+	Caller *Caller
+	// Catalog provides ModelCapability without fetching when it is not nil
+	Catalog *Catalog
+	cache   *cache.Cache
+	once    sync.Once
+}
+
+// NewRequestValidator new request validator, model capabilities are fetched by c and cached
+func NewRequestValidator(c *Caller) *RequestValidator {
+	return &RequestValidator{Caller: c}
+}
+
+// BeforeCall validate the request of call
+func (r *RequestValidator) BeforeCall(ctx context.Context, call *Call) error {
+	v, ok := call.Request.(Validator)
+	if !ok {
+		return nil
+	}
+	if err := v.Validate(); err != nil {
+		return err
+	}
+	cv, ok := call.Request.(CapabilityValidator)
+	if !ok || r.Caller == nil {
+		return nil
+	}
+	model, pro, ok := requestModelCode(call.Request)
+	if !ok {
+		return nil
+	}
+	m, err := r.ModelCapability(ctx, model, pro)
+	if err != nil {
+		// capability is best effort, the server still validates the request
+		r.Caller.Client.WriteLog(LogWarn, "RequestValidator: model: %d, fetch model capability error: %v\n", model, err)
+		return nil
+	}
+	return cv.ValidateCapability(m)
+}
+
+// AfterCall do nothing
+func (r *RequestValidator) AfterCall(_ context.Context, _ *Call, _ WujieCode, _ interface{}, _ error) {
+}
+
+// ModelCapability get the capability of model, pro selects model base infos of pro
+func (r *RequestValidator) ModelCapability(ctx context.Context, model ModelCode, pro bool) (*ModelCapability, error) {
+	if r.Catalog != nil {
+		if m, ok := r.Catalog.Capability(model, pro); ok {
+			return m, nil
+		}
+	}
+	cacheKey := fmt.Sprintf("%d:%v", model, pro)
+	if m, found := r.capabilities().Get(cacheKey); found {
+		return m.(*ModelCapability), nil
+	}
+	m := &ModelCapability{ModelCode: model}
+	if pro {
+		_, infos, err := r.Caller.ModelBaseInfosPro(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("r.Caller.ModelBaseInfosPro: %w", err)
+		}
+		for _, info := range infos {
+			if info.ModelCode == model {
+				m.ModelVersion = info.ModelVersion
+			}
+		}
+	} else {
+		_, infos, err := r.Caller.ModelBaseInfos(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("r.Caller.ModelBaseInfos: %w", err)
+		}
+		for _, info := range infos {
+			if info.ModelCode == model {
+				m.ModelVersion = info.ModelVersion
+			}
+		}
+		// DefaultResourceModel serves models of ModelBaseInfos only
+		if _, m.Resource, err = r.Caller.DefaultResourceModel(ctx, model); err != nil {
+			return nil, fmt.Errorf("r.Caller.DefaultResourceModel: %w", err)
+		}
+	}
+	r.capabilities().Set(cacheKey, m, cache.DefaultExpiration)
+	return m, nil
+}
+
+// requestModelCode get model of request and whether it is a pro model, ok is false for requests without model
+func requestModelCode(req interface{}) (model ModelCode, pro bool, ok bool) {
+	switch r := req.(type) {
+	case *CreateImageRequest:
+		return r.Model, false, true
+	case *ImagePriceInfoRequest:
+		return r.Model, false, true
+	case *CreateImageProRequest:
+		return r.ModelCode, true, true
+	case *CreateMidjourneyRequest:
+		return r.Model, false, true
+	case *CreateFluxRequest:
+		return r.Model, false, true
+	}
+	return 0, false, false
+}
+
+// capabilities get cache of model capabilities, it is created on first use so that the zero value is usable
+func (r *RequestValidator) capabilities() *cache.Cache {
+	r.once.Do(func() {
+		r.cache = cache.New(30*time.Minute, 10*time.Minute)
+	})
+	return r.cache
+}
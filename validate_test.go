@@ -0,0 +1,59 @@
+package wujiesdk_test
+
+// @Title        validate_test.go
+// @Description  test request validator against fake server
+// @Create       XdpCs 2026-10-20 11:50
+// @Update       XdpCs 2026-10-20 13:30
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/XdpCs/wujiesdk"
+	"github.com/XdpCs/wujiesdk/wujietest"
+)
+
+func TestRequestValidator(t *testing.T) {
+	s := wujietest.NewServer(wujietest.WithTiming(0, 0))
+	defer s.Close()
+	c := s.Caller()
+	v := wujiesdk.NewRequestValidator(c)
+	c.AddCallerHooks(v)
+	ctx := context.Background()
+
+	unknown := wujiesdk.CreateImageRequest{Model: 1, Prompt: "a cat", Num: 1, Character: []string{"unknown"}}
+	var verrs wujiesdk.ValidationErrors
+	if _, _, err := c.CreateImage(ctx, &unknown); !errors.As(err, &verrs) {
+		t.Errorf("CreateImage error: %v, want ValidationErrors", err)
+	}
+	if _, _, err := c.ImagePriceInfo(ctx, &wujiesdk.ImagePriceInfoRequest{CreateImageRequest: unknown}); !errors.As(err, &verrs) {
+		t.Errorf("ImagePriceInfo error: %v, want ValidationErrors", err)
+	}
+	if n := s.Calls(wujiesdk.CreateImageWujieRouter) + s.Calls(wujiesdk.ImagePriceInfoWujieRouter); n != 0 {
+		t.Errorf("server got %d calls of rejected requests", n)
+	}
+
+	known := unknown
+	known.Character = []string{"ch_girl"}
+	if _, _, err := c.CreateImage(ctx, &known); err != nil {
+		t.Errorf("CreateImage error: %v", err)
+	}
+
+	// default resources of model 1 belong to the normal model, they do not limit pro model 1
+	pro := wujiesdk.NewCreateImageProRequest(1, "a cat", 512, 512)
+	pro.OptionParam.Character = []string{"unknown"}
+	if err := v.BeforeCall(ctx, &wujiesdk.Call{Name: "CreateImagePro", Router: wujiesdk.CreateImageProWujieRouter, Request: pro}); err != nil {
+		t.Errorf("BeforeCall of pro error: %v", err)
+	}
+}
+
+func TestRequestValidatorZeroValue(t *testing.T) {
+	s := wujietest.NewServer(wujietest.WithTiming(0, 0))
+	defer s.Close()
+	v := &wujiesdk.RequestValidator{Caller: s.Caller()}
+	m, err := v.ModelCapability(context.Background(), 1, false)
+	if err != nil || m.ModelVersion != "SD1.5" || m.Resource == nil {
+		t.Errorf("ModelCapability: %+v, error: %v, want SD1.5 with resource", m, err)
+	}
+}
@@ -0,0 +1,185 @@
+package wujiesdk
+
+// @Title        video_cost.go
+// @Description  estimate cost of video to video locally by price table
+// @Create       XdpCs 2026-10-19 14:55
+// @Update       XdpCs 2026-10-20 13:00
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// DefaultVideoPriceTableTTL is how long VideoCostEstimator keeps the price table
+const DefaultVideoPriceTableTTL = 30 * time.Minute
+
+// VideoCostEstimate is the estimated points of CreateVideoRequest,
+// price of the table is points of one second of video
+type VideoCostEstimate struct {
+	ModelCode     ModelCode      `json:"model_code"`
+	ModelName     string         `json:"model_name"`
+	QueueType     VideoQueueType `json:"queue_type"`
+	VideoDuration int            `json:"video_duration"`
+	Price         int            `json:"price"`
+	NightPrice    int            `json:"night_price"`
+	UnitPrice     int            `json:"unit_price"`    // price of the selected queue
+	Points        int            `json:"points"`        // UnitPrice * VideoDuration
+	NormalPoints  int            `json:"normal_points"` // points on normal queue
+	NightPoints   int            `json:"night_points"`  // points on night queue
+}
+
+// NightSaving get points saved by night queue
+func (v *VideoCostEstimate) NightSaving() int {
+	return v.NormalPoints - v.NightPoints
+}
+
+func (v *VideoCostEstimate) String() string {
+	return fmt.Sprintf("%+v", *v)
+}
+
+// EstimateVideoCost estimate points of cReq by table, zero QueueType is normal queue
+func EstimateVideoCost(table *VideoOptionMenuAndPriceTable, cReq *CreateVideoRequest) (*VideoCostEstimate, error) {
+	if table == nil {
+		return nil, fmt.Errorf("EstimateVideoCost: error: price table is nil")
+	}
+	if cReq.VideoDuration <= 0 {
+		return nil, fmt.Errorf("EstimateVideoCost: video_duration: %d, error: must be positive", cReq.VideoDuration)
+	}
+	queueType := VideoQueueType(cReq.QueueType)
+	if queueType == 0 {
+		queueType = NormalVideoQueueType
+	}
+	if queueType != NormalVideoQueueType && queueType != NightVideoQueueType {
+		return nil, fmt.Errorf("EstimateVideoCost: queue_type: %d, error: unknown queue type", cReq.QueueType)
+	}
+	v := &VideoCostEstimate{
+		ModelCode:     cReq.ModelCode,
+		QueueType:     queueType,
+		VideoDuration: cReq.VideoDuration,
+		Price:         table.PayInfoVo.Price,
+		NightPrice:    table.PayInfoVo.NightPrice,
+		NormalPoints:  table.PayInfoVo.Price * cReq.VideoDuration,
+		NightPoints:   table.PayInfoVo.NightPrice * cReq.VideoDuration,
+	}
+	if len(table.AiVideoModelOptionVos) > 0 {
+		found := false
+		for _, option := range table.AiVideoModelOptionVos {
+			if option.ModelCode == cReq.ModelCode {
+				v.ModelName, found = option.Name, true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("EstimateVideoCost: model_code: %d, error: model is not in price table", cReq.ModelCode)
+		}
+	}
+	v.UnitPrice, v.Points = v.Price, v.NormalPoints
+	if queueType == NightVideoQueueType {
+		v.UnitPrice, v.Points = v.NightPrice, v.NightPoints
+	}
+	return v, nil
+}
+
+// VideoCostEstimator estimates cost of video by cached VideoOptionMenuAndPriceTable,
+// the table is refreshed after TTL, and replaced whenever a Caller with this hook gets a new one,
+// it is a Pricer of CreateVideo and a CallerHook, it is safe for concurrent use
+type VideoCostEstimator struct {
+	Caller *Caller
+	TTL    time.Duration
+	Now    func() time.Time
+
+	mu        sync.Mutex
+	table     *VideoOptionMenuAndPriceTable
+	fetchedAt time.Time
+}
+
+// NewVideoCostEstimator new video cost estimator, ttl <= 0 uses DefaultVideoPriceTableTTL
+func NewVideoCostEstimator(c *Caller, ttl time.Duration) *VideoCostEstimator {
+	if ttl <= 0 {
+		ttl = DefaultVideoPriceTableTTL
+	}
+	return &VideoCostEstimator{Caller: c, TTL: ttl, Now: time.Now}
+}
+
+// EstimateVideoCost estimate points of cReq by cached price table
+func (v *VideoCostEstimator) EstimateVideoCost(ctx context.Context, cReq *CreateVideoRequest) (*VideoCostEstimate, error) {
+	table, err := v.PriceTable(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return EstimateVideoCost(table, cReq)
+}
+
+// PriceTable get cached price table, it is fetched when it is missing or expired
+func (v *VideoCostEstimator) PriceTable(ctx context.Context) (*VideoOptionMenuAndPriceTable, error) {
+	v.mu.Lock()
+	table, fetchedAt := v.table, v.fetchedAt
+	v.mu.Unlock()
+	if table != nil && v.now().Sub(fetchedAt) < v.TTL {
+		return table, nil
+	}
+	if err := v.Refresh(ctx); err != nil {
+		return nil, err
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.table, nil
+}
+
+// Refresh fetch price table now
+func (v *VideoCostEstimator) Refresh(ctx context.Context) error {
+	// the table is stored by AfterCall when this estimator is a hook of v.Caller
+	_, table, err := v.Caller.VideoOptionMenuAndPriceTable(ctx)
+	if err != nil {
+		return fmt.Errorf("v.Caller.VideoOptionMenuAndPriceTable: %w", err)
+	}
+	v.store(table)
+	return nil
+}
+
+// Invalidate drop cached price table
+func (v *VideoCostEstimator) Invalidate() {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.table = nil
+}
+
+// Price price CreateVideo, other calls spend nothing
+func (v *VideoCostEstimator) Price(ctx context.Context, call *Call) (Cost, error) {
+	cReq, ok := call.Request.(*CreateVideoRequest)
+	if !ok {
+		return Cost{}, nil
+	}
+	estimate, err := v.EstimateVideoCost(ctx, cReq)
+	if err != nil {
+		return Cost{}, fmt.Errorf("v.EstimateVideoCost: %w", err)
+	}
+	return Cost{Points: estimate.Points}, nil
+}
+
+// BeforeCall do nothing
+func (v *VideoCostEstimator) BeforeCall(_ context.Context, _ *Call) error {
+	return nil
+}
+
+// AfterCall keep the newest price table got by any call of VideoOptionMenuAndPriceTable
+func (v *VideoCostEstimator) AfterCall(_ context.Context, _ *Call, code WujieCode, result interface{}, err error) {
+	if table, ok := result.(*VideoOptionMenuAndPriceTable); ok && err == nil && code == OKWujieCode && table != nil {
+		v.store(table)
+	}
+}
+
+func (v *VideoCostEstimator) store(table *VideoOptionMenuAndPriceTable) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.table, v.fetchedAt = table, v.now()
+}
+
+func (v *VideoCostEstimator) now() time.Time {
+	if v.Now == nil {
+		return time.Now()
+	}
+	return v.Now()
+}
@@ -0,0 +1,263 @@
+package wujiesdk
+
+// @Title        video_scheduler.go
+// @Description  schedule video jobs to night queue when it is cheaper and meets the deadline
+// @Create       XdpCs 2026-10-19 15:30
+// @Update       XdpCs 2026-10-20 14:15
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Clock is the time source of scheduler, it is injectable for test
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+// SystemClock is Clock of time package
+type SystemClock struct{}
+
+func (SystemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (SystemClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// CostPreference is how video job trades time for points
+type CostPreference int
+
+const (
+	// CheapestCostPreference holds the job for night queue until normal queue can just meet the deadline
+	CheapestCostPreference CostPreference = iota
+	// BalancedCostPreference uses night queue when it meets the deadline and saves enough points
+	BalancedCostPreference
+	// FastestCostPreference always uses normal queue
+	FastestCostPreference
+)
+
+// default settings of VideoScheduler
+const (
+	DefaultVideoSchedulerInterval     = time.Minute
+	DefaultVideoSchedulerSafetyMargin = 5 * time.Minute
+)
+
+// VideoJob is a video job waiting for scheduling, QueueType of Request is chosen by scheduler
+type VideoJob struct {
+	Request    *CreateVideoRequest
+	Deadline   time.Time // zero means no deadline
+	Preference CostPreference
+}
+
+// ScheduledVideoJob is the handle of VideoJob in scheduler
+type ScheduledVideoJob struct {
+	VideoJob
+	QueueType   VideoQueueType
+	Reason      string
+	SubmittedAt time.Time
+	Key         string
+	Err         error
+	done        chan struct{}
+}
+
+// Done is closed when the job is submitted, failed or canceled
+func (s *ScheduledVideoJob) Done() <-chan struct{} {
+	return s.done
+}
+
+// Wait wait for submission and get the key of video
+func (s *ScheduledVideoJob) Wait(ctx context.Context) (string, error) {
+	select {
+	case <-ctx.Done():
+		return "", ctx.Err()
+	case <-s.done:
+		return s.Key, s.Err
+	}
+}
+
+// VideoQueueDecision is the decision of scheduler for one job at one moment
+type VideoQueueDecision struct {
+	Submit    bool
+	QueueType VideoQueueType
+	Reason    string
+}
+
+// DecideVideoQueue decide whether to submit job now and which queue to use,
+// FreeExpectedSeconds and NightExpectedTime of info are seconds until the job would finish on each queue
+func DecideVideoQueue(now time.Time, job *VideoJob, info *VideoModelQueueInfo, estimate *VideoCostEstimate,
+	minNightSaving int, margin time.Duration) VideoQueueDecision {
+	if job.Preference == FastestCostPreference {
+		return VideoQueueDecision{Submit: true, QueueType: NormalVideoQueueType, Reason: "fastest preference"}
+	}
+	if minNightSaving < 1 || job.Preference == CheapestCostPreference {
+		minNightSaving = 1
+	}
+	if estimate.NightSaving() < minNightSaving {
+		return VideoQueueDecision{Submit: true, QueueType: NormalVideoQueueType, Reason: "night queue does not save enough points"}
+	}
+	normalDone := now.Add(time.Duration(info.FreeExpectedSeconds)*time.Second + margin)
+	nightDone := now.Add(time.Duration(info.NightExpectedTime)*time.Second + margin)
+	if job.Deadline.IsZero() || !nightDone.After(job.Deadline) {
+		return VideoQueueDecision{Submit: true, QueueType: NightVideoQueueType, Reason: "night queue meets the deadline"}
+	}
+	if job.Preference == BalancedCostPreference {
+		return VideoQueueDecision{Submit: true, QueueType: NormalVideoQueueType, Reason: "night queue misses the deadline"}
+	}
+	if !normalDone.Before(job.Deadline) {
+		return VideoQueueDecision{Submit: true, QueueType: NormalVideoQueueType, Reason: "last moment for normal queue to meet the deadline"}
+	}
+	return VideoQueueDecision{Reason: "waiting for night queue"}
+}
+
+// VideoScheduler holds video jobs in a local queue and submits them by Caller at the right moment,
+// it checks live VideoModelQueueInfo and the price table every Interval, it is safe for concurrent use
+type VideoScheduler struct {
+	Caller         *Caller
+	Estimator      *VideoCostEstimator
+	Clock          Clock
+	Interval       time.Duration
+	SafetyMargin   time.Duration // added to expected time of queues
+	MinNightSaving int           // points night queue must save for BalancedCostPreference
+
+	mu      sync.Mutex
+	pending []*ScheduledVideoJob
+	wake    chan struct{}
+}
+
+// NewVideoScheduler new video scheduler, estimator prices the jobs and may be shared with CallerPricer
+func NewVideoScheduler(c *Caller, estimator *VideoCostEstimator) *VideoScheduler {
+	if estimator == nil {
+		estimator = NewVideoCostEstimator(c, 0)
+	}
+	return &VideoScheduler{
+		Caller:       c,
+		Estimator:    estimator,
+		Clock:        SystemClock{},
+		Interval:     DefaultVideoSchedulerInterval,
+		SafetyMargin: DefaultVideoSchedulerSafetyMargin,
+		wake:         make(chan struct{}, 1),
+	}
+}
+
+// Schedule add job to the local queue
+func (v *VideoScheduler) Schedule(job VideoJob) (*ScheduledVideoJob, error) {
+	if job.Request == nil {
+		return nil, fmt.Errorf("VideoScheduler.Schedule: error: request is nil")
+	}
+	if err := job.Request.Validate(); err != nil {
+		return nil, fmt.Errorf("job.Request.Validate: %w", err)
+	}
+	s := &ScheduledVideoJob{VideoJob: job, done: make(chan struct{})}
+	v.mu.Lock()
+	v.pending = append(v.pending, s)
+	v.mu.Unlock()
+	v.notify()
+	return s, nil
+}
+
+// Cancel remove job from the local queue, it reports false when job is already submitted
+func (v *VideoScheduler) Cancel(job *ScheduledVideoJob) bool {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	for i, s := range v.pending {
+		if s == job {
+			v.pending = append(v.pending[:i], v.pending[i+1:]...)
+			s.Err = context.Canceled
+			close(s.done)
+			return true
+		}
+	}
+	return false
+}
+
+// Pending get jobs in the local queue
+func (v *VideoScheduler) Pending() []*ScheduledVideoJob {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return append([]*ScheduledVideoJob(nil), v.pending...)
+}
+
+// Run check the local queue every Interval until ctx is done
+func (v *VideoScheduler) Run(ctx context.Context) error {
+	for {
+		v.Tick(ctx)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-v.wake:
+		case <-v.Clock.After(v.Interval):
+		}
+	}
+}
+
+// Tick check every job in the local queue once and submit those whose moment has come,
+// queue info of each model is fetched once per tick, jobs of a model whose fetch failed wait for the next tick
+func (v *VideoScheduler) Tick(ctx context.Context) {
+	jobs := v.Pending()
+	if len(jobs) == 0 {
+		return
+	}
+	infos := make(map[ModelCode]*VideoModelQueueInfo)
+	for _, job := range jobs {
+		model := job.Request.ModelCode
+		info, ok := infos[model]
+		if !ok {
+			_, fetched, err := v.Caller.VideoModelQueueInfo(ctx, model)
+			if err != nil {
+				v.Caller.Client.WriteLog(LogWarn, "VideoScheduler: model: %d, get queue info error: %v\n", model, err)
+				fetched = nil
+			}
+			info, infos[model] = fetched, fetched
+		}
+		if info == nil {
+			continue
+		}
+		night := *job.Request
+		night.QueueType = int(NightVideoQueueType)
+		estimate, err := v.Estimator.EstimateVideoCost(ctx, &night)
+		if err != nil {
+			v.Caller.Client.WriteLog(LogWarn, "VideoScheduler: request: %v, estimate cost error: %v\n", job.Request, err)
+			continue
+		}
+		decision := DecideVideoQueue(v.Clock.Now(), &job.VideoJob, info, estimate, v.MinNightSaving, v.SafetyMargin)
+		if decision.Submit {
+			v.submit(ctx, job, decision)
+		}
+	}
+}
+
+func (v *VideoScheduler) submit(ctx context.Context, job *ScheduledVideoJob, decision VideoQueueDecision) {
+	if !v.remove(job) {
+		return
+	}
+	cReq := *job.Request
+	cReq.QueueType = int(decision.QueueType)
+	_, key, err := v.Caller.CreateVideo(ctx, &cReq)
+	job.QueueType, job.Reason, job.SubmittedAt = decision.QueueType, decision.Reason, v.Clock.Now()
+	job.Key, job.Err = key, err
+	close(job.done)
+}
+
+func (v *VideoScheduler) remove(job *ScheduledVideoJob) bool {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	for i, s := range v.pending {
+		if s == job {
+			v.pending = append(v.pending[:i], v.pending[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+func (v *VideoScheduler) notify() {
+	select {
+	case v.wake <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,107 @@
+package wujiesdk_test
+
+// @Title        video_scheduler_test.go
+// @Description  test night queue decision of video scheduler against fake server
+// @Create       XdpCs 2026-10-20 14:15
+// @Update       XdpCs 2026-10-20 14:15
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/XdpCs/wujiesdk"
+	"github.com/XdpCs/wujiesdk/wujietest"
+)
+
+// fixedClock is a Clock which never moves
+type fixedClock struct {
+	t time.Time
+}
+
+func (c fixedClock) Now() time.Time {
+	return c.t
+}
+
+func (c fixedClock) After(time.Duration) <-chan time.Time {
+	return make(chan time.Time)
+}
+
+func newTestVideoScheduler(t *testing.T, now time.Time) (*wujietest.Server, *wujiesdk.VideoScheduler) {
+	t.Helper()
+	// the fake server expects 60 seconds on normal queue and 120 seconds on night queue
+	s := wujietest.NewServer(wujietest.WithTiming(0, time.Minute), wujietest.WithClock(func() time.Time { return now }))
+	v := wujiesdk.NewVideoScheduler(s.Caller(), nil)
+	v.Clock = fixedClock{t: now}
+	v.SafetyMargin = 0
+	return s, v
+}
+
+func scheduleVideo(t *testing.T, v *wujiesdk.VideoScheduler, deadline time.Time) *wujiesdk.ScheduledVideoJob {
+	t.Helper()
+	job, err := v.Schedule(wujiesdk.VideoJob{
+		Request:    &wujiesdk.CreateVideoRequest{ModelCode: 1, OriginVideoUrl: "https://example.com/a.mp4", VideoDuration: 10},
+		Deadline:   deadline,
+		Preference: wujiesdk.CheapestCostPreference,
+	})
+	if err != nil {
+		t.Fatalf("Schedule error: %v", err)
+	}
+	return job
+}
+
+func TestVideoSchedulerNightQueue(t *testing.T) {
+	now := time.Date(2026, 10, 20, 22, 0, 0, 0, time.Local)
+	s, v := newTestVideoScheduler(t, now)
+	defer s.Close()
+	night := scheduleVideo(t, v, now.Add(10*time.Minute))
+	waiting := scheduleVideo(t, v, now.Add(90*time.Second))
+	last := scheduleVideo(t, v, now.Add(time.Minute))
+
+	v.Tick(context.Background())
+	if n := s.Calls(wujiesdk.VideoModelQueueInfoWujieRouter); n != 1 {
+		t.Errorf("queue info fetched %d times in one tick, want 1", n)
+	}
+	for _, c := range []struct {
+		job       *wujiesdk.ScheduledVideoJob
+		queueType wujiesdk.VideoQueueType
+	}{
+		{night, wujiesdk.NightVideoQueueType},
+		{last, wujiesdk.NormalVideoQueueType},
+	} {
+		select {
+		case <-c.job.Done():
+		default:
+			t.Fatalf("job with deadline %v is not submitted", c.job.Deadline)
+		}
+		if c.job.Err != nil || c.job.Key == "" || c.job.QueueType != c.queueType || !c.job.SubmittedAt.Equal(now) {
+			t.Errorf("submitted job: %+v, want queue type %d at %v", c.job, c.queueType, now)
+		}
+	}
+	if pending := v.Pending(); len(pending) != 1 || pending[0] != waiting {
+		t.Errorf("pending jobs: %+v, want the job waiting for night queue", pending)
+	}
+}
+
+func TestVideoSchedulerQueueInfoError(t *testing.T) {
+	now := time.Date(2026, 10, 20, 22, 0, 0, 0, time.Local)
+	s, v := newTestVideoScheduler(t, now)
+	defer s.Close()
+	scheduleVideo(t, v, now.Add(10*time.Minute))
+	scheduleVideo(t, v, now.Add(10*time.Minute))
+	s.Inject(wujiesdk.VideoModelQueueInfoWujieRouter, wujietest.Fault{Code: "20199999", Message: "busy"})
+
+	v.Tick(context.Background())
+	if n := s.Calls(wujiesdk.VideoModelQueueInfoWujieRouter); n != 1 {
+		t.Errorf("failed queue info fetched %d times in one tick, want 1", n)
+	}
+	if n := len(v.Pending()); n != 2 {
+		t.Errorf("%d pending jobs after failed fetch, want 2", n)
+	}
+
+	s.ClearFaults()
+	v.Tick(context.Background())
+	if n := len(v.Pending()); n != 0 {
+		t.Errorf("%d pending jobs after next tick, want 0", n)
+	}
+}
@@ -0,0 +1,54 @@
+package wujietest
+
+// @Title        fixtures.go
+// @Description  catalog fixtures served by fake server
+// @Create       XdpCs 2026-10-19 21:55
+// @Update       XdpCs 2026-10-20 10:45
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/XdpCs/wujiesdk"
+)
+
+//go:embed fixtures.json
+var defaultFixtures []byte
+
+// Fixtures is the catalog served by Server, it can be changed before NewServer or loaded from json
+type Fixtures struct {
+	ModelBaseInfos        []wujiesdk.ModelBaseInfo                                 `json:"model_base_infos"`
+	DefaultResources      map[wujiesdk.ModelCode]wujiesdk.DefaultResourceModelData `json:"default_resources"`
+	StyleModels           []wujiesdk.StyleModel                                    `json:"style_models"`
+	Spells                []wujiesdk.QuerySpellData                                `json:"spells"`
+	ModelBaseInfosPro     []wujiesdk.ModelBaseInfoPro                              `json:"model_base_infos_pro"`
+	ControlNetOptions     []wujiesdk.ControlNetOptionPro                           `json:"control_net_options"`
+	LabOptions            map[wujiesdk.LabOptionType][]wujiesdk.LabOption          `json:"lab_options"`
+	VideoOptionMenu       wujiesdk.VideoOptionMenuAndPriceTable                    `json:"video_option_menu"`
+	CameraTemplates       []wujiesdk.CameraTemplateOption                          `json:"camera_templates"`
+	MagicDiceThemes       []wujiesdk.MagicDiceTheme                                `json:"magic_dice_themes"`
+	MagicDice             wujiesdk.CreateMagicDiceResult                           `json:"magic_dice"`
+	AvatarDefaultResource wujiesdk.AvatarDefaultResource                           `json:"avatar_default_resource"`
+	SpellAnalysisTags     string                                                   `json:"spell_analysis_tags"`
+}
+
+// DefaultFixtures get a copy of fixtures used by NewServer
+func DefaultFixtures() *Fixtures {
+	f, err := LoadFixtures(bytes.NewReader(defaultFixtures))
+	if err != nil {
+		panic(err)
+	}
+	return f
+}
+
+// LoadFixtures load fixtures from json in the format of fixtures.json
+func LoadFixtures(r io.Reader) (*Fixtures, error) {
+	var f Fixtures
+	if err := json.NewDecoder(r).Decode(&f); err != nil {
+		return nil, fmt.Errorf("json.NewDecoder: %w", err)
+	}
+	return &f, nil
+}
@@ -0,0 +1,1093 @@
+package wujietest
+
+// @Title        handlers.go
+// @Description  handlers of every WujieRouter of fake server
+// @Create       XdpCs 2026-10-19 21:55
+// @Update       XdpCs 2026-10-20 13:00
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/XdpCs/wujiesdk"
+)
+
+func (s *Server) newRoutes() map[route]handler {
+	get, post := http.MethodGet, http.MethodPost
+	return map[route]handler{
+		// account
+		{get, wujiesdk.AvailableIntegralBalanceWujieRouter}: s.availableIntegralBalance,
+		{post, wujiesdk.ExchangePointWujieRouter}:           s.exchangePoint,
+		{post, wujiesdk.AccountBalanceProWujieRouter}:       s.accountBalancePro,
+		// common ai
+		{get, wujiesdk.DefaultResourceModelWujieRouter}:      s.defaultResourceModel,
+		{get, wujiesdk.ModelBaseInfosWujieRouter}:            s.modelBaseInfos,
+		{post, wujiesdk.ImageGeneratingInfoWujieRouter}:      s.generatingInfo,
+		{post, wujiesdk.CreateParamsWujieRouter}:             s.createParams,
+		{get, wujiesdk.ImageInfoWujieRouter}:                 s.imageInfo,
+		{get, wujiesdk.ImageModelQueueInfoWujieRouter}:       s.imageModelQueueInfo,
+		{get, wujiesdk.DefaultResourceStyleModelWujieRouter}: s.defaultResourceStyleModel,
+		{post, wujiesdk.CreateImageWujieRouter}:              s.createImage,
+		{post, wujiesdk.AccelerateImageWujieRouter}:          s.accelerateImage,
+		{post, wujiesdk.CancelImageWujieRouter}:              s.cancelImage,
+		{post, wujiesdk.ImagePriceInfoWujieRouter}:           s.imagePriceInfo,
+		{post, wujiesdk.SuperSizeWujieRouter}:                s.postSuperSize,
+		{get, wujiesdk.SuperSizeWujieRouter}:                 s.getSuperSize,
+		{post, wujiesdk.PromptOptimizeSubmitWujieRouter}:     s.promptOptimizeSubmit,
+		{get, wujiesdk.PromptOptimizeResultWujieRouter}:      s.promptOptimizeResult,
+		{post, wujiesdk.YouthifyWujieRouter}:                 s.youthify,
+		{get, wujiesdk.QuerySpellWujieRouter}:                s.querySpell,
+		{post, wujiesdk.CreateMidjourneyWujieRouter}:         s.createMidjourney,
+		{post, wujiesdk.CreateFluxWujieRouter}:               s.createFlux,
+		// pro ai
+		{post, wujiesdk.CreateImageProWujieRouter}:         s.createImagePro,
+		{post, wujiesdk.ImageGeneratingInfoProWujieRouter}: s.generatingInfoPro,
+		{get, wujiesdk.ModelBaseInfosProWujieRouter}:       s.modelBaseInfosPro,
+		{get, wujiesdk.ControlNetOptionProWujieRouter}:     s.controlNetOptionPro,
+		{get, wujiesdk.ImageInfoProWujieRouter}:            s.imageInfoPro,
+		{post, wujiesdk.LabOptionsWujieRouter}:             s.labOptions,
+		{post, wujiesdk.LabInfoWujieRouter}:                s.labInfo,
+		{post, wujiesdk.CreateSegmentationWujieRouter}:     s.createSegmentation,
+		{post, wujiesdk.CreateInfiniteZoomWujieRouter}:     s.createInfiniteZoom,
+		{post, wujiesdk.CreateVectorStudioWujieRouter}:     s.createVectorStudio,
+		{post, wujiesdk.CreateSVDWujieRouter}:              s.createSVD,
+		{get, wujiesdk.SVDInfoWujieRouter}:                 s.svdInfo,
+		// avatar
+		{post, wujiesdk.CreateAvatarArtworkWujieRouter}:  s.createAvatarArtwork,
+		{get, wujiesdk.AvatarDefaultResourceWujieRouter}: s.avatarDefaultResource,
+		{post, wujiesdk.CreateAvatarWujieRouter}:         s.createAvatar,
+		{post, wujiesdk.DeleteAvatarWujieRouter}:         s.deleteAvatar,
+		{get, wujiesdk.AvatarInfoWujieRouter}:            s.avatarInfo,
+		{post, wujiesdk.ImageBatchCheckWujieRouter}:      s.imageBatchCheck,
+		// spell analysis
+		{post, wujiesdk.CreateSpellAnalysisWujieRouter}: s.createSpellAnalysis,
+		{get, wujiesdk.SpellAnalysisInfoWujieRouter}:    s.spellAnalysisInfo,
+		// magic dice
+		{get, wujiesdk.MagicDiceThemeWujieRouter}:   s.magicDiceTheme,
+		{post, wujiesdk.CreateMagicDiceWujieRouter}: s.createMagicDice,
+		// video
+		{post, wujiesdk.CreateVideoWujieRouter}:                 s.createVideo,
+		{get, wujiesdk.VideoInfoWujieRouter}:                    s.videoInfo,
+		{get, wujiesdk.VideoOptionMenuAndPriceTableWujieRouter}: s.videoOptionMenu,
+		{get, wujiesdk.VideoModelQueueInfoWujieRouter}:          s.videoModelQueueInfo,
+		{post, wujiesdk.VideoGeneratingInfoWujieRouter}:         s.videoGeneratingInfo,
+		// camera
+		{get, wujiesdk.CameraTemplateOptionsWujieRouter}: s.cameraTemplateOptions,
+		{post, wujiesdk.CreateCameraWujieRouter}:         s.createCamera,
+		{post, wujiesdk.CameraGeneratingInfoWujieRouter}: s.cameraGeneratingInfo,
+		{get, wujiesdk.CameraInfoWujieRouter}:            s.cameraInfo,
+	}
+}
+
+// screen reject prompts containing words of WithSensitiveWords
+func (s *Server) screen(prompts ...string) error {
+	for _, prompt := range prompts {
+		for _, word := range s.sensitiveWords {
+			if word != "" && strings.Contains(prompt, word) {
+				return codeErr(wujiesdk.PromptContainsSensitiveWordsWujieCode, "sensitive word: %s", word)
+			}
+		}
+	}
+	return nil
+}
+
+func required(name, value string) error {
+	if strings.TrimSpace(value) == "" {
+		return invalidParameter("%s is required", name)
+	}
+	return nil
+}
+
+// account
+
+func (s *Server) availableIntegralBalance(_ *http.Request) (interface{}, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.settleLocked()
+	return map[string]int{"balance": s.balance}, nil
+}
+
+func (s *Server) exchangePoint(r *http.Request) (interface{}, error) {
+	var eReq wujiesdk.ExchangePointRequest
+	if err := decodeBody(r, &eReq); err != nil {
+		return nil, err
+	}
+	if err := required("exchange_target_mobile", eReq.ExchangeTargetMobile); err != nil {
+		return nil, err
+	}
+	if eReq.Amount <= 0 {
+		return nil, invalidParameter("amount: %d, must be positive", eReq.Amount)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.settleLocked()
+	return nil, s.chargeLocked(eReq.Amount, 0)
+}
+
+func (s *Server) accountBalancePro(_ *http.Request) (interface{}, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.settleLocked()
+	return map[string]int{"resourceBalance": s.proBalance}, nil
+}
+
+// common ai
+
+func (s *Server) defaultResourceModel(r *http.Request) (interface{}, error) {
+	model, err := queryInt(r, "model")
+	if err != nil {
+		return nil, err
+	}
+	data, ok := s.fixtures.DefaultResources[wujiesdk.ModelCode(model)]
+	if !ok {
+		return nil, invalidParameter("model: %d, not found", model)
+	}
+	return &data, nil
+}
+
+func (s *Server) modelBaseInfos(_ *http.Request) (interface{}, error) {
+	return s.fixtures.ModelBaseInfos, nil
+}
+
+func (s *Server) defaultResourceStyleModel(_ *http.Request) (interface{}, error) {
+	return &wujiesdk.DefaultResourceStyleModelData{StyleModels: s.fixtures.StyleModels}, nil
+}
+
+func (s *Server) querySpell(_ *http.Request) (interface{}, error) {
+	return s.fixtures.Spells, nil
+}
+
+type imageSpec struct {
+	request   interface{}
+	num       int
+	model     wujiesdk.ModelCode
+	width     int
+	height    int
+	prompt    string
+	notifyURL string
+}
+
+// createImages charge and add image jobs
+func (s *Server) createImages(spec imageSpec) (*wujiesdk.CreateImageData, error) {
+	if spec.num <= 0 {
+		spec.num = 1
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.settleLocked()
+	cost := spec.num * s.prices.Image
+	if err := s.chargeLocked(cost, 0); err != nil {
+		return nil, err
+	}
+	data := &wujiesdk.CreateImageData{ExpectedIntegralCost: cost}
+	for i := 0; i < spec.num; i++ {
+		j := s.newJobLocked(ImageJobKind, spec.request, spec.notifyURL)
+		j.cost, j.model, j.prompt = s.prices.Image, spec.model, spec.prompt
+		if spec.width > 0 && spec.height > 0 {
+			j.width, j.height = spec.width, spec.height
+		}
+		data.Keys = append(data.Keys, j.key)
+		data.Results = append(data.Results, wujiesdk.CreateImageResult{Key: j.key, ExpectedSecond: j.remaining(j.created)})
+	}
+	return data, nil
+}
+
+func (s *Server) createImage(r *http.Request) (interface{}, error) {
+	var cReq wujiesdk.CreateImageRequest
+	if err := decodeBody(r, &cReq); err != nil {
+		return nil, err
+	}
+	if cReq.Prompt == "" && cReq.InitImageURL == "" {
+		return nil, invalidParameter("prompt or init_image_url is required")
+	}
+	if err := s.screen(cReq.Prompt); err != nil {
+		return nil, err
+	}
+	return s.createImages(imageSpec{
+		request: &cReq, num: cReq.Num, model: cReq.Model, width: cReq.Width, height: cReq.Height,
+		prompt: cReq.Prompt, notifyURL: cReq.NotifyURL,
+	})
+}
+
+func (s *Server) createMidjourney(r *http.Request) (interface{}, error) {
+	var cReq wujiesdk.CreateMidjourneyRequest
+	if err := decodeBody(r, &cReq); err != nil {
+		return nil, err
+	}
+	if err := required("prompt", cReq.Prompt); err != nil {
+		return nil, err
+	}
+	if err := s.screen(cReq.Prompt); err != nil {
+		return nil, err
+	}
+	return s.createImages(imageSpec{
+		request: &cReq, num: cReq.Num, model: cReq.Model, width: cReq.Width, height: cReq.Height,
+		prompt: cReq.Prompt, notifyURL: cReq.NotifyUrl,
+	})
+}
+
+func (s *Server) createFlux(r *http.Request) (interface{}, error) {
+	var cReq wujiesdk.CreateFluxRequest
+	if err := decodeBody(r, &cReq); err != nil {
+		return nil, err
+	}
+	if err := required("prompt", cReq.Prompt); err != nil {
+		return nil, err
+	}
+	if err := s.screen(cReq.Prompt); err != nil {
+		return nil, err
+	}
+	return s.createImages(imageSpec{
+		request: &cReq, num: cReq.Num, model: cReq.Model, width: cReq.Width, height: cReq.Height,
+		prompt: cReq.Prompt, notifyURL: cReq.NotifyUrl,
+	})
+}
+
+func (s *Server) youthify(r *http.Request) (interface{}, error) {
+	var yReq wujiesdk.YouthifyRequest
+	if err := decodeBody(r, &yReq); err != nil {
+		return nil, err
+	}
+	if err := required("image_url", yReq.ImageURL); err != nil {
+		return nil, err
+	}
+	data, err := s.createImages(imageSpec{
+		request: &yReq, num: 1, width: yReq.Width, height: yReq.Height, notifyURL: yReq.NotifyURL,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &wujiesdk.YouthifyData{
+		Keys:                 strings.Join(data.Keys, ","),
+		Results:              data.Results,
+		ExpectedIntegralCost: data.ExpectedIntegralCost,
+	}, nil
+}
+
+func (s *Server) generatingInfo(r *http.Request) (interface{}, error) {
+	var keys []string
+	if err := decodeBody(r, &keys); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.settleLocked()
+	now := s.now()
+	data := &wujiesdk.GeneratingInfoData{List: []wujiesdk.ImageGeneratingInfo{}}
+	for _, key := range keys {
+		if j, ok := s.jobs[key]; ok && j.kind == ImageJobKind {
+			data.List = append(data.List, s.imageGeneratingInfoLocked(j, now))
+		}
+	}
+	return data, nil
+}
+
+func (s *Server) imageInfo(r *http.Request) (interface{}, error) {
+	key := r.URL.Query().Get("key")
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	j, err := s.jobLocked(ImageJobKind, key)
+	if err != nil {
+		return nil, err
+	}
+	g := s.imageGeneratingInfoLocked(j, s.now())
+	info := &wujiesdk.ImageInfoData{
+		Prompt:       j.prompt,
+		Model:        j.model,
+		Width:        j.width,
+		Height:       j.height,
+		Status:       g.Status,
+		PictureUrl:   g.PictureURL,
+		StartGenTime: g.StartGenTime,
+		CompleteTime: g.CompleteTime,
+		AuditInfo:    g.AuditInfo,
+		FailMessage:  g.FailMessage,
+		ModelPrompt:  j.prompt,
+		IntegralCost: j.cost,
+	}
+	if g.PictureURL != "" {
+		info.MiniPictureURL = g.PictureURL
+	}
+	if g.CompleteTime > 0 {
+		info.GenerateTime = g.CompleteTime - g.StartGenTime
+	}
+	if cReq, ok := j.request.(*wujiesdk.CreateImageRequest); ok {
+		info.UcPrompt = cReq.UcPrompt
+		info.InitImageURL = cReq.InitImageURL
+		info.InitImageSimilarity = cReq.InitImageSimilarity
+		info.CreativityDegree = cReq.CreativityDegree
+		artists := cReq.Artists
+		if cReq.Artist != "" {
+			artists = append([]string{cReq.Artist}, artists...)
+		}
+		info.Artist = strings.Join(artists, ",")
+		info.Style = strings.Join(cReq.Style, ",")
+		info.ImageType = strings.Join(cReq.ImageType, ",")
+		info.ElementMagic = cReq.ElementMagic
+		info.CharacterOptions = cReq.Character
+		for _, f := range cReq.ModelFusion {
+			info.ModelFusion = append(info.ModelFusion, wujiesdk.ModelFusionInfo{Name: s.fusionName(f.Key), Weight: f.Weight})
+		}
+		info.StyleModel = cReq.StyleModel
+		info.PretreatmentMethod = cReq.PretreatmentMethod
+		info.Steps = cReq.Steps
+		info.Cfg = float64(cReq.Cfg)
+		info.SamplerIndex = cReq.SamplerIndex
+		info.Seed = cReq.Seed
+	}
+	return info, nil
+}
+
+func (s *Server) createParams(r *http.Request) (interface{}, error) {
+	var body struct {
+		Key []string `json:"key"`
+	}
+	if err := decodeBody(r, &body); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	params := []wujiesdk.CreateParams{}
+	for _, key := range body.Key {
+		j, ok := s.jobs[key]
+		if !ok || j.kind != ImageJobKind {
+			continue
+		}
+		p := wujiesdk.CreateParams{
+			Key:               j.key,
+			Model:             j.model,
+			ModelAsString:     strconv.Itoa(int(j.model)),
+			ModelCode:         j.model,
+			ModelCodeAsString: strconv.Itoa(int(j.model)),
+			Prompt:            j.prompt,
+			Size:              fmt.Sprintf("%dx%d", j.width, j.height),
+		}
+		if j.state(now).status == wujiesdk.SuccessJobStatus {
+			p.ArtworkURL = s.assetURL(j, ".png")
+		}
+		if cReq, ok := j.request.(*wujiesdk.CreateImageRequest); ok {
+			p.UcPrompt = cReq.UcPrompt
+			p.CreativityDegree, p.CreativityDegreeAsString = cReq.CreativityDegree, strconv.Itoa(cReq.CreativityDegree)
+			p.InitImageURL = cReq.InitImageURL
+			p.InitWidth, p.InitWidthAsString = cReq.InitWidth, strconv.Itoa(cReq.InitWidth)
+			p.InitHeight, p.InitHeightAsString = cReq.InitHeight, strconv.Itoa(cReq.InitHeight)
+			p.PretreatmentMethod = cReq.PretreatmentMethod
+			p.Pattern = cReq.Pattern
+			p.StyleDecoration = strings.Join(cReq.StyleDecoration, ",")
+			p.Character = strings.Join(cReq.Character, ",")
+			p.StyleModel = cReq.StyleModel
+			p.Steps, p.StepsAsString = cReq.Steps, strconv.Itoa(cReq.Steps)
+			p.Cfg, p.CfgAsString = float64(cReq.Cfg), strconv.Itoa(cReq.Cfg)
+			p.SamplerIndex = strconv.Itoa(cReq.SamplerIndex)
+			p.Seed = cReq.Seed
+			p.ClipSkip, p.ClipSkipAsString = cReq.ClipSkip, strconv.Itoa(cReq.ClipSkip)
+		}
+		params = append(params, p)
+	}
+	return params, nil
+}
+
+func (s *Server) imageModelQueueInfo(r *http.Request) (interface{}, error) {
+	if _, err := queryInt(r, "model"); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	num := s.queueNumLocked(ImageJobKind, s.now())
+	return &wujiesdk.ImageModelQueueInfoData{
+		ExpectedSeconds: seconds((s.queue + s.generate) * time.Duration(num+1)),
+		QueueNum:        num,
+	}, nil
+}
+
+func (s *Server) cancelImage(r *http.Request) (interface{}, error) {
+	var body struct {
+		Key string `json:"key"`
+	}
+	if err := decodeBody(r, &body); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	j, err := s.jobLocked(ImageJobKind, body.Key)
+	if err != nil {
+		return nil, err
+	}
+	now := s.now()
+	if j.state(now).status != wujiesdk.QueuingJobStatus {
+		return nil, codeErr(wujiesdk.JobNotInQueueAndCannotCancelWujieCode, "key: %s, not in queue", j.key)
+	}
+	j.canceledAt = now
+	s.settleLocked()
+	return "", nil
+}
+
+func (s *Server) accelerateImage(r *http.Request) (interface{}, error) {
+	var aReq wujiesdk.AccelerateImageRequest
+	if err := decodeBody(r, &aReq); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	j, err := s.jobLocked(ImageJobKind, aReq.Key)
+	if err != nil {
+		return nil, err
+	}
+	now := s.now()
+	switch j.state(now).status {
+	case wujiesdk.QueuingJobStatus:
+		j.queue = now.Sub(j.created)
+	case wujiesdk.GeneratingJobStatus:
+		j.generate /= 2
+	default:
+		return nil, codeErr(wujiesdk.ImageStatusChange, "key: %s, finished", j.key)
+	}
+	if err := s.chargeLocked(s.prices.Accelerate, 0); err != nil {
+		return nil, err
+	}
+	return "", nil
+}
+
+func (s *Server) imagePriceInfo(r *http.Request) (interface{}, error) {
+	var iReq wujiesdk.ImagePriceInfoRequest
+	if err := decodeBody(r, &iReq); err != nil {
+		return nil, err
+	}
+	num := iReq.Num
+	if num <= 0 {
+		num = 1
+	}
+	data := &wujiesdk.ImagePriceInfoData{}
+	data.IntegralUse.IntegralUseOnCreate = num * s.prices.Image
+	data.IntegralUse.IntegralUseOnAccelerate = iReq.AccelerateTimes * s.prices.Accelerate
+	return data, nil
+}
+
+func (s *Server) postSuperSize(r *http.Request) (interface{}, error) {
+	var sReq wujiesdk.PostSuperSizeRequest
+	if err := decodeBody(r, &sReq); err != nil {
+		return nil, err
+	}
+	if err := required("url", sReq.URL); err != nil {
+		return nil, err
+	}
+	if sReq.Multiple <= 0 {
+		return nil, invalidParameter("multiple: %v, must be positive", sReq.Multiple)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.settleLocked()
+	if err := s.chargeLocked(s.prices.SuperSize, 0); err != nil {
+		return nil, err
+	}
+	j := s.newJobLocked(SuperSizeJobKind, &sReq, "")
+	j.cost = s.prices.SuperSize
+	return map[string]string{"key": j.key}, nil
+}
+
+func (s *Server) getSuperSize(r *http.Request) (interface{}, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.settleLocked()
+	now := s.now()
+	infos := []wujiesdk.SuperSizeInfo{}
+	for _, key := range r.URL.Query()["key"] {
+		if j, ok := s.jobs[key]; ok && j.kind == SuperSizeJobKind {
+			infos = append(infos, s.superSizeInfoLocked(j, now))
+		}
+	}
+	return infos, nil
+}
+
+func (s *Server) promptOptimizeSubmit(r *http.Request) (interface{}, error) {
+	var pReq wujiesdk.PromptOptimizeSubmitRequest
+	if err := decodeBody(r, &pReq); err != nil {
+		return nil, err
+	}
+	if err := required("task_id", pReq.TaskID); err != nil {
+		return nil, err
+	}
+	if err := required("original", pReq.Original); err != nil {
+		return nil, err
+	}
+	if err := s.screen(pReq.Original); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.tasks[pReq.TaskID]; ok {
+		return nil, invalidParameter("task_id: %s, duplicated", pReq.TaskID)
+	}
+	s.tasks[pReq.TaskID] = s.newJobLocked(PromptOptimizeJobKind, &pReq, pReq.CallbackURL)
+	return nil, nil
+}
+
+func (s *Server) promptOptimizeResult(r *http.Request) (interface{}, error) {
+	taskID := r.URL.Query().Get("taskId")
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	j, ok := s.tasks[taskID]
+	if !ok {
+		return nil, invalidParameter("taskId: %s, not found", taskID)
+	}
+	return s.promptOptimizeResultLocked(j, s.now()), nil
+}
+
+// pro ai
+
+func (s *Server) createImagePro(r *http.Request) (interface{}, error) {
+	var cReq wujiesdk.CreateImageProRequest
+	if err := decodeBody(r, &cReq); err != nil {
+		return nil, err
+	}
+	if cReq.Prompt == "" && cReq.ImgToImgParam.InitImageUrl == "" {
+		return nil, invalidParameter("prompt or img_to_img_param.init_image_url is required")
+	}
+	if err := s.screen(cReq.Prompt); err != nil {
+		return nil, err
+	}
+	num := cReq.BatchCount
+	if num <= 0 {
+		num = 1
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.settleLocked()
+	if err := s.chargeLocked(0, num*s.prices.Pro); err != nil {
+		return nil, err
+	}
+	data := &wujiesdk.CreateImageProData{}
+	for i := 0; i < num; i++ {
+		j := s.newJobLocked(ProJobKind, &cReq, "")
+		j.cost, j.pro, j.model, j.prompt = s.prices.Pro, true, cReq.ModelCode, cReq.Prompt
+		if cReq.Width > 0 && cReq.Height > 0 {
+			j.width, j.height = cReq.Width, cReq.Height
+		}
+		data.Results = append(data.Results, wujiesdk.CreateImageProResult{Key: j.key, ExpectedSecond: j.remaining(j.created)})
+	}
+	return data, nil
+}
+
+func (s *Server) generatingInfoPro(r *http.Request) (interface{}, error) {
+	var keys []string
+	if err := decodeBody(r, &keys); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.settleLocked()
+	now := s.now()
+	data := &wujiesdk.GeneratingInfoProData{Infos: []wujiesdk.GeneratingInfoPro{}}
+	for _, key := range keys {
+		if j, ok := s.jobs[key]; ok && j.kind == ProJobKind {
+			data.Infos = append(data.Infos, s.generatingInfoProLocked(j, now))
+		}
+	}
+	return data, nil
+}
+
+func (s *Server) modelBaseInfosPro(_ *http.Request) (interface{}, error) {
+	return s.fixtures.ModelBaseInfosPro, nil
+}
+
+func (s *Server) controlNetOptionPro(_ *http.Request) (interface{}, error) {
+	return s.fixtures.ControlNetOptions, nil
+}
+
+// fusionName get name of model fusion of key in default resources, infos carry names of model fusions instead of keys
+func (s *Server) fusionName(key string) string {
+	for _, resource := range s.fixtures.DefaultResources {
+		for _, f := range resource.CreateOptionMenu.ModelFusion {
+			if f.Key == key {
+				return f.Name
+			}
+		}
+	}
+	return key
+}
+
+func (s *Server) imageInfoPro(r *http.Request) (interface{}, error) {
+	key := r.URL.Query().Get("key")
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	j, err := s.jobLocked(ProJobKind, key)
+	if err != nil {
+		return nil, err
+	}
+	cReq := j.request.(*wujiesdk.CreateImageProRequest)
+	info := &wujiesdk.ImageInfoPro{
+		ModelCode:         cReq.ModelCode,
+		Prompt:            cReq.Prompt,
+		Width:             j.width,
+		Height:            j.height,
+		SupersizeMultiple: cReq.SupersizeMultiple,
+		PrefineMultiple:   cReq.PrefineMultiple,
+	}
+	info.OptionInfo.Character = cReq.OptionParam.Character
+	for _, f := range cReq.OptionParam.ModelFusion {
+		info.OptionInfo.ModelFusion = append(info.OptionInfo.ModelFusion, wujiesdk.ModelFusionInfo{Name: s.fusionName(f.Key), Weight: f.Weight})
+	}
+	a := cReq.AdvancedParam
+	info.AdvancedInfo.UcPrompt, info.AdvancedInfo.RestoreFaces, info.AdvancedInfo.Tilling = a.UcPrompt, a.RestoreFaces, a.Tilling
+	info.AdvancedInfo.Seed, info.AdvancedInfo.VaeFile, info.AdvancedInfo.Cfg = a.Seed, a.VaeFile, float64(a.Cfg)
+	info.AdvancedInfo.SamplerSteps, info.AdvancedInfo.SamplerIndex = a.SamplerSteps, a.SamplerIndex
+	info.AdvancedInfo.ClipSkip, info.AdvancedInfo.Ensd = a.ClipSkip, a.Ensd
+	info.ImgToImgInfo.InitImageUrl = cReq.ImgToImgParam.InitImageUrl
+	info.ImgToImgInfo.CreativityDegree = cReq.ImgToImgParam.CreativityDegree
+	info.CostInfo.DurationCost = j.cost
+	return info, nil
+}
+
+func (s *Server) labOptions(r *http.Request) (interface{}, error) {
+	var lReq wujiesdk.LabOptionsRequest
+	if err := decodeBody(r, &lReq); err != nil {
+		return nil, err
+	}
+	if lReq.Input == nil {
+		return nil, invalidParameter("input is required")
+	}
+	options, ok := s.fixtures.LabOptions[lReq.Input.OptionType]
+	if !ok {
+		return nil, invalidParameter("optionType: %s, not found", lReq.Input.OptionType)
+	}
+	return map[string]interface{}{"aiLabQuery": map[string]interface{}{"options": options}}, nil
+}
+
+func (s *Server) labInfo(r *http.Request) (interface{}, error) {
+	var lReq wujiesdk.LabInfoRequest
+	if err := decodeBody(r, &lReq); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.settleLocked()
+	j, err := s.jobLocked(LabJobKind, lReq.ServiceKey)
+	if err != nil {
+		return nil, err
+	}
+	if lReq.AiType != "" && string(lReq.AiType) != j.aiType {
+		return nil, invalidParameter("aiType: %s, serviceKey: %s, mismatch", lReq.AiType, j.key)
+	}
+	return s.labInfoLocked(j, s.now()), nil
+}
+
+// createLab charge and add lab job, result is the create result named field of aiLabMutation
+func (s *Server) createLab(request interface{}, aiType wujiesdk.LabInfoType, field, notifyURL string, width, height int) (interface{}, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.settleLocked()
+	if err := s.chargeLocked(s.prices.Lab, 0); err != nil {
+		return nil, err
+	}
+	j := s.newJobLocked(LabJobKind, request, notifyURL)
+	j.cost, j.aiType = s.prices.Lab, string(aiType)
+	if width > 0 && height > 0 {
+		j.width, j.height = width, height
+	}
+	result := map[string]string{"key": j.key, "aiType": j.aiType}
+	return map[string]interface{}{"aiLabMutation": map[string]interface{}{field: result}}, nil
+}
+
+func (s *Server) createSegmentation(r *http.Request) (interface{}, error) {
+	var cReq wujiesdk.CreateSegmentationRequest
+	if err := decodeBody(r, &cReq); err != nil {
+		return nil, err
+	}
+	if cReq.Input == nil {
+		return nil, invalidParameter("input is required")
+	}
+	if err := required("imageUrl", cReq.Input.ImageUrl); err != nil {
+		return nil, err
+	}
+	return s.createLab(&cReq, wujiesdk.SegmentationLabInfoType, "segmentAnythingCreateV2", cReq.Input.NotifyUrl, 0, 0)
+}
+
+func (s *Server) createInfiniteZoom(r *http.Request) (interface{}, error) {
+	var cReq wujiesdk.CreateInfiniteZoomRequest
+	if err := decodeBody(r, &cReq); err != nil {
+		return nil, err
+	}
+	if cReq.Input == nil {
+		return nil, invalidParameter("input is required")
+	}
+	if err := required("initImageUrl", cReq.Input.InitImageUrl); err != nil {
+		return nil, err
+	}
+	if err := s.screen(cReq.Input.PromptPrefix, cReq.Input.PromptSuffix); err != nil {
+		return nil, err
+	}
+	return s.createLab(&cReq, wujiesdk.InfiniteZoomLabInfoType, "infiniteZoomCreateV2", cReq.Input.NotifyUrl,
+		cReq.Input.ImageWidth, cReq.Input.ImageHeight)
+}
+
+func (s *Server) createVectorStudio(r *http.Request) (interface{}, error) {
+	var cReq wujiesdk.CreateVectorStudioRequest
+	if err := decodeBody(r, &cReq); err != nil {
+		return nil, err
+	}
+	if cReq.Input == nil {
+		return nil, invalidParameter("input is required")
+	}
+	if err := required("initImage", cReq.Input.InitImage); err != nil {
+		return nil, err
+	}
+	return s.createLab(&cReq, wujiesdk.VectorLabInfoType, "vectorStudioCreateV2", cReq.Input.NotifyUrl,
+		cReq.Input.Width, cReq.Input.Height)
+}
+
+func (s *Server) createSVD(r *http.Request) (interface{}, error) {
+	var cReq wujiesdk.CreateSVDRequest
+	if err := decodeBody(r, &cReq); err != nil {
+		return nil, err
+	}
+	if err := required("init_image_url", cReq.InitImageUrl); err != nil {
+		return nil, err
+	}
+	if cReq.Duration <= 0 {
+		return nil, invalidParameter("duration: %d, must be positive", cReq.Duration)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.settleLocked()
+	if err := s.chargeLocked(s.prices.SVD, 0); err != nil {
+		return nil, err
+	}
+	j := s.newJobLocked(SVDJobKind, &cReq, cReq.NotifyUrl)
+	j.cost = s.prices.SVD
+	return map[string]string{"key": j.key}, nil
+}
+
+func (s *Server) svdInfo(r *http.Request) (interface{}, error) {
+	key := r.URL.Query().Get("key")
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.settleLocked()
+	j, err := s.jobLocked(SVDJobKind, key)
+	if err != nil {
+		return nil, err
+	}
+	return s.svdInfoLocked(j, s.now()), nil
+}
+
+// avatar
+
+func (s *Server) createAvatarArtwork(r *http.Request) (interface{}, error) {
+	var cReq wujiesdk.CreateAvatarArtworkRequest
+	if err := decodeBody(r, &cReq); err != nil {
+		return nil, err
+	}
+	if err := required("avatar_key", cReq.AvatarKey); err != nil {
+		return nil, err
+	}
+	if err := s.screen(cReq.Prompt); err != nil {
+		return nil, err
+	}
+	if cReq.AvatarKey != s.fixtures.AvatarDefaultResource.AvatarKey {
+		s.mu.Lock()
+		j, err := s.jobLocked(AvatarJobKind, cReq.AvatarKey)
+		ready := err == nil && j.state(s.now()).status == wujiesdk.SuccessJobStatus
+		s.mu.Unlock()
+		if !ready {
+			return nil, invalidParameter("avatar_key: %s, not ready", cReq.AvatarKey)
+		}
+	}
+	num := 0
+	for _, t := range cReq.ArtworkTemplates {
+		if t.Number > 0 {
+			num += t.Number
+		} else {
+			num++
+		}
+	}
+	data, err := s.createImages(imageSpec{request: &cReq, num: num, prompt: cReq.Prompt, notifyURL: cReq.NotifyURL})
+	if err != nil {
+		return nil, err
+	}
+	return &wujiesdk.CreateAvatarArtworkData{
+		Keys:                 data.Keys,
+		Results:              data.Results,
+		ExpectedIntegralCost: data.ExpectedIntegralCost,
+	}, nil
+}
+
+func (s *Server) avatarDefaultResource(_ *http.Request) (interface{}, error) {
+	return &s.fixtures.AvatarDefaultResource, nil
+}
+
+func (s *Server) createAvatar(r *http.Request) (interface{}, error) {
+	var cReq wujiesdk.CreateAvatarRequest
+	if err := decodeBody(r, &cReq); err != nil {
+		return nil, err
+	}
+	if len(cReq.TrainImageUrlList) == 0 {
+		return nil, invalidParameter("train_image_url_list is required")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.settleLocked()
+	if err := s.chargeLocked(s.prices.Avatar, 0); err != nil {
+		return nil, err
+	}
+	j := s.newJobLocked(AvatarJobKind, &cReq, cReq.NotifyUrl)
+	j.cost = s.prices.Avatar
+	return &wujiesdk.CreateAvatarData{Key: j.key, ExpectedSecond: j.remaining(j.created)}, nil
+}
+
+func (s *Server) deleteAvatar(r *http.Request) (interface{}, error) {
+	var body struct {
+		AvatarKey string `json:"avatar_key"`
+	}
+	if err := decodeBody(r, &body); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := s.jobLocked(AvatarJobKind, body.AvatarKey); err != nil {
+		return nil, err
+	}
+	delete(s.jobs, body.AvatarKey)
+	return nil, nil
+}
+
+func (s *Server) avatarInfo(r *http.Request) (interface{}, error) {
+	key := r.URL.Query().Get("key")
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.settleLocked()
+	j, err := s.jobLocked(AvatarJobKind, key)
+	if err != nil {
+		return nil, err
+	}
+	return s.avatarInfoLocked(j, s.now()), nil
+}
+
+func (s *Server) imageBatchCheck(r *http.Request) (interface{}, error) {
+	var body struct {
+		ImageURLList []string `json:"image_url_list"`
+	}
+	if err := decodeBody(r, &body); err != nil {
+		return nil, err
+	}
+	if len(body.ImageURLList) == 0 {
+		return nil, invalidParameter("image_url_list is required")
+	}
+	infos := make([]wujiesdk.ImageCheckInfo, 0, len(body.ImageURLList))
+	for _, u := range body.ImageURLList {
+		infos = append(infos, wujiesdk.ImageCheckInfo{ImageUrl: u, Pass: true, Status: "PASS", Similarity: 1})
+	}
+	return map[string]interface{}{"image_check_info_list": infos}, nil
+}
+
+// spell analysis
+
+func (s *Server) createSpellAnalysis(r *http.Request) (interface{}, error) {
+	var cReq wujiesdk.CreateSpellAnalysisRequest
+	if err := decodeBody(r, &cReq); err != nil {
+		return nil, err
+	}
+	if err := required("image_url", cReq.ImageURL); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.settleLocked()
+	if err := s.chargeLocked(s.prices.SpellAnalysis, 0); err != nil {
+		return nil, err
+	}
+	j := s.newJobLocked(SpellAnalysisJobKind, &cReq, cReq.NotifyURL)
+	j.cost = s.prices.SpellAnalysis
+	return map[string]string{"key": j.key}, nil
+}
+
+func (s *Server) spellAnalysisInfo(r *http.Request) (interface{}, error) {
+	key := r.URL.Query().Get("spellAnalysisKey")
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.settleLocked()
+	j, err := s.jobLocked(SpellAnalysisJobKind, key)
+	if err != nil {
+		return nil, err
+	}
+	return s.spellAnalysisInfoLocked(j, s.now()), nil
+}
+
+// magic dice
+
+func (s *Server) magicDiceTheme(_ *http.Request) (interface{}, error) {
+	return s.fixtures.MagicDiceThemes, nil
+}
+
+func (s *Server) createMagicDice(r *http.Request) (interface{}, error) {
+	var cReq wujiesdk.CreateMagicDiceRequest
+	if err := decodeBody(r, &cReq); err != nil {
+		return nil, err
+	}
+	found := false
+	for _, t := range s.fixtures.MagicDiceThemes {
+		found = found || t.ThemeID == cReq.ThemeId
+	}
+	if !found {
+		return nil, invalidParameter("theme_id: %d, not found", cReq.ThemeId)
+	}
+	result := s.fixtures.MagicDice
+	if cReq.Keyword != "" {
+		result.PromptChinese = cReq.Keyword + "，" + result.PromptChinese
+		result.PromptEnglish = cReq.Keyword + ", " + result.PromptEnglish
+	}
+	return &result, nil
+}
+
+// video
+
+func (s *Server) createVideo(r *http.Request) (interface{}, error) {
+	var cReq wujiesdk.CreateVideoRequest
+	if err := decodeBody(r, &cReq); err != nil {
+		return nil, err
+	}
+	if err := required("origin_video_url", cReq.OriginVideoUrl); err != nil {
+		return nil, err
+	}
+	if cReq.VideoDuration <= 0 {
+		return nil, invalidParameter("video_duration: %d, must be positive", cReq.VideoDuration)
+	}
+	if s.videoModelName(cReq.ModelCode) == "" {
+		return nil, invalidParameter("model_code: %d, not found", cReq.ModelCode)
+	}
+	price := s.fixtures.VideoOptionMenu.PayInfoVo.Price
+	if wujiesdk.VideoQueueType(cReq.QueueType) == wujiesdk.NightVideoQueueType {
+		price = s.fixtures.VideoOptionMenu.PayInfoVo.NightPrice
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.settleLocked()
+	if err := s.chargeLocked(price*cReq.VideoDuration, 0); err != nil {
+		return nil, err
+	}
+	j := s.newJobLocked(VideoJobKind, &cReq, cReq.NotifyUrl)
+	j.cost, j.model = price*cReq.VideoDuration, cReq.ModelCode
+	return map[string]string{"key": j.key}, nil
+}
+
+func (s *Server) videoInfo(r *http.Request) (interface{}, error) {
+	key := r.URL.Query().Get("key")
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.settleLocked()
+	j, err := s.jobLocked(VideoJobKind, key)
+	if err != nil {
+		return nil, err
+	}
+	return s.videoInfoLocked(j, s.now()), nil
+}
+
+func (s *Server) videoOptionMenu(_ *http.Request) (interface{}, error) {
+	return &s.fixtures.VideoOptionMenu, nil
+}
+
+func (s *Server) videoModelQueueInfo(r *http.Request) (interface{}, error) {
+	if _, err := queryInt(r, "modelCode"); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	num := s.queueNumLocked(VideoJobKind, s.now())
+	expected := seconds((s.queue + s.generate) * time.Duration(num+1))
+	return &wujiesdk.VideoModelQueueInfo{FreeExpectedSeconds: expected, NightExpectedTime: 2 * expected, QueueNum: num}, nil
+}
+
+func (s *Server) videoGeneratingInfo(r *http.Request) (interface{}, error) {
+	var keys []string
+	if err := decodeBody(r, &keys); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.settleLocked()
+	now := s.now()
+	data := &wujiesdk.VideoGeneratingInfo{List: []wujiesdk.VideoGeneratingInfoDetail{}, NextPollingTime: 1}
+	for _, key := range keys {
+		if j, ok := s.jobs[key]; ok && j.kind == VideoJobKind {
+			data.List = append(data.List, *s.videoInfoLocked(j, now))
+		}
+	}
+	return data, nil
+}
+
+// camera
+
+func (s *Server) cameraTemplateOptions(_ *http.Request) (interface{}, error) {
+	return s.fixtures.CameraTemplates, nil
+}
+
+func (s *Server) createCamera(r *http.Request) (interface{}, error) {
+	var cReq wujiesdk.CreateCameraRequest
+	if err := decodeBody(r, &cReq); err != nil {
+		return nil, err
+	}
+	if err := required("avtar_key", cReq.AvtarKey); err != nil {
+		return nil, err
+	}
+	if cReq.TemplateCreateParam == nil {
+		return nil, invalidParameter("template_create_param is required")
+	}
+	if cReq.TemplateCreateParam.TemplateUrl == "" {
+		found := false
+		for _, t := range s.fixtures.CameraTemplates {
+			found = found || t.Key == cReq.TemplateCreateParam.TemplateKey
+		}
+		if !found {
+			return nil, invalidParameter("template_key: %s, not found", cReq.TemplateCreateParam.TemplateKey)
+		}
+	}
+	num := cReq.TemplateCreateParam.Count
+	if num <= 0 {
+		num = 1
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.settleLocked()
+	if err := s.chargeLocked(0, num*s.prices.Camera); err != nil {
+		return nil, err
+	}
+	data := &wujiesdk.CreateCameraResult{ExpectedDurationCost: num * s.prices.Camera}
+	for i := 0; i < num; i++ {
+		j := s.newJobLocked(CameraJobKind, &cReq, "")
+		j.cost, j.pro = s.prices.Camera, true
+		data.Keys = append(data.Keys, j.key)
+	}
+	return data, nil
+}
+
+func (s *Server) cameraGeneratingInfo(r *http.Request) (interface{}, error) {
+	var keys []string
+	if err := decodeBody(r, &keys); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.settleLocked()
+	now := s.now()
+	infos := []wujiesdk.CameraGeneratingInfo{}
+	for _, key := range keys {
+		if j, ok := s.jobs[key]; ok && j.kind == CameraJobKind {
+			infos = append(infos, s.cameraGeneratingInfoLocked(j, now))
+		}
+	}
+	return map[string]interface{}{"infos": infos}, nil
+}
+
+func (s *Server) cameraInfo(r *http.Request) (interface{}, error) {
+	key := r.URL.Query().Get("key")
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.settleLocked()
+	j, err := s.jobLocked(CameraJobKind, key)
+	if err != nil {
+		return nil, err
+	}
+	return s.cameraInfoLocked(j, s.now()), nil
+}
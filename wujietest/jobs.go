@@ -0,0 +1,613 @@
+package wujietest
+
+// @Title        jobs.go
+// @Description  lifecycle, callbacks and assets of jobs of fake server
+// @Create       XdpCs 2026-10-19 21:55
+// @Update       XdpCs 2026-10-20 13:00
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"image"
+	"image/color"
+	"image/png"
+	"net/http"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/XdpCs/wujiesdk"
+)
+
+// JobKind is the kind of job simulated by Server
+type JobKind string
+
+const (
+	ImageJobKind          JobKind = "image" // CreateImage, CreateMidjourney, CreateFlux, Youthify and CreateAvatarArtwork
+	ProJobKind            JobKind = "pro"
+	SuperSizeJobKind      JobKind = "super_size"
+	PromptOptimizeJobKind JobKind = "prompt_optimize"
+	AvatarJobKind         JobKind = "avatar"
+	SpellAnalysisJobKind  JobKind = "spell_analysis"
+	VideoJobKind          JobKind = "video"
+	CameraJobKind         JobKind = "camera"
+	SVDJobKind            JobKind = "svd"
+	LabJobKind            JobKind = "lab"
+)
+
+// CanceledFailMessage is the failure of jobs canceled by CancelImage
+var CanceledFailMessage = wujiesdk.FailMessage{FailCode: 499, FailMessage: "canceled"}
+
+var labStatus = map[wujiesdk.JobStatus]string{
+	wujiesdk.QueuingJobStatus:    "QUEUING",
+	wujiesdk.GeneratingJobStatus: "GENERATING",
+	wujiesdk.SuccessJobStatus:    "SUCCESS",
+	wujiesdk.FailedJobStatus:     "FAILED",
+}
+
+type job struct {
+	seq        int
+	kind       JobKind
+	key        string
+	request    interface{}
+	created    time.Time
+	queue      time.Duration
+	generate   time.Duration
+	fail       *wujiesdk.FailMessage
+	canceledAt time.Time
+	cost       int
+	pro        bool // cost is seconds of pro balance
+	notifyURL  string
+	settled    bool
+	model      wujiesdk.ModelCode
+	prompt     string
+	width      int
+	height     int
+	aiType     string // lab info type
+}
+
+type jobState struct {
+	status   wujiesdk.JobStatus
+	percent  float64
+	startGen time.Time
+	complete time.Time
+	fail     wujiesdk.FailMessage
+}
+
+func (j *job) state(now time.Time) jobState {
+	startGen, complete := j.created.Add(j.queue), j.created.Add(j.queue+j.generate)
+	if !j.canceledAt.IsZero() {
+		return jobState{status: wujiesdk.FailedJobStatus, complete: j.canceledAt, fail: CanceledFailMessage}
+	}
+	switch {
+	case now.Before(startGen):
+		return jobState{status: wujiesdk.QueuingJobStatus}
+	case now.Before(complete):
+		return jobState{
+			status:   wujiesdk.GeneratingJobStatus,
+			percent:  float64(now.Sub(startGen)*100) / float64(j.generate),
+			startGen: startGen,
+		}
+	case j.fail != nil:
+		return jobState{status: wujiesdk.FailedJobStatus, startGen: startGen, complete: complete, fail: *j.fail}
+	}
+	return jobState{status: wujiesdk.SuccessJobStatus, percent: 100, startGen: startGen, complete: complete}
+}
+
+func (j *job) remaining(now time.Time) int {
+	return seconds(j.created.Add(j.queue + j.generate).Sub(now))
+}
+
+// seconds round d up to seconds
+func seconds(d time.Duration) int {
+	if d <= 0 {
+		return 0
+	}
+	return int((d + time.Second - 1) / time.Second)
+}
+
+func unix(t time.Time) int {
+	if t.IsZero() {
+		return 0
+	}
+	return int(t.Unix())
+}
+
+// newJobLocked add job whose outcome is decided by Outcome
+func (s *Server) newJobLocked(kind JobKind, request interface{}, notifyURL string) *job {
+	s.seq++
+	j := &job{
+		seq:       s.seq,
+		kind:      kind,
+		key:       fmt.Sprintf("%s-%d", strings.ReplaceAll(string(kind), "_", "-"), s.seq),
+		request:   request,
+		created:   s.now(),
+		queue:     s.queue,
+		generate:  s.generate,
+		notifyURL: notifyURL,
+		width:     512,
+		height:    512,
+	}
+	if s.outcome != nil {
+		j.fail = s.outcome(kind, j.key, request)
+	}
+	s.jobs[j.key] = j
+	return j
+}
+
+func (s *Server) jobLocked(kind JobKind, key string) (*job, error) {
+	j, ok := s.jobs[key]
+	if !ok || j.kind != kind {
+		return nil, invalidParameter("key: %s, not found", key)
+	}
+	return j, nil
+}
+
+// chargeLocked take points and seconds from balance
+func (s *Server) chargeLocked(points, proSeconds int) error {
+	if points > s.balance || proSeconds > s.proBalance {
+		return codeErr(wujiesdk.InsufficientPointsBalanceWujieCode, "balance: %d, pro balance: %d, points: %d, seconds: %d",
+			s.balance, s.proBalance, points, proSeconds)
+	}
+	s.balance -= points
+	s.proBalance -= proSeconds
+	return nil
+}
+
+// settleLocked refund failed jobs and queue callbacks of finished jobs
+func (s *Server) settleLocked() {
+	now := s.now()
+	var settled []*job
+	for _, j := range s.jobs {
+		if j.settled || !j.state(now).status.Terminal() {
+			continue
+		}
+		j.settled = true
+		if j.state(now).status == wujiesdk.FailedJobStatus {
+			if j.pro {
+				s.proBalance += j.cost
+			} else {
+				s.balance += j.cost
+			}
+		}
+		if j.notifyURL != "" {
+			settled = append(settled, j)
+		}
+	}
+	sort.Slice(settled, func(a, b int) bool { return settled[a].seq < settled[b].seq })
+	s.pending = append(s.pending, settled...)
+}
+
+// FailJob make job of key fail with f when it finishes, it reports false when job is not found or already finished
+func (s *Server) FailJob(key string, f wujiesdk.FailMessage) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.settleLocked()
+	j, ok := s.jobs[key]
+	if !ok || j.settled {
+		return false
+	}
+	j.fail = &f
+	return true
+}
+
+// JobStatus get status of job of key
+func (s *Server) JobStatus(key string) (wujiesdk.JobStatus, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	j, ok := s.jobs[key]
+	if !ok {
+		return 0, false
+	}
+	return j.state(s.now()).status, true
+}
+
+// Callback is a notify_url callback sent by Server
+type Callback struct {
+	Kind       JobKind
+	Key        string
+	URL        string
+	Body       []byte
+	StatusCode int
+	Err        error
+}
+
+// Callbacks get callbacks sent so far
+func (s *Server) Callbacks() []Callback {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Callback(nil), s.callbacks...)
+}
+
+// FlushCallbacks send callbacks of every finished job now, callbacks are also sent in background
+func (s *Server) FlushCallbacks() {
+	s.deliverMu.Lock()
+	defer s.deliverMu.Unlock()
+	s.mu.Lock()
+	s.settleLocked()
+	pending := s.pending
+	s.pending = nil
+	bodies := make([][]byte, len(pending))
+	for i, j := range pending {
+		bodies[i], _ = json.Marshal(s.callbackLocked(j))
+	}
+	s.mu.Unlock()
+
+	for i, j := range pending {
+		c := Callback{Kind: j.kind, Key: j.key, URL: j.notifyURL, Body: bodies[i]}
+		resp, err := s.callbackClient.Post(j.notifyURL, wujiesdk.ApplicationJson, bytes.NewReader(bodies[i]))
+		if err != nil {
+			c.Err = err
+		} else {
+			c.StatusCode = resp.StatusCode
+			_ = resp.Body.Close()
+		}
+		s.mu.Lock()
+		s.callbacks = append(s.callbacks, c)
+		s.mu.Unlock()
+	}
+}
+
+func (s *Server) runCallbacks() {
+	defer s.wg.Done()
+	ticker := time.NewTicker(callbackInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+			s.FlushCallbacks()
+		}
+	}
+}
+
+// callbackLocked get body of callback, which is CreateImageCallBackSuccessResp or CreateImageCallBackFailedResp
+// for images and the info of job for the others
+func (s *Server) callbackLocked(j *job) interface{} {
+	now := s.now()
+	switch j.kind {
+	case ImageJobKind:
+		st := j.state(now)
+		if st.status == wujiesdk.FailedJobStatus {
+			return &wujiesdk.CreateImageCallBackFailedResp{
+				FailMessage:                 st.fail.FailMessage,
+				CreateImageBaseCallBackResp: wujiesdk.CreateImageBaseCallBackResp{Code: st.fail.FailCode, Key: j.key},
+			}
+		}
+		return &wujiesdk.CreateImageCallBackSuccessResp{
+			ArtworkUrl:   s.assetURL(j, ".png"),
+			AuditInfo:    wujiesdk.AuditInfo{TotalSuggestion: "pass", Url: s.assetURL(j, ".png")},
+			CompleteTime: unix(st.complete),
+			IntegralCost: j.cost,
+			CreateImageBaseCallBackResp: wujiesdk.CreateImageBaseCallBackResp{
+				Code: 200, Key: j.key, Success: true,
+			},
+		}
+	case PromptOptimizeJobKind:
+		return s.promptOptimizeResultLocked(j, now)
+	case AvatarJobKind:
+		return s.avatarInfoLocked(j, now)
+	case SpellAnalysisJobKind:
+		return s.spellAnalysisInfoLocked(j, now)
+	case VideoJobKind:
+		return s.videoInfoLocked(j, now)
+	case SVDJobKind:
+		return s.svdInfoLocked(j, now)
+	case LabJobKind:
+		return s.labInfoLocked(j, now)
+	}
+	return nil
+}
+
+func (s *Server) assetURL(j *job, ext string) string {
+	return s.URL + assetsPath + j.key + ext
+}
+
+func (s *Server) imageGeneratingInfoLocked(j *job, now time.Time) wujiesdk.ImageGeneratingInfo {
+	st := j.state(now)
+	info := wujiesdk.ImageGeneratingInfo{
+		Key:             j.key,
+		Status:          int(st.status),
+		ExpectedSeconds: j.remaining(now),
+		StartGenTime:    unix(st.startGen),
+		CompleteTime:    unix(st.complete),
+		CompletePercent: st.percent,
+		FailMessage:     st.fail,
+		ModelPrompt:     j.prompt,
+		IntegralCost:    j.cost,
+	}
+	if st.status == wujiesdk.QueuingJobStatus {
+		info.QueueBeforeNum = s.queueBeforeLocked(j, now)
+	}
+	if st.status == wujiesdk.SuccessJobStatus {
+		info.PictureURL = s.assetURL(j, ".png")
+		info.AuditInfo = `{"total_suggestion":"pass"}`
+	}
+	return info
+}
+
+// queueBeforeLocked get number of queuing jobs of the same kind created before j
+func (s *Server) queueBeforeLocked(j *job, now time.Time) int {
+	n := 0
+	for _, o := range s.jobs {
+		if o.kind == j.kind && o.seq < j.seq && o.state(now).status == wujiesdk.QueuingJobStatus {
+			n++
+		}
+	}
+	return n
+}
+
+func (s *Server) queueNumLocked(kind JobKind, now time.Time) int {
+	n := 0
+	for _, j := range s.jobs {
+		if j.kind == kind && j.state(now).status == wujiesdk.QueuingJobStatus {
+			n++
+		}
+	}
+	return n
+}
+
+func (s *Server) generatingInfoProLocked(j *job, now time.Time) wujiesdk.GeneratingInfoPro {
+	st := j.state(now)
+	info := wujiesdk.GeneratingInfoPro{
+		Key:             j.key,
+		Status:          int(st.status),
+		ExpectedSeconds: j.remaining(now),
+		StartGenTime:    unix(st.startGen),
+		CompleteTime:    unix(st.complete),
+		CompletePercent: st.percent,
+		FailMessage:     st.fail,
+	}
+	if st.status == wujiesdk.SuccessJobStatus {
+		info.PictureURL = s.assetURL(j, ".png")
+		info.AuditInfo = `{"total_suggestion":"pass"}`
+	}
+	return info
+}
+
+func (s *Server) superSizeInfoLocked(j *job, now time.Time) wujiesdk.SuperSizeInfo {
+	st := j.state(now)
+	r := j.request.(*wujiesdk.PostSuperSizeRequest)
+	info := wujiesdk.SuperSizeInfo{
+		Key:      j.key,
+		URL:      r.URL,
+		Multiple: r.Multiple,
+		Status:   int(st.status),
+		Integral: j.cost,
+	}
+	if st.status == wujiesdk.SuccessJobStatus {
+		info.SrURL = s.assetURL(j, ".png")
+		info.Duration = int(j.generate / time.Second)
+	}
+	return info
+}
+
+func (s *Server) promptOptimizeResultLocked(j *job, now time.Time) *wujiesdk.PromptOptimizeResultData {
+	st := j.state(now)
+	r := j.request.(*wujiesdk.PromptOptimizeSubmitRequest)
+	data := &wujiesdk.PromptOptimizeResultData{TaskID: r.TaskID, Code: int(st.status)}
+	if st.status == wujiesdk.SuccessJobStatus {
+		data.Result = r.Original + ", masterpiece, best quality"
+	}
+	return data
+}
+
+func (s *Server) avatarInfoLocked(j *job, now time.Time) *wujiesdk.AvatarInfoData {
+	st := j.state(now)
+	info := &wujiesdk.AvatarInfoData{Key: j.key, Status: int(st.status)}
+	if st.status == wujiesdk.SuccessJobStatus {
+		info.ModelFusionName = "avatar_" + j.key
+	}
+	return info
+}
+
+func (s *Server) spellAnalysisInfoLocked(j *job, now time.Time) *wujiesdk.SpellAnalysisInfo {
+	st := j.state(now)
+	r := j.request.(*wujiesdk.CreateSpellAnalysisRequest)
+	info := &wujiesdk.SpellAnalysisInfo{SpellAnalysisInfoKey: j.key, ImageURL: r.ImageURL, Status: int(st.status)}
+	if st.status == wujiesdk.SuccessJobStatus {
+		info.Tags = s.fixtures.SpellAnalysisTags
+	}
+	return info
+}
+
+func (s *Server) videoInfoLocked(j *job, now time.Time) *wujiesdk.VideoInfo {
+	st := j.state(now)
+	r := j.request.(*wujiesdk.CreateVideoRequest)
+	info := &wujiesdk.VideoInfo{
+		Key:             j.key,
+		ModelCode:       r.ModelCode,
+		ModelName:       s.videoModelName(r.ModelCode),
+		OriginVideoUrl:  r.OriginVideoUrl,
+		Status:          int(st.status),
+		CreateTime:      unix(j.created),
+		CompleteTime:    unix(st.complete),
+		ExpectedSeconds: j.remaining(now),
+		CompletePercent: st.percent,
+		FailMessage:     st.fail,
+		QueueType:       r.QueueType,
+	}
+	if st.status == wujiesdk.SuccessJobStatus {
+		info.AiVideoUrl = s.assetURL(j, ".mp4")
+		info.ViolationInfo.TotalSuggestion = "pass"
+		info.AiVideoMetaInfo = wujiesdk.AiVideoMetaInfo{
+			Format:    "mp4",
+			Width:     j.width,
+			Height:    j.height,
+			Duration:  r.VideoDuration,
+			CodecType: "h264",
+			FrameRate: 24,
+			Cover:     wujiesdk.VideoCover{Url: s.assetURL(j, ".png"), Width: j.width, Height: j.height},
+		}
+	}
+	return info
+}
+
+func (s *Server) videoModelName(modelCode wujiesdk.ModelCode) string {
+	for _, m := range s.fixtures.VideoOptionMenu.AiVideoModelOptionVos {
+		if m.ModelCode == modelCode {
+			return m.Name
+		}
+	}
+	return ""
+}
+
+func (s *Server) cameraGeneratingInfoLocked(j *job, now time.Time) wujiesdk.CameraGeneratingInfo {
+	st := j.state(now)
+	info := wujiesdk.CameraGeneratingInfo{
+		Key:             j.key,
+		Status:          int(st.status),
+		ExpectedSeconds: j.remaining(now),
+		StartGenTime:    unix(st.startGen),
+		CompleteTime:    unix(st.complete),
+		CompletePercent: st.percent,
+		FailMessage:     st.fail,
+	}
+	if st.status == wujiesdk.SuccessJobStatus {
+		info.ArtworkUrl = s.assetURL(j, ".png")
+	}
+	return info
+}
+
+func (s *Server) cameraInfoLocked(j *job, now time.Time) *wujiesdk.CameraInfo {
+	st := j.state(now)
+	info := &wujiesdk.CameraInfo{
+		Key:         j.key,
+		Status:      int(st.status),
+		Width:       j.width,
+		Height:      j.height,
+		Seed:        strconv.Itoa(j.seq),
+		FailMessage: st.fail,
+	}
+	if st.status == wujiesdk.SuccessJobStatus {
+		info.ArtworkUrl = s.assetURL(j, ".png")
+	}
+	return info
+}
+
+func (s *Server) svdInfoLocked(j *job, now time.Time) *wujiesdk.SVDInfo {
+	st := j.state(now)
+	r := j.request.(*wujiesdk.CreateSVDRequest)
+	info := &wujiesdk.SVDInfo{
+		Key:             j.key,
+		InitImageUrl:    r.InitImageUrl,
+		Duration:        r.Duration,
+		MotionAmplitude: r.MotionAmplitude,
+		NoiseIntensity:  r.NoiseIntensity,
+		RandomSeed:      r.RandomSeed,
+		Status:          int(st.status),
+		FailMessage:     st.fail,
+	}
+	if st.status == wujiesdk.SuccessJobStatus {
+		info.VideoUrl = s.assetURL(j, ".mp4")
+	}
+	return info
+}
+
+func (s *Server) labInfoLocked(j *job, now time.Time) *wujiesdk.LabInfo {
+	st := j.state(now)
+	info := &wujiesdk.LabInfo{
+		AiType:          j.aiType,
+		ServiceKey:      j.key,
+		CompletePercent: int(st.percent),
+		Status:          labStatus[st.status],
+	}
+	if st.status == wujiesdk.FailedJobStatus {
+		info.FailMessage = st.fail
+	}
+	success := st.status == wujiesdk.SuccessJobStatus
+	switch r := j.request.(type) {
+	case *wujiesdk.CreateSegmentationRequest:
+		info.SegmentInfo = wujiesdk.SegmentInfo{
+			ImageUrl:       r.Input.ImageUrl,
+			ModelCode:      r.Input.ModelCode,
+			NegativePoints: r.Input.NegativePoints,
+			PositivePoints: r.Input.PositivePoints,
+			Prompt:         r.Input.Prompt,
+			Threshold:      r.Input.Threshold,
+		}
+		if success {
+			info.SegmentInfo.ImageUrls = []string{s.assetURL(j, "_0.png"), s.assetURL(j, "_1.png")}
+		}
+	case *wujiesdk.CreateInfiniteZoomRequest:
+		z := &info.InfiniteZoomInfo
+		z.InitImageUrl, z.ExitImageUrl, z.ModelCode = r.Input.InitImageUrl, r.Input.ExitImageUrl, r.Input.ModelCode
+		z.VideoSecond, z.VideoFrameRate, z.ImageWidth, z.ImageHeight = r.Input.VideoSecond, r.Input.VideoFrameRate, j.width, j.height
+		z.PromptPrefix, z.PromptSuffix, z.UcPrompt = r.Input.PromptPrefix, r.Input.PromptSuffix, r.Input.UcPrompt
+		if success {
+			z.VideoUrl = s.assetURL(j, ".mp4")
+		}
+	case *wujiesdk.CreateVectorStudioRequest:
+		v := &info.VectorInfo
+		v.Vectorization, v.Style, v.Threshold = r.Input.Vectorization, r.Input.Style, r.Input.Threshold
+		v.TransparentPNG, v.NoiseTolerance, v.Quantize = r.Input.TransparentPNG, r.Input.NoiseTolerance, r.Input.Quantize
+		if success {
+			v.MiniArtWorkUrl = s.assetURL(j, ".png")
+			v.VectorRes = []wujiesdk.VectorRes{{Type: "svg", Url: s.assetURL(j, ".svg")}, {Type: "png", Url: s.assetURL(j, "_0.png")}}
+		}
+	}
+	return info
+}
+
+// serveAsset serve a generated file of a successful job, name is key, optional _index and extension
+func (s *Server) serveAsset(w http.ResponseWriter, r *http.Request, name string) {
+	ext := path.Ext(name)
+	key := strings.TrimSuffix(name, ext)
+	s.mu.Lock()
+	j, ok := s.jobs[key]
+	if !ok {
+		if i := strings.LastIndex(key, "_"); i > 0 {
+			j, ok = s.jobs[key[:i]]
+		}
+	}
+	var status wujiesdk.JobStatus
+	var width, height int
+	if ok {
+		status, width, height = j.state(s.now()).status, j.width, j.height
+	}
+	s.mu.Unlock()
+	if !ok || status != wujiesdk.SuccessJobStatus {
+		http.NotFound(w, r)
+		return
+	}
+
+	var body []byte
+	var contentType string
+	switch ext {
+	case ".png":
+		h := fnv.New32a()
+		_, _ = h.Write([]byte(name))
+		sum := h.Sum32()
+		img := image.NewUniform(color.RGBA{R: uint8(sum), G: uint8(sum >> 8), B: uint8(sum >> 16), A: 0xff})
+		var buf bytes.Buffer
+		_ = png.Encode(&buf, &boundedImage{Uniform: img, rect: image.Rect(0, 0, width, height)})
+		body, contentType = buf.Bytes(), "image/png"
+	case ".svg":
+		body = []byte(fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d"></svg>`, width, height))
+		contentType = "image/svg+xml"
+	case ".mp4":
+		// ftyp box is enough for sniffing
+		body = append([]byte{0, 0, 0, 0x18}, []byte("ftypmp42\x00\x00\x00\x00mp42isom")...)
+		contentType = "video/mp4"
+	default:
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set(wujiesdk.ContentType, contentType)
+	http.ServeContent(w, r, name, time.Time{}, bytes.NewReader(body))
+}
+
+// boundedImage is image.Uniform with bounds
+type boundedImage struct {
+	*image.Uniform
+	rect image.Rectangle
+}
+
+func (b *boundedImage) Bounds() image.Rectangle {
+	return b.rect
+}
@@ -0,0 +1,417 @@
+package wujietest
+
+// @Title        recorder.go
+// @Description  record and replay http interactions as cassette files
+// @Create       XdpCs 2026-10-19 22:30
+// @Update       XdpCs 2026-10-19 22:30
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/XdpCs/wujiesdk"
+)
+
+// CassetteVersion is the version of cassette files written by Recorder
+const CassetteVersion = 1
+
+// Redacted replaces values of redacted headers in cassettes
+const Redacted = "[REDACTED]"
+
+// ErrUnrecordedRequest is returned by Recorder in replay when no interaction matches the request
+var ErrUnrecordedRequest = errors.New("unrecorded request")
+
+// Mode is the mode of Recorder
+type Mode int
+
+const (
+	ModeReplay     Mode = iota // only replay cassette, unrecorded requests fail
+	ModeRecord                 // send every request and record a new cassette
+	ModeRecordOnce             // replay cassette if it exists, otherwise record it
+)
+
+// String get name of mode
+func (m Mode) String() string {
+	switch m {
+	case ModeReplay:
+		return "replay"
+	case ModeRecord:
+		return "record"
+	case ModeRecordOnce:
+		return "record_once"
+	}
+	return fmt.Sprintf("Mode(%d)", int(m))
+}
+
+// ScrubRule replaces text matched by Pattern with Replacement in bodies, queries and headers,
+// Replacement can use $1 as regexp.Regexp.ReplaceAllString does
+type ScrubRule struct {
+	Name        string
+	Pattern     *regexp.Regexp
+	Replacement string
+}
+
+var (
+	// PhoneScrubRule hides mainland China mobile phone numbers
+	PhoneScrubRule = ScrubRule{Name: "phone", Pattern: regexp.MustCompile(`\b1[3-9]\d{9}\b`), Replacement: "[PHONE]"}
+	// URLScrubRule drops query of urls, which holds signatures and tokens of signed urls
+	URLScrubRule = ScrubRule{Name: "url", Pattern: regexp.MustCompile(`(https?://[^\s"'?#]+)\?[^\s"'#]*`), Replacement: "$1"}
+)
+
+// DefaultScrubRules is used by NewRecorder without WithScrubRules
+var DefaultScrubRules = []ScrubRule{PhoneScrubRule, URLScrubRule}
+
+// DefaultRedactHeaders is used by NewRecorder without WithRedactHeaders
+var DefaultRedactHeaders = []string{wujiesdk.HTTPHeaderAuthorization}
+
+// Cassette is the content of a cassette file
+type Cassette struct {
+	Version      int           `json:"version"`
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is a recorded request and its response
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedRequest is a scrubbed request, Router is WujieRouter for wujie's api and the url without query otherwise
+type RecordedRequest struct {
+	Method string      `json:"method"`
+	Router string      `json:"router"`
+	Query  string      `json:"query,omitempty"`
+	Body   string      `json:"body,omitempty"`
+	Header http.Header `json:"header,omitempty"`
+}
+
+// RecordedResponse is a scrubbed response, Body is base64 when Encoding is "base64"
+type RecordedResponse struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body,omitempty"`
+	Encoding   string      `json:"encoding,omitempty"`
+}
+
+// key is what requests are matched by
+func (r *RecordedRequest) key() string {
+	return r.Method + " " + r.Router + "?" + r.Query + " " + r.Body
+}
+
+// LoadCassette load cassette from file
+func LoadCassette(path string) (*Cassette, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("os.ReadFile: path: %v, error: %w", path, err)
+	}
+	var c Cassette
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("json.Unmarshal: path: %v, error: %w", path, err)
+	}
+	if c.Version != CassetteVersion {
+		return nil, fmt.Errorf("wujietest.LoadCassette: path: %v, unsupported version: %d", path, c.Version)
+	}
+	return &c, nil
+}
+
+// Save write cassette to file, directories are created if needed
+func (c *Cassette) Save(path string) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("json.MarshalIndent: error: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("os.MkdirAll: path: %v, error: %w", path, err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("os.WriteFile: path: %v, error: %w", path, err)
+	}
+	return nil
+}
+
+// RecorderOption set optional field of Recorder
+type RecorderOption func(r *Recorder)
+
+// WithRealTransport set transport used to send requests in record, http.DefaultTransport by default
+func WithRealTransport(rt http.RoundTripper) RecorderOption {
+	return func(r *Recorder) {
+		r.real = rt
+	}
+}
+
+// WithScrubRules replace DefaultScrubRules
+func WithScrubRules(rules ...ScrubRule) RecorderOption {
+	return func(r *Recorder) {
+		r.rules = rules
+	}
+}
+
+// WithRedactHeaders replace DefaultRedactHeaders
+func WithRedactHeaders(names ...string) RecorderOption {
+	return func(r *Recorder) {
+		r.redact = names
+	}
+}
+
+// Recorder is a http.RoundTripper recording interactions to a cassette file or replaying them from it.
+// Requests are matched by method, WujieRouter, query and normalized json body after scrubbing,
+// identical requests get their recorded responses in order and the last one once they run out.
+type Recorder struct {
+	path   string
+	mode   Mode
+	real   http.RoundTripper
+	rules  []ScrubRule
+	redact []string
+	host   string
+	prefix string
+
+	mu         sync.Mutex
+	cassette   *Cassette
+	used       []bool
+	last       map[string]int
+	unrecorded []string
+}
+
+// NewRecorder new recorder of cassette at path, the cassette must exist in ModeReplay
+func NewRecorder(path string, mode Mode, opts ...RecorderOption) (*Recorder, error) {
+	r := &Recorder{
+		path:     path,
+		mode:     mode,
+		real:     http.DefaultTransport,
+		rules:    DefaultScrubRules,
+		redact:   DefaultRedactHeaders,
+		cassette: &Cassette{Version: CassetteVersion},
+		last:     make(map[string]int),
+	}
+	if u, err := url.Parse(wujiesdk.Domain); err == nil {
+		r.host, r.prefix = u.Host, u.Path
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	if r.mode == ModeRecordOnce {
+		r.mode = ModeRecord
+		if _, err := os.Stat(path); err == nil {
+			r.mode = ModeReplay
+		}
+	}
+	switch r.mode {
+	case ModeReplay:
+		c, err := LoadCassette(path)
+		if err != nil {
+			return nil, fmt.Errorf("wujietest.LoadCassette: %w", err)
+		}
+		r.cassette = c
+		r.used = make([]bool, len(c.Interactions))
+	case ModeRecord:
+	default:
+		return nil, fmt.Errorf("wujietest.NewRecorder: unknown mode: %v", mode)
+	}
+	return r, nil
+}
+
+// Mode get mode recorder runs in, ModeRecordOnce is resolved to ModeReplay or ModeRecord
+func (r *Recorder) Mode() Mode {
+	return r.mode
+}
+
+// HTTPClient get http client sending requests through recorder
+func (r *Recorder) HTTPClient() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+// RoundTrip implement http.RoundTripper
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil && req.Body != http.NoBody {
+		data, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("io.ReadAll: read request body error: %w", err)
+		}
+		body = data
+	}
+	recorded := r.recordRequest(req, body)
+	if r.mode == ModeReplay {
+		return r.replay(req, recorded)
+	}
+
+	out := req.Clone(req.Context())
+	out.Body = io.NopCloser(bytes.NewReader(body))
+	out.ContentLength = int64(len(body))
+	resp, err := r.real.RoundTrip(out)
+	if err != nil {
+		return nil, err
+	}
+	data, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("io.ReadAll: read response body error: %w", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(data))
+
+	r.mu.Lock()
+	r.cassette.Interactions = append(r.cassette.Interactions, Interaction{
+		Request:  *recorded,
+		Response: r.recordResponse(resp, data),
+	})
+	r.mu.Unlock()
+	return resp, nil
+}
+
+func (r *Recorder) replay(req *http.Request, recorded *RecordedRequest) (*http.Response, error) {
+	key := recorded.key()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	i := -1
+	for j, in := range r.cassette.Interactions {
+		if !r.used[j] && in.Request.key() == key {
+			i = j
+			break
+		}
+	}
+	if i < 0 {
+		last, ok := r.last[key]
+		if !ok {
+			msg := fmt.Sprintf("%s %s?%s body: %s", recorded.Method, recorded.Router, recorded.Query, recorded.Body)
+			r.unrecorded = append(r.unrecorded, msg)
+			return nil, fmt.Errorf("wujietest.Recorder: cassette: %v, %s, error: %w", r.path, msg, ErrUnrecordedRequest)
+		}
+		i = last
+	}
+	r.used[i] = true
+	r.last[key] = i
+
+	in := r.cassette.Interactions[i].Response
+	data := []byte(in.Body)
+	if in.Encoding == "base64" {
+		decoded, err := base64.StdEncoding.DecodeString(in.Body)
+		if err != nil {
+			return nil, fmt.Errorf("base64.StdEncoding.DecodeString: cassette: %v, error: %w", r.path, err)
+		}
+		data = decoded
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", in.StatusCode, http.StatusText(in.StatusCode)),
+		StatusCode:    in.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        in.Header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(data)),
+		ContentLength: int64(len(data)),
+		Request:       req,
+	}, nil
+}
+
+// Unrecorded get requests replay could not match
+func (r *Recorder) Unrecorded() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.unrecorded...)
+}
+
+// Stop save cassette in record and report unrecorded requests in replay
+func (r *Recorder) Stop() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.mode == ModeRecord {
+		return r.cassette.Save(r.path)
+	}
+	if len(r.unrecorded) > 0 {
+		return fmt.Errorf("wujietest.Recorder: cassette: %v, %d requests: %s, error: %w",
+			r.path, len(r.unrecorded), strings.Join(r.unrecorded, "; "), ErrUnrecordedRequest)
+	}
+	return nil
+}
+
+func (r *Recorder) recordRequest(req *http.Request, body []byte) *RecordedRequest {
+	router := req.URL.Scheme + "://" + req.URL.Host + req.URL.Path
+	if req.URL.Host == r.host && strings.HasPrefix(req.URL.Path, r.prefix+"/") {
+		router = strings.TrimPrefix(req.URL.Path, r.prefix)
+	}
+	query := req.URL.Query()
+	for k, vs := range query {
+		for i := range vs {
+			vs[i] = r.scrub(vs[i])
+		}
+		query[k] = vs
+	}
+	return &RecordedRequest{
+		Method: req.Method,
+		Router: r.scrub(router),
+		Query:  query.Encode(),
+		Body:   r.scrub(normalizeJSON(body)),
+		Header: r.scrubHeader(req.Header),
+	}
+}
+
+func (r *Recorder) recordResponse(resp *http.Response, body []byte) RecordedResponse {
+	out := RecordedResponse{
+		StatusCode: resp.StatusCode,
+		Header:     r.scrubHeader(resp.Header),
+	}
+	if utf8.Valid(body) {
+		out.Body = r.scrub(string(body))
+	} else {
+		out.Body, out.Encoding = base64.StdEncoding.EncodeToString(body), "base64"
+	}
+	return out
+}
+
+func (r *Recorder) scrub(s string) string {
+	for _, rule := range r.rules {
+		s = rule.Pattern.ReplaceAllString(s, rule.Replacement)
+	}
+	return s
+}
+
+func (r *Recorder) scrubHeader(h http.Header) http.Header {
+	if len(h) == 0 {
+		return nil
+	}
+	out := make(http.Header, len(h))
+	for k, vs := range h {
+		scrubbed := make([]string, len(vs))
+		for i, v := range vs {
+			scrubbed[i] = r.scrub(v)
+		}
+		out[k] = scrubbed
+	}
+	for _, name := range r.redact {
+		if _, ok := out[http.CanonicalHeaderKey(name)]; ok {
+			out.Set(name, Redacted)
+		}
+	}
+	return out
+}
+
+// normalizeJSON compact json, json.Marshal sorts keys of maps so that field order and spaces do not matter
+func normalizeJSON(body []byte) string {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return ""
+	}
+	d := json.NewDecoder(bytes.NewReader(body))
+	d.UseNumber()
+	var v interface{}
+	if err := d.Decode(&v); err != nil || d.More() {
+		return string(body)
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return string(body)
+	}
+	return string(data)
+}
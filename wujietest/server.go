@@ -0,0 +1,523 @@
+package wujietest
+
+// @Title        server.go
+// @Description  in-process fake of wujie's api for tests
+// @Create       XdpCs 2026-10-19 21:55
+// @Update       XdpCs 2026-10-19 21:55
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/XdpCs/wujiesdk"
+)
+
+// DefaultAppID is the app id of credentials generated by NewServer
+const DefaultAppID = "wujietest"
+
+const (
+	DefaultQueueDuration    = 100 * time.Millisecond // time a job stays in queue
+	DefaultGenerateDuration = 400 * time.Millisecond // time a job takes to generate after queue
+	DefaultBalance          = 10000                  // points of AvailableIntegralBalance
+	DefaultProBalance       = 36000                  // seconds of AccountBalancePro
+)
+
+// AnyRouter matches every router in Server.Inject
+const AnyRouter wujiesdk.WujieRouter = "*"
+
+// UnauthorizedWujieCode is the code of responses whose Authorization is invalid
+const UnauthorizedWujieCode wujiesdk.WujieCode = "401"
+
+// MaxSignSkew is how far timestamp of Authorization may be from now
+const MaxSignSkew = 10 * time.Minute
+
+const (
+	basePath         = "/wj-open/v1"
+	assetsPath       = "/assets/"
+	callbackInterval = 20 * time.Millisecond
+)
+
+// Prices is what jobs cost, points are taken from balance and seconds from pro balance,
+// cost of failed or canceled jobs is refunded
+type Prices struct {
+	Image         int // points per image of CreateImage, CreateMidjourney, CreateFlux, Youthify and CreateAvatarArtwork
+	Accelerate    int // points per AccelerateImage
+	SuperSize     int // points per PostSuperSize
+	Avatar        int // points per CreateAvatar
+	SpellAnalysis int // points per CreateSpellAnalysis
+	SVD           int // points per CreateSVD
+	Lab           int // points per lab job
+	Pro           int // seconds per image of CreateImagePro
+	Camera        int // seconds per artwork of CreateCamera
+}
+
+// DefaultPrices is the prices used by NewServer, price of video is from Fixtures.VideoOptionMenu
+var DefaultPrices = Prices{
+	Image:         10,
+	Accelerate:    5,
+	SuperSize:     20,
+	Avatar:        500,
+	SpellAnalysis: 2,
+	SVD:           100,
+	Lab:           20,
+	Pro:           30,
+	Camera:        30,
+}
+
+// Fault is what Server does instead of handling a request
+type Fault struct {
+	Code    wujiesdk.WujieCode // respond with the code when it is not empty
+	Message string             // message of Code
+	Status  int                // respond with the http status when it is not 0, such as 502
+	Latency time.Duration      // delay before responding, request is handled after it when Code and Status are empty
+	Times   int                // number of requests to affect, 0 affects every request until ClearFaults
+}
+
+type fault struct {
+	Fault
+	left int
+}
+
+// Outcome decide whether a job fails when it finishes, nil means success
+type Outcome func(kind JobKind, key string, request interface{}) *wujiesdk.FailMessage
+
+// Option is option of NewServer
+type Option func(*Server)
+
+// WithTiming set how long jobs stay in queue and take to generate
+func WithTiming(queue, generate time.Duration) Option {
+	return func(s *Server) {
+		s.queue, s.generate = queue, generate
+	}
+}
+
+// WithClock set source of time of job lifecycle, such as ManualClock.Now
+func WithClock(now func() time.Time) Option {
+	return func(s *Server) {
+		s.now = now
+	}
+}
+
+// WithBalance set points and pro seconds of account
+func WithBalance(points, proSeconds int) Option {
+	return func(s *Server) {
+		s.balance, s.proBalance = points, proSeconds
+	}
+}
+
+// WithPrices set prices of jobs
+func WithPrices(prices Prices) Option {
+	return func(s *Server) {
+		s.prices = prices
+	}
+}
+
+// WithFixtures set catalog served by Server
+func WithFixtures(f *Fixtures) Option {
+	return func(s *Server) {
+		s.fixtures = f
+	}
+}
+
+// WithApp trust app with public key, Server generates credentials of DefaultAppID without it
+func WithApp(appID string, publicKey *rsa.PublicKey) Option {
+	return func(s *Server) {
+		s.apps[appID] = publicKey
+	}
+}
+
+// WithSensitiveWords reject prompts containing any of words with PromptContainsSensitiveWordsWujieCode
+func WithSensitiveWords(words ...string) Option {
+	return func(s *Server) {
+		s.sensitiveWords = append(s.sensitiveWords, words...)
+	}
+}
+
+// WithOutcome set outcome of jobs, every job succeeds without it
+func WithOutcome(outcome Outcome) Option {
+	return func(s *Server) {
+		s.outcome = outcome
+	}
+}
+
+// WithCallbackClient set http client for notify_url callbacks
+func WithCallbackClient(httpClient *http.Client) Option {
+	return func(s *Server) {
+		s.callbackClient = httpClient
+	}
+}
+
+// Server is an in-process fake of wujie's api, it implements every WujieRouter,
+// verifies Authorization, keeps balance and simulates jobs from queue to success or failure
+type Server struct {
+	URL string // base url of server, such as http://127.0.0.1:1234
+
+	ts             *httptest.Server
+	routes         map[route]handler
+	now            func() time.Time
+	queue          time.Duration
+	generate       time.Duration
+	prices         Prices
+	fixtures       *Fixtures
+	sensitiveWords []string
+	outcome        Outcome
+	callbackClient *http.Client
+	credentials    *wujiesdk.Credentials
+
+	mu         sync.Mutex
+	apps       map[string]*rsa.PublicKey
+	balance    int
+	proBalance int
+	seq        int
+	jobs       map[string]*job
+	tasks      map[string]*job // prompt optimize jobs by task id
+	faults     map[wujiesdk.WujieRouter][]*fault
+	calls      map[wujiesdk.WujieRouter]int
+	pending    []*job
+	callbacks  []Callback
+
+	deliverMu sync.Mutex
+	stop      chan struct{}
+	wg        sync.WaitGroup
+}
+
+// NewServer start a fake server, it should be closed by Close
+func NewServer(options ...Option) *Server {
+	s := &Server{
+		now:            time.Now,
+		queue:          DefaultQueueDuration,
+		generate:       DefaultGenerateDuration,
+		prices:         DefaultPrices,
+		balance:        DefaultBalance,
+		proBalance:     DefaultProBalance,
+		callbackClient: &http.Client{Timeout: 5 * time.Second},
+		apps:           map[string]*rsa.PublicKey{},
+		jobs:           map[string]*job{},
+		tasks:          map[string]*job{},
+		faults:         map[wujiesdk.WujieRouter][]*fault{},
+		calls:          map[wujiesdk.WujieRouter]int{},
+		stop:           make(chan struct{}),
+	}
+	for _, option := range options {
+		option(s)
+	}
+	if s.fixtures == nil {
+		s.fixtures = DefaultFixtures()
+	}
+	if len(s.apps) == 0 {
+		s.credentials, s.apps[DefaultAppID] = generateCredentials(DefaultAppID)
+	}
+	s.routes = s.newRoutes()
+	s.ts = httptest.NewServer(s)
+	s.URL = s.ts.URL
+	s.wg.Add(1)
+	go s.runCallbacks()
+	return s
+}
+
+func generateCredentials(appID string) (*wujiesdk.Credentials, *rsa.PublicKey) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		panic(fmt.Sprintf("wujietest: rsa.GenerateKey: %v", err))
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		panic(fmt.Sprintf("wujietest: x509.MarshalPKCS8PrivateKey: %v", err))
+	}
+	c, err := wujiesdk.NewCredentials(appID, base64.StdEncoding.EncodeToString(der))
+	if err != nil {
+		panic(fmt.Sprintf("wujietest: wujiesdk.NewCredentials: %v", err))
+	}
+	return c, &key.PublicKey
+}
+
+// Close stop callbacks and shut down server
+func (s *Server) Close() {
+	close(s.stop)
+	s.wg.Wait()
+	s.ts.Close()
+}
+
+// Credentials get credentials generated by NewServer, it is nil when WithApp is used
+func (s *Server) Credentials() *wujiesdk.Credentials {
+	return s.credentials
+}
+
+// HTTPClient get http client which sends requests of Domain to server
+func (s *Server) HTTPClient() *http.Client {
+	target, _ := url.Parse(s.URL)
+	return &http.Client{Transport: &rewriteTransport{target: target, base: s.ts.Client().Transport}}
+}
+
+// Client get client of server with credentials c, c is Credentials() when it is nil
+func (s *Server) Client(c *wujiesdk.Credentials) *wujiesdk.Client {
+	if c == nil {
+		c = s.credentials
+	}
+	return wujiesdk.NewClient(s.HTTPClient(), 3, c, wujiesdk.NewLogger(wujiesdk.LogInfo, log.New(io.Discard, "", 0)))
+}
+
+// Caller get caller of Client(nil)
+func (s *Server) Caller() *wujiesdk.Caller {
+	return wujiesdk.NewCaller(s.Client(nil))
+}
+
+// rewriteTransport send requests to target whatever host they are for
+type rewriteTransport struct {
+	target *url.URL
+	base   http.RoundTripper
+}
+
+func (r *rewriteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.URL.Scheme, req.URL.Host, req.Host = r.target.Scheme, r.target.Host, r.target.Host
+	return r.base.RoundTrip(req)
+}
+
+// Balance get points and pro seconds of account
+func (s *Server) Balance() (points, proSeconds int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.settleLocked()
+	return s.balance, s.proBalance
+}
+
+// SetBalance set points and pro seconds of account
+func (s *Server) SetBalance(points, proSeconds int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.balance, s.proBalance = points, proSeconds
+}
+
+// Inject make requests of router fail with f, AnyRouter affects every router,
+// faults of a router are used before faults of AnyRouter in the order they are injected
+func (s *Server) Inject(router wujiesdk.WujieRouter, f Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults[router] = append(s.faults[router], &fault{Fault: f, left: f.Times})
+}
+
+// ClearFaults remove every injected fault
+func (s *Server) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = map[wujiesdk.WujieRouter][]*fault{}
+}
+
+// Calls get number of requests of router, including rejected ones
+func (s *Server) Calls(router wujiesdk.WujieRouter) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.calls[router]
+}
+
+func (s *Server) takeFault(router wujiesdk.WujieRouter) *Fault {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, r := range []wujiesdk.WujieRouter{router, AnyRouter} {
+		faults := s.faults[r]
+		for i, f := range faults {
+			if f.Times > 0 {
+				f.left--
+				if f.left <= 0 {
+					s.faults[r] = append(faults[:i:i], faults[i+1:]...)
+				}
+			}
+			copied := f.Fault
+			return &copied
+		}
+	}
+	return nil
+}
+
+type route struct {
+	method string
+	router wujiesdk.WujieRouter
+}
+
+// handler handle request of a router, it returns data of response or *wujiesdk.WujieCodeError
+type handler func(r *http.Request) (interface{}, error)
+
+type response struct {
+	wujiesdk.BaseResponse
+	Data interface{} `json:"data,omitempty"`
+}
+
+// ServeHTTP serve wujie's api under /wj-open/v1 and generated assets under /assets/
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	p := strings.TrimPrefix(r.URL.Path, basePath)
+	if strings.HasPrefix(p, assetsPath) {
+		s.serveAsset(w, r, strings.TrimPrefix(p, assetsPath))
+		return
+	}
+	router := wujiesdk.WujieRouter(p)
+	h, ok := s.routes[route{r.Method, router}]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	s.mu.Lock()
+	s.calls[router]++
+	s.seq++
+	traceID := fmt.Sprintf("wujietest-%d", s.seq)
+	s.mu.Unlock()
+	w.Header().Set(wujiesdk.TraceID, traceID)
+
+	if f := s.takeFault(router); f != nil {
+		if f.Latency > 0 {
+			if sleepCtx(r.Context(), f.Latency) != nil {
+				return
+			}
+		}
+		if f.Status != 0 {
+			http.Error(w, http.StatusText(f.Status), f.Status)
+			return
+		}
+		if f.Code != "" {
+			writeResponse(w, http.StatusOK, f.Code, f.Message, nil)
+			return
+		}
+	}
+	if err := s.verify(r); err != nil {
+		writeResponse(w, http.StatusUnauthorized, UnauthorizedWujieCode, err.Error(), nil)
+		return
+	}
+	data, err := h(r)
+	if err != nil {
+		var wErr *wujiesdk.WujieCodeError
+		if !errors.As(err, &wErr) {
+			wErr = &wujiesdk.WujieCodeError{Code: wujiesdk.InvalidParameterWujieCode, Message: err.Error()}
+		}
+		writeResponse(w, http.StatusOK, wErr.Code, wErr.Message, nil)
+		return
+	}
+	writeResponse(w, http.StatusOK, wujiesdk.OKWujieCode, "success", data)
+}
+
+func writeResponse(w http.ResponseWriter, status int, code wujiesdk.WujieCode, message string, data interface{}) {
+	w.Header().Set(wujiesdk.ContentType, wujiesdk.ApplicationJson)
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(&response{
+		BaseResponse: wujiesdk.BaseResponse{Code: string(code), Message: message, Success: code == wujiesdk.OKWujieCode},
+		Data:         data,
+	})
+}
+
+// verify check Authorization signed by Credentials
+func (s *Server) verify(r *http.Request) error {
+	header := r.Header.Get(wujiesdk.HTTPHeaderAuthorization)
+	if header == "" {
+		return fmt.Errorf("missing %s", wujiesdk.HTTPHeaderAuthorization)
+	}
+	var auth struct {
+		Sign             string `json:"sign"`
+		SecretKeyVersion string `json:"secretKeyVersion"`
+		AppID            string `json:"appId"`
+		Original         string `json:"original"`
+	}
+	if err := json.Unmarshal([]byte(header), &auth); err != nil {
+		return fmt.Errorf("invalid %s: %v", wujiesdk.HTTPHeaderAuthorization, err)
+	}
+	s.mu.Lock()
+	publicKey, ok := s.apps[auth.AppID]
+	s.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("unknown appId: %s", auth.AppID)
+	}
+	var original struct {
+		AppID     string `json:"appId"`
+		Timestamp int64  `json:"timestamp"`
+	}
+	if err := json.Unmarshal([]byte(auth.Original), &original); err != nil {
+		return fmt.Errorf("invalid original: %v", err)
+	}
+	if original.AppID != auth.AppID {
+		return fmt.Errorf("appId of original: %s, appId: %s, mismatch", original.AppID, auth.AppID)
+	}
+	if skew := time.Since(time.Unix(original.Timestamp, 0)); skew > MaxSignSkew || skew < -MaxSignSkew {
+		return fmt.Errorf("timestamp: %d, expired", original.Timestamp)
+	}
+	sign, err := base64.StdEncoding.DecodeString(auth.Sign)
+	if err != nil {
+		return fmt.Errorf("invalid sign: %v", err)
+	}
+	digest := sha256.Sum256([]byte(auth.Original))
+	if err := rsa.VerifyPKCS1v15(publicKey, crypto.SHA256, digest[:], sign); err != nil {
+		return fmt.Errorf("invalid sign: %v", err)
+	}
+	return nil
+}
+
+// ManualClock is a clock which only moves by Advance, it is used by WithClock
+type ManualClock struct {
+	mu sync.Mutex
+	t  time.Time
+}
+
+// NewManualClock new manual clock at t
+func NewManualClock(t time.Time) *ManualClock {
+	return &ManualClock{t: t}
+}
+
+// Now get time of clock
+func (m *ManualClock) Now() time.Time {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.t
+}
+
+// Advance move clock forward by d
+func (m *ManualClock) Advance(d time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.t = m.t.Add(d)
+}
+
+func codeErr(code wujiesdk.WujieCode, format string, a ...interface{}) error {
+	return &wujiesdk.WujieCodeError{Code: code, Message: fmt.Sprintf(format, a...)}
+}
+
+func invalidParameter(format string, a ...interface{}) error {
+	return codeErr(wujiesdk.InvalidParameterWujieCode, format, a...)
+}
+
+func decodeBody(r *http.Request, v interface{}) error {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		return invalidParameter("invalid body: %v", err)
+	}
+	return nil
+}
+
+func queryInt(r *http.Request, name string) (int, error) {
+	v, err := strconv.Atoi(r.URL.Query().Get(name))
+	if err != nil {
+		return 0, invalidParameter("invalid %s: %v", name, err)
+	}
+	return v, nil
+}
+
+func sleepCtx(ctx context.Context, d time.Duration) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(d):
+		return nil
+	}
+}